@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
+
 	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra"
+	"github.com/gofiber/fiber/v2"
 )
 
 func main() {
@@ -10,19 +13,34 @@ func main() {
 	viperConfig := infra.NewViper()
 	log := infra.NewLogger(viperConfig)
 	db := infra.NewDatabase(viperConfig, log)
-	validate := infra.NewValidator(viperConfig)
-	app := infra.NewFiber(viperConfig)
+	validate := infra.NewValidator(viperConfig, log)
+	app := infra.NewFiber(viperConfig, log)
+	mail := infra.NewMailer(viperConfig, log)
+	keys := infra.NewKeySet(viperConfig, log)
+	hasher := infra.NewPasswordHasher(viperConfig, log)
+	policy := infra.NewPasswordPolicy(viperConfig, log)
 
 	// 2. Bootstrap Application (Wiring semua module di sini)
-	infra.Bootstrap(&infra.BootstrapConfig{
+	workers := infra.Bootstrap(&infra.BootstrapConfig{
 		DB:       db,
 		App:      app,
 		Log:      log,
 		Validate: validate,
 		Config:   viperConfig,
+		Mailer:   mail,
+		Keys:     keys,
+		Hasher:   hasher,
+		Policy:   policy,
 	})
 
-	// 3. Start Server
+	// 3. Start Background Workers (sekali saja, bukan di setiap child process prefork)
+	if !fiber.IsChild() {
+		for _, worker := range workers {
+			go worker.Run(context.Background())
+		}
+	}
+
+	// 4. Start Server
 	webPort := viperConfig.GetInt("web.port")
 	log.Infof("Server starting at port %d", webPort)
 	err := app.Listen(fmt.Sprintf(":%d", webPort))
@@ -0,0 +1,24 @@
+-- Table: Refresh Tokens
+-- Token disimpan dalam bentuk hash (SHA-256), tidak pernah plaintext.
+-- family_id mengelompokkan seluruh hasil rotasi dari satu kali login,
+-- sehingga saat token lama dipakai ulang seluruh family bisa dicabut.
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    family_id UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    replaced_by UUID,
+
+    CONSTRAINT refresh_tokens_token_hash_unique UNIQUE (token_hash),
+    CONSTRAINT fk_refresh_tokens_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- Index untuk revoke per family dan per user
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_id);
@@ -0,0 +1,26 @@
+-- Table: Revoked Tokens
+-- Access token (jti) yang dicabut sebelum exp, misal karena logout.
+-- Baris boleh dibersihkan setelah expires_at lewat karena token sudah tidak valid.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    jti UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_revoked_tokens_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires ON revoked_tokens(expires_at);
+
+-- Table: User Token Cutoffs
+-- "Logout dari semua device": semua token user dengan iat sebelum revoked_before ditolak.
+CREATE TABLE IF NOT EXISTS user_token_cutoffs (
+    user_id UUID PRIMARY KEY,
+    revoked_before TIMESTAMP WITH TIME ZONE NOT NULL,
+    CONSTRAINT fk_user_token_cutoffs_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
@@ -0,0 +1,20 @@
+-- Table: Action Tokens
+-- Token sekali pakai yang dikirim lewat email (reset password, dll).
+-- Hanya hash (SHA-256) yang disimpan; used_at terisi saat token dipakai.
+CREATE TABLE IF NOT EXISTS action_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    purpose VARCHAR(32) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT action_tokens_token_hash_unique UNIQUE (token_hash),
+    CONSTRAINT fk_action_tokens_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_action_tokens_user_purpose ON action_tokens(user_id, purpose);
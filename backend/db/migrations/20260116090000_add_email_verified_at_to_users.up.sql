@@ -0,0 +1,5 @@
+-- Email verification: NULL berarti email belum diverifikasi
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Akun yang sudah ada sebelum verifikasi diwajibkan dianggap terverifikasi, agar tidak terkunci dari login
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
@@ -0,0 +1,13 @@
+-- Soft delete: username & email boleh dipakai ulang setelah akun dihapus,
+-- jadi unique constraint diganti dengan partial unique index (hanya baris aktif).
+-- Nama index tetap mengandung "email" / "username" agar mapping error di repository tetap jalan.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_unique;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_username_unique;
+DROP INDEX IF EXISTS idx_users_email;
+DROP INDEX IF EXISTS idx_users_username;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_unique ON users(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_unique ON users(username) WHERE deleted_at IS NULL;
+
+-- Index untuk purge akun yang sudah melewati masa retensi
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,9 @@
+-- Table: Login Attempts
+-- Penghitung gagal login per key ("user:<id>", "email:<email>" / "username:<username>" untuk akun tidak dikenal, "ip:<alamat>", "mfa:<id>").
+-- Counter di-reset otomatis jika gagal terakhir dan akhir lockout terakhir sudah lewat dari window.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    locked_until TIMESTAMP WITH TIME ZONE
+);
@@ -0,0 +1,22 @@
+-- Table: API Tokens (personal access token untuk script / integrasi)
+-- Hanya hash (SHA-256) yang disimpan; prefix disimpan untuk membantu user mengenali token.
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    scope VARCHAR(16) NOT NULL DEFAULT 'full',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT api_tokens_token_hash_unique UNIQUE (token_hash),
+    CONSTRAINT fk_api_tokens_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user ON api_tokens(user_id);
@@ -0,0 +1,18 @@
+-- Table: Sessions (satu baris per login / device)
+-- id sama dengan family_id refresh token dan claim "sid" di access token.
+CREATE TABLE IF NOT EXISTS sessions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    terminated_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT fk_sessions_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_active ON sessions(user_id) WHERE terminated_at IS NULL;
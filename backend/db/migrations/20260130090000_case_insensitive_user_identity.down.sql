@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_username_unique;
+DROP INDEX IF EXISTS users_email_unique;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_unique ON users(email) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_unique ON users(username) WHERE deleted_at IS NULL;
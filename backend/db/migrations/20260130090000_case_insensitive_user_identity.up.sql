@@ -0,0 +1,8 @@
+-- Email & username tidak membedakan huruf besar/kecil: "Foo@x.com" dan "foo@x.com" adalah akun yang sama.
+-- Unique index diganti ke lower(...), repository membandingkan lower(kolom) = lower($1) sehingga index tetap terpakai.
+-- Migrasi ini gagal jika sudah ada akun aktif yang hanya berbeda huruf besar/kecil; bereskan datanya dulu.
+DROP INDEX IF EXISTS users_email_unique;
+DROP INDEX IF EXISTS users_username_unique;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_unique ON users(lower(email)) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS users_username_unique ON users(lower(username)) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_histories_category;
+ALTER TABLE histories DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
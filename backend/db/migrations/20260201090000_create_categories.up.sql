@@ -0,0 +1,41 @@
+-- Table: Categories (kategori pengeluaran)
+-- Kategori default (is_default) dibuat per user saat registrasi dan tidak bisa dihapus; sisanya dibuat user sendiri.
+CREATE TABLE IF NOT EXISTS categories (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7),
+    icon VARCHAR(50),
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_categories_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- Nama kategori unik per user, tanpa membedakan huruf besar/kecil
+CREATE UNIQUE INDEX IF NOT EXISTS categories_user_name_unique ON categories(user_id, lower(name));
+
+-- Pengeluaran boleh tanpa kategori; kategori yang dihapus membuat history-nya kembali tanpa kategori
+ALTER TABLE histories ADD COLUMN IF NOT EXISTS category_id UUID REFERENCES categories(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_histories_category ON histories(category_id);
+
+-- Kategori default untuk user yang sudah ada (harus sama dengan category.DefaultCategories)
+INSERT INTO categories (id, user_id, name, color, icon, is_default)
+SELECT uuid_generate_v4(), u.id, d.name, d.color, d.icon, TRUE
+FROM users u
+CROSS JOIN (VALUES
+    ('Food & Drinks', '#F97316', 'utensils'),
+    ('Groceries', '#84CC16', 'shopping-basket'),
+    ('Transportation', '#0EA5E9', 'car'),
+    ('Housing', '#8B5CF6', 'home'),
+    ('Bills & Utilities', '#EAB308', 'receipt'),
+    ('Health', '#EF4444', 'heart-pulse'),
+    ('Entertainment', '#EC4899', 'film'),
+    ('Shopping', '#14B8A6', 'shopping-bag'),
+    ('Education', '#6366F1', 'graduation-cap'),
+    ('Others', '#64748B', 'circle-ellipsis')
+) AS d(name, color, icon)
+ON CONFLICT DO NOTHING;
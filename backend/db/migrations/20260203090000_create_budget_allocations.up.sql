@@ -0,0 +1,24 @@
+-- Table: Budget Allocations (envelope per kategori di dalam satu budget bulanan)
+-- Total amount per budget tidak boleh melebihi monthly_budgets.budget (dicek di aplikasi dengan mengunci baris budget).
+CREATE TABLE IF NOT EXISTS budget_allocations (
+    budget_id UUID NOT NULL,
+    category_id UUID NOT NULL,
+    amount NUMERIC(15, 2) NOT NULL CHECK (amount >= 0),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (budget_id, category_id),
+
+    CONSTRAINT fk_allocations_budget
+    FOREIGN KEY(budget_id)
+    REFERENCES monthly_budgets(id)
+    ON DELETE CASCADE,
+
+    -- Kategori dihapus => jatahnya kembali menjadi unallocated
+    CONSTRAINT fk_allocations_category
+    FOREIGN KEY(category_id)
+    REFERENCES categories(id)
+    ON DELETE CASCADE
+);
+
+-- Index untuk cascade saat kategori dihapus
+CREATE INDEX IF NOT EXISTS idx_budget_allocations_category ON budget_allocations(category_id);
@@ -0,0 +1,6 @@
+ALTER TABLE monthly_budgets DROP COLUMN IF EXISTS closed_at;
+ALTER TABLE monthly_budgets DROP COLUMN IF EXISTS previous_budget_id;
+ALTER TABLE monthly_budgets DROP COLUMN IF EXISTS carried_over;
+ALTER TABLE monthly_budgets DROP COLUMN IF EXISTS rollover_cap;
+ALTER TABLE monthly_budgets DROP COLUMN IF EXISTS rollover_deficit;
+ALTER TABLE monthly_budgets DROP COLUMN IF EXISTS rollover_surplus;
@@ -0,0 +1,10 @@
+-- Rollover: sisa / kekurangan budget dibawa ke bulan berikutnya saat bulan ditutup
+-- rollover_cap membatasi nilai absolut carry-over (NULL = tanpa batas)
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS rollover_surplus BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS rollover_deficit BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS rollover_cap NUMERIC(15, 2) CHECK (rollover_cap >= 0);
+
+-- carried_over disimpan terpisah dari budget dasar supaya tetap terlihat asalnya
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS carried_over NUMERIC(15, 2) NOT NULL DEFAULT 0;
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS previous_budget_id UUID REFERENCES monthly_budgets(id) ON DELETE SET NULL;
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS histories_recurring_occurrence;
+ALTER TABLE histories DROP COLUMN IF EXISTS recurring_id;
+DROP TABLE IF EXISTS recurring_transactions;
@@ -0,0 +1,33 @@
+-- Table: Recurring Transactions (template sewa, langganan, cicilan)
+-- rrule memakai subset RRULE (lihat recurring.Rule); next_run_at NULL berarti jadwal sudah selesai
+CREATE TABLE IF NOT EXISTS recurring_transactions (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    category_id UUID,
+    name VARCHAR(100) NOT NULL,
+    amount NUMERIC(15, 2) NOT NULL,
+    rrule VARCHAR(200) NOT NULL,
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE,
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_recurring_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE,
+
+    CONSTRAINT fk_recurring_category
+    FOREIGN KEY(category_id)
+    REFERENCES categories(id)
+    ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_user ON recurring_transactions(user_id);
+-- Index untuk scheduler: template aktif yang sudah jatuh tempo
+CREATE INDEX IF NOT EXISTS idx_recurring_due ON recurring_transactions(next_run_at) WHERE active;
+
+-- History hasil recurring; satu kejadian (recurring_id, date) hanya boleh dibuat sekali
+ALTER TABLE histories ADD COLUMN IF NOT EXISTS recurring_id UUID REFERENCES recurring_transactions(id) ON DELETE SET NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS histories_recurring_occurrence ON histories(recurring_id, date) WHERE recurring_id IS NOT NULL;
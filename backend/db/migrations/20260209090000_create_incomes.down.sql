@@ -0,0 +1,2 @@
+ALTER TABLE monthly_budgets DROP COLUMN IF EXISTS income_percent;
+DROP TABLE IF EXISTS incomes;
@@ -0,0 +1,22 @@
+-- Table: Incomes (pemasukan: gaji, bonus, freelance, ...)
+-- source dibatasi ke daftar income.Sources
+CREATE TABLE IF NOT EXISTS incomes (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    description VARCHAR(255),
+    amount NUMERIC(15, 2) NOT NULL CHECK (amount > 0),
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_incomes_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- Index untuk list & ringkasan bulanan per user
+CREATE INDEX IF NOT EXISTS idx_incomes_user_date ON incomes(user_id, date);
+
+-- Budget bisa dihitung dari persentase pemasukan bulan yang sama (NULL = budget tetap)
+ALTER TABLE monthly_budgets ADD COLUMN IF NOT EXISTS income_percent NUMERIC(5, 2) CHECK (income_percent > 0 AND income_percent <= 100);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS account_transfers;
+ALTER TABLE recurring_transactions DROP COLUMN IF EXISTS account_id;
+ALTER TABLE incomes DROP COLUMN IF EXISTS account_id;
+ALTER TABLE histories DROP COLUMN IF EXISTS account_id;
+DROP TABLE IF EXISTS accounts;
@@ -0,0 +1,71 @@
+-- Table: Accounts (rekening bank, cash, e-wallet, kartu kredit)
+-- Saldo tidak disimpan: opening_balance + pemasukan - pengeluaran +/- transfer (lihat account.Repository)
+CREATE TABLE IF NOT EXISTS accounts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('bank', 'cash', 'e_wallet', 'credit_card')),
+    opening_balance NUMERIC(15, 2) NOT NULL DEFAULT 0,
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT fk_accounts_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+-- Nama akun unik per user (case-insensitive); tepat satu akun default per user
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_user_name_unique ON accounts(user_id, lower(name));
+CREATE UNIQUE INDEX IF NOT EXISTS accounts_user_default ON accounts(user_id) WHERE is_default;
+
+-- Akun default (harus sama dengan account.DefaultAccountName) untuk user yang sudah ada
+INSERT INTO accounts (id, user_id, name, type, opening_balance, is_default)
+SELECT uuid_generate_v4(), u.id, 'Cash', 'cash', 0, TRUE
+FROM users u
+ON CONFLICT DO NOTHING;
+
+-- Setiap transaksi terhubung ke satu akun; transaksi lama dipindahkan ke akun default.
+-- FK tanpa ON DELETE (NO ACTION): akun yang masih punya transaksi tidak bisa dihapus.
+ALTER TABLE histories ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES accounts(id);
+UPDATE histories h
+SET account_id = a.id
+FROM monthly_budgets b
+JOIN accounts a ON a.user_id = b.user_id AND a.is_default
+WHERE b.id = h.budget_id AND h.account_id IS NULL;
+ALTER TABLE histories ALTER COLUMN account_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_histories_account ON histories(account_id);
+
+ALTER TABLE incomes ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES accounts(id);
+UPDATE incomes i
+SET account_id = a.id
+FROM accounts a
+WHERE a.user_id = i.user_id AND a.is_default AND i.account_id IS NULL;
+ALTER TABLE incomes ALTER COLUMN account_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_incomes_account ON incomes(account_id);
+
+-- Template recurring tanpa akun memakai akun default saat dibuat menjadi history
+ALTER TABLE recurring_transactions ADD COLUMN IF NOT EXISTS account_id UUID REFERENCES accounts(id) ON DELETE SET NULL;
+
+-- Table: Account Transfers (pindah dana antar akun, bukan pengeluaran)
+CREATE TABLE IF NOT EXISTS account_transfers (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    from_account_id UUID NOT NULL REFERENCES accounts(id),
+    to_account_id UUID NOT NULL REFERENCES accounts(id),
+    amount NUMERIC(15, 2) NOT NULL CHECK (amount > 0),
+    date TIMESTAMP WITH TIME ZONE NOT NULL,
+    note VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+
+    CONSTRAINT account_transfers_distinct CHECK (from_account_id <> to_account_id),
+
+    CONSTRAINT fk_account_transfers_user
+    FOREIGN KEY(user_id)
+    REFERENCES users(id)
+    ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_transfers_user_date ON account_transfers(user_id, date);
+CREATE INDEX IF NOT EXISTS idx_account_transfers_from ON account_transfers(from_account_id);
+CREATE INDEX IF NOT EXISTS idx_account_transfers_to ON account_transfers(to_account_id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS monthly_budgets_user_month_unique;
@@ -0,0 +1,4 @@
+-- Satu budget per user per bulan (UTC, sama dengan budget.Repository.FindByMonth).
+-- Budget ganda di bulan yang sama harus digabung manual dulu, selain itu migration ini gagal.
+CREATE UNIQUE INDEX IF NOT EXISTS monthly_budgets_user_month_unique
+    ON monthly_budgets(user_id, date_trunc('month', date AT TIME ZONE 'UTC'));
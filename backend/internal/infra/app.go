@@ -1,7 +1,19 @@
 package infra
 
 import (
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/jwtkey"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/mailer"
 	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/password"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/account"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/apitoken"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/category"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/history"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/income"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/recurring"
 	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/user" // Import module User
 
 	"github.com/go-playground/validator/v10"
@@ -17,15 +29,103 @@ type BootstrapConfig struct {
 	Log      *logrus.Logger
 	Validate *validator.Validate
 	Config   *viper.Viper
+	Mailer   mailer.Mailer
+	Keys     *jwtkey.KeySet
+	Hasher   *password.Hasher
+	Policy   *password.Policy
 }
 
-func Bootstrap(config *BootstrapConfig) {
+// Bootstrap me-wiring semua module dan mengembalikan background worker yang harus dijalankan
+func Bootstrap(config *BootstrapConfig) []*Worker {
+
+	categoryRepo := category.NewRepository(config.DB)
+	categoryUseCase := category.NewUseCase(categoryRepo, config.Log, config.Validate)
+	categoryHandler := category.NewHandler(categoryUseCase)
 
+	apiTokenRepo := apitoken.NewRepository(config.DB)
 	userRepo := user.NewRepository(config.DB)
-	userUseCase := user.NewUseCase(userRepo, config.Log, config.Validate, config.Config)
+	userTokenRepo := user.NewTokenRepository(config.DB)
+	revocationStore := user.NewRevocationStore(config.DB, revocationCacheTTL(config.Config))
+	sessionStore := user.NewSessionStore(config.DB, revocationCacheTTL(config.Config))
+	userAttemptRepo := user.NewAttemptRepository(config.DB)
+	userUseCase := user.NewUseCase(userRepo, userTokenRepo, revocationStore, sessionStore, apiTokenRepo, config.Mailer, userAttemptRepo, categoryUseCase, config.Keys, config.Hasher, config.Policy, config.Log, config.Validate, config.Config)
 	userHandler := user.NewHandler(userUseCase)
 
-	authMiddleware := middleware.AuthMiddleware(config.Config)
+	auditRepo := user.NewAuditRepository(config.DB)
+	adminUseCase := user.NewAdminUseCase(userRepo, userTokenRepo, revocationStore, sessionStore, apiTokenRepo, config.Mailer, auditRepo, config.Log, config.Validate, config.Config)
+	adminHandler := user.NewAdminHandler(adminUseCase)
+
+	apiTokenUseCase := apitoken.NewUseCase(apiTokenRepo, config.Log, config.Validate)
+	apiTokenHandler := apitoken.NewHandler(apiTokenUseCase)
+
+	accountRepo := account.NewRepository(config.DB)
+	accountUseCase := account.NewUseCase(accountRepo, config.Log, config.Validate)
+	accountHandler := account.NewHandler(accountUseCase)
+
+	incomeRepo := income.NewRepository(config.DB)
+
+	budgetRepo := budget.NewRepository(config.DB)
+	budgetUseCase := budget.NewUseCase(budgetRepo, categoryRepo, incomeRepo, config.Log, config.Validate)
+	budgetHandler := budget.NewHandler(budgetUseCase)
+
+	incomeUseCase := income.NewUseCase(incomeRepo, budgetRepo, accountRepo, budgetUseCase, config.Log, config.Validate)
+	incomeHandler := income.NewHandler(incomeUseCase)
+
+	historyRepo := history.NewRepository(config.DB)
+	historyUseCase := history.NewUseCase(historyRepo, budgetRepo, categoryRepo, accountRepo, config.Log, config.Validate)
+	historyHandler := history.NewHandler(historyUseCase)
+
+	recurringRepo := recurring.NewRepository(config.DB)
+	recurringUseCase := recurring.NewUseCase(recurringRepo, budgetRepo, categoryRepo, accountRepo, config.Log, config.Validate)
+	recurringHandler := recurring.NewHandler(recurringUseCase)
 
+	userStatusStore := user.NewStatusStore(config.DB, revocationCacheTTL(config.Config))
+	authMiddleware := middleware.AuthMiddleware(config.Keys, revocationStore, sessionStore, apiTokenUseCase, userStatusStore, authOptions(config.Config))
+
+	jwtkey.NewHandler(config.Keys).RegisterRoutes(config.App)
 	userHandler.RegisterRoutes(config.App, authMiddleware)
+	adminHandler.RegisterRoutes(config.App, authMiddleware, middleware.RequireRole(user.RoleAdmin))
+	apiTokenHandler.RegisterRoutes(config.App, authMiddleware)
+	categoryHandler.RegisterRoutes(config.App, authMiddleware)
+	accountHandler.RegisterRoutes(config.App, authMiddleware)
+	budgetHandler.RegisterRoutes(config.App, authMiddleware)
+	incomeHandler.RegisterRoutes(config.App, authMiddleware)
+	historyHandler.RegisterRoutes(config.App, authMiddleware)
+	recurringHandler.RegisterRoutes(config.App, authMiddleware)
+
+	return []*Worker{
+		NewWorker("purge-deleted-accounts", time.Hour, userUseCase.PurgeDeletedAccounts, config.Log),
+		NewWorker("materialise-recurring", recurringInterval(config.Config), recurringUseCase.MaterialiseDue, config.Log),
+	}
+}
+
+// recurringInterval: seberapa sering scheduler recurring memeriksa kejadian yang jatuh tempo
+func recurringInterval(cfg *viper.Viper) time.Duration {
+	interval := cfg.GetDuration("recurring.interval")
+	if interval == 0 {
+		interval = 15 * time.Minute // Default value
+	}
+	return interval
+}
+
+func revocationCacheTTL(cfg *viper.Viper) time.Duration {
+	ttl := cfg.GetDuration("jwt.revocation_cache_ttl")
+	if ttl == 0 {
+		ttl = 30 * time.Second // Default value
+	}
+	return ttl
+}
+
+// authOptions: iss/aud harus sama dengan yang dipakai module user saat sign access token
+func authOptions(cfg *viper.Viper) middleware.AuthOptions {
+	leeway := cfg.GetDuration("jwt.leeway")
+	if leeway == 0 {
+		leeway = 30 * time.Second // Default value
+	}
+	return middleware.AuthOptions{
+		Issuer:         cfg.GetString("jwt.issuer"),
+		Audience:       cfg.GetString("jwt.audience"),
+		Leeway:         leeway,
+		APITokenPrefix: apitoken.TokenPrefix,
+	}
 }
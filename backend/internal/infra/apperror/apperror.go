@@ -0,0 +1,171 @@
+package apperror
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Kind: Kategori error, menentukan HTTP status response
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindTooManyRequests
+)
+
+var kindStatus = map[Kind]int{
+	KindInternal:        fiber.StatusInternalServerError,
+	KindBadRequest:      fiber.StatusBadRequest,
+	KindUnauthorized:    fiber.StatusUnauthorized,
+	KindForbidden:       fiber.StatusForbidden,
+	KindNotFound:        fiber.StatusNotFound,
+	KindConflict:        fiber.StatusConflict,
+	KindTooManyRequests: fiber.StatusTooManyRequests,
+}
+
+// Error: Error domain yang aman dikirim ke client.
+// Code stabil (snake_case) untuk dicek client, Message untuk dibaca manusia.
+type Error struct {
+	Kind       Kind
+	Code       string
+	Message    string
+	RetryAfter time.Duration // hanya dipakai KindTooManyRequests
+
+	status int // override status, lihat FromStatus
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Status: HTTP status untuk Kind error ini
+func (e *Error) Status() int {
+	if e.status != 0 {
+		return e.status
+	}
+	if status, ok := kindStatus[e.Kind]; ok {
+		return status
+	}
+	return fiber.StatusInternalServerError
+}
+
+// Coder: Error dengan data dinamis (mis. RetryAfter) yang bisa diubah menjadi *Error
+type Coder interface {
+	AppError() *Error
+}
+
+func New(kind Kind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func BadRequest(code string, message string) *Error {
+	return New(KindBadRequest, code, message)
+}
+
+func Unauthorized(code string, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code string, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code string, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code string, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func TooManyRequests(code string, message string, retryAfter time.Duration) *Error {
+	e := New(KindTooManyRequests, code, message)
+	e.RetryAfter = retryAfter
+	return e
+}
+
+func Internal(code string, message string) *Error {
+	return New(KindInternal, code, message)
+}
+
+// FromStatus membuat *Error dari HTTP status apa saja (mis. error bawaan Fiber 405 / 413).
+// Code diturunkan dari status text: 405 => "method_not_allowed".
+func FromStatus(status int, message string) *Error {
+	kind := KindInternal
+	for k, s := range kindStatus {
+		if s == status {
+			kind = k
+		}
+	}
+	code := strings.ToLower(strings.ReplaceAll(http.StatusText(status), " ", "_"))
+	if code == "" {
+		code = "http_error"
+	}
+	return &Error{Kind: kind, Code: code, Message: message, status: status}
+}
+
+// Error umum yang dipakai lintas module
+var (
+	ErrInternal         = Internal("internal_error", "internal server error")
+	ErrInvalidBody      = BadRequest("invalid_body", "invalid request body")
+	ErrInvalidQuery     = BadRequest("invalid_query", "invalid query parameters")
+	ErrValidationFailed = BadRequest("validation_failed", "validation failed")
+	ErrUnauthorized     = Unauthorized("unauthorized", "unauthorized")
+	ErrForbidden        = Forbidden("forbidden", "forbidden")
+)
+
+// From mencari *Error (atau Coder) di rantai err. nil jika err bukan error aplikasi.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.AppError()
+	}
+	return nil
+}
+
+// ContentType: Media type RFC 7807
+const ContentType = "application/problem+json"
+
+// Problem: Body response error sesuai RFC 7807, ditambah code dan list error validasi
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	Errors   any    `json:"errors,omitempty"`
+}
+
+// Write mengirim e sebagai application/problem+json. fieldErrors opsional (error validasi).
+func Write(c *fiber.Ctx, e *Error, fieldErrors any) error {
+	status := e.Status()
+	if e.RetryAfter > 0 {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
+
+	return c.Status(status).JSON(Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   e.Message,
+		Instance: c.Path(),
+		Code:     e.Code,
+		Errors:   fieldErrors,
+	}, ContentType)
+}
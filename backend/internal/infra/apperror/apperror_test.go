@@ -0,0 +1,116 @@
+package apperror_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================================
+// 1. HELPER SETUP
+// ==========================================
+
+type retryError struct {
+	after time.Duration
+}
+
+func (e *retryError) Error() string { return "slow down" }
+
+func (e *retryError) AppError() *apperror.Error {
+	return apperror.TooManyRequests("slow_down", e.Error(), e.after)
+}
+
+// write menjalankan apperror.Write di dalam request Fiber sungguhan
+func write(t *testing.T, e *apperror.Error, fieldErrors any) (*http.Response, apperror.Problem) {
+	app := fiber.New()
+	app.Get("/api/things", func(c *fiber.Ctx) error {
+		return apperror.Write(c, e, fieldErrors)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/things?page=2", nil))
+	require.NoError(t, err)
+
+	var problem apperror.Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&problem))
+	return resp, problem
+}
+
+// ==========================================
+// 2. GROUP: ERROR TESTS
+// ==========================================
+
+func TestStatus_FollowsKind(t *testing.T) {
+	assert.Equal(t, fiber.StatusBadRequest, apperror.BadRequest("x", "x").Status())
+	assert.Equal(t, fiber.StatusUnauthorized, apperror.Unauthorized("x", "x").Status())
+	assert.Equal(t, fiber.StatusForbidden, apperror.Forbidden("x", "x").Status())
+	assert.Equal(t, fiber.StatusNotFound, apperror.NotFound("x", "x").Status())
+	assert.Equal(t, fiber.StatusConflict, apperror.Conflict("x", "x").Status())
+	assert.Equal(t, fiber.StatusTooManyRequests, apperror.TooManyRequests("x", "x", time.Second).Status())
+	assert.Equal(t, fiber.StatusInternalServerError, apperror.Internal("x", "x").Status())
+}
+
+func TestFrom_WrappedError(t *testing.T) {
+	notFound := apperror.NotFound("thing_not_found", "thing not found")
+
+	assert.Same(t, notFound, apperror.From(fmt.Errorf("load thing: %w", notFound)))
+	assert.Nil(t, apperror.From(errors.New("boom")))
+}
+
+func TestFrom_Coder(t *testing.T) {
+	appErr := apperror.From(&retryError{after: 90 * time.Second})
+
+	require.NotNil(t, appErr)
+	assert.Equal(t, "slow_down", appErr.Code)
+	assert.Equal(t, 90*time.Second, appErr.RetryAfter)
+}
+
+func TestFromStatus_DerivesCode(t *testing.T) {
+	appErr := apperror.FromStatus(fiber.StatusMethodNotAllowed, "Method Not Allowed")
+
+	assert.Equal(t, fiber.StatusMethodNotAllowed, appErr.Status())
+	assert.Equal(t, "method_not_allowed", appErr.Code)
+	assert.Equal(t, apperror.KindNotFound, apperror.FromStatus(fiber.StatusNotFound, "Cannot GET /x").Kind)
+}
+
+// ==========================================
+// 3. GROUP: PROBLEM RESPONSE TESTS
+// ==========================================
+
+func TestWrite_ProblemJSON(t *testing.T) {
+	resp, problem := write(t, apperror.NotFound("thing_not_found", "thing not found"), nil)
+
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, apperror.ContentType, resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(t, apperror.Problem{
+		Type:     "about:blank",
+		Title:    "Not Found",
+		Status:   fiber.StatusNotFound,
+		Detail:   "thing not found",
+		Instance: "/api/things",
+		Code:     "thing_not_found",
+	}, problem)
+}
+
+func TestWrite_RetryAfterRoundedUp(t *testing.T) {
+	resp, problem := write(t, apperror.TooManyRequests("too_many_attempts", "try again later", 1500*time.Millisecond), nil)
+
+	assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get(fiber.HeaderRetryAfter))
+	assert.Equal(t, "too_many_attempts", problem.Code)
+}
+
+func TestWrite_FieldErrors(t *testing.T) {
+	_, problem := write(t, apperror.ErrValidationFailed, []map[string]string{{"field": "email", "rule": "required"}})
+
+	assert.Equal(t, "validation_failed", problem.Code)
+	assert.Equal(t, []any{map[string]any{"field": "email", "rule": "required"}}, problem.Errors)
+}
@@ -0,0 +1,13 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Execer: dipenuhi *pgxpool.Pool maupun pgx.Tx, untuk query yang dipakai di dalam
+// maupun di luar transaksi (termasuk transaksi milik module lain)
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
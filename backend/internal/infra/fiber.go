@@ -1,29 +1,50 @@
 package infra
 
 import (
+	"errors"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/validation"
 	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-func NewFiber(config *viper.Viper) *fiber.App {
+func NewFiber(config *viper.Viper, log *logrus.Logger) *fiber.App {
 	var app = fiber.New(fiber.Config{
 		AppName:      config.GetString("app.name"),
-		ErrorHandler: NewErrorHandler(),
+		ErrorHandler: NewErrorHandler(log),
 		Prefork:      config.GetBool("web.prefork"),
+		// Isi (misal "X-Forwarded-For") jika berjalan di belakang reverse proxy, agar c.IP() akurat
+		ProxyHeader: config.GetString("web.proxy_header"),
 	})
 
 	return app
 }
 
-func NewErrorHandler() fiber.ErrorHandler {
+// NewErrorHandler: Satu-satunya tempat error diubah menjadi response (application/problem+json).
+// Handler & middleware cukup "return err".
+func NewErrorHandler(log *logrus.Logger) fiber.ErrorHandler {
 	return func(ctx *fiber.Ctx, err error) error {
-		code := fiber.StatusInternalServerError
-		if e, ok := err.(*fiber.Error); ok {
-			code = e.Code
+		// 1. Error validasi input (400 + list field)
+		if fields, ok := validation.Errors(ctx, err); ok {
+			return apperror.Write(ctx, apperror.ErrValidationFailed, fields)
+		}
+
+		// 2. Error domain dari module
+		// (ErrInternal sudah di-log oleh usecase beserta penyebab aslinya)
+		if appErr := apperror.From(err); appErr != nil {
+			return apperror.Write(ctx, appErr, nil)
+		}
+
+		// 3. Error bawaan Fiber (route tidak ada, method salah, body terlalu besar, ...)
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			return apperror.Write(ctx, apperror.FromStatus(fiberErr.Code, fiberErr.Message), nil)
 		}
 
-		return ctx.Status(code).JSON(fiber.Map{
-			"errors": err.Error(),
-		})
+		// 4. Error tak dikenal: jangan bocorkan detailnya ke client
+		log.WithError(err).WithField("path", ctx.Path()).Error("Unhandled error")
+		return apperror.Write(ctx, apperror.ErrInternal, nil)
 	}
 }
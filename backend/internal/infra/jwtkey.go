@@ -0,0 +1,16 @@
+package infra
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/jwtkey"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewKeySet memuat key JWT sekali saat startup; config yang salah menghentikan aplikasi
+func NewKeySet(viper *viper.Viper, log *logrus.Logger) *jwtkey.KeySet {
+	keys, err := jwtkey.Load(viper)
+	if err != nil {
+		log.Fatalf("Failed to load JWT keys: %v", err)
+	}
+	return keys
+}
@@ -0,0 +1,202 @@
+package jwtkey
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrNoSigningKey  = errors.New("jwt: no signing key configured (set jwt.secret or jwt.signing_kid)")
+	ErrUnknownKey    = errors.New("jwt: unknown key id")
+	ErrUnexpectedAlg = errors.New("jwt: unexpected signing method")
+)
+
+// Key: Satu key JWT. signKey nil berarti key hanya dipakai untuk verifikasi
+// (key lama yang masih menerima token selama masa rotasi).
+type Key struct {
+	ID        string
+	Method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet: Semua key yang dimuat SEKALI saat startup.
+// Token ditandatangani dengan satu key aktif; verifikasi memilih key berdasarkan header "kid".
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+}
+
+// keyConfig: Satu entry di jwt.keys
+type keyConfig struct {
+	ID             string `mapstructure:"kid"`
+	Algorithm      string `mapstructure:"algorithm"`
+	PrivateKeyFile string `mapstructure:"private_key_file"`
+	PublicKeyFile  string `mapstructure:"public_key_file"`
+}
+
+// Load membaca key dari config:
+//   - jwt.keys: daftar key RS256/EdDSA dari file PEM, masing-masing dengan kid unik
+//   - jwt.signing_kid: kid yang dipakai untuk menandatangani token baru
+//   - jwt.secret: key HS256 lama (tanpa kid); menandatangani jika signing_kid kosong,
+//     dan tetap menerima token lama selama migrasi ke key asimetris
+func Load(cfg *viper.Viper) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*Key)}
+
+	// 1. Key HS256 lama
+	if secret := cfg.GetString("jwt.secret"); secret != "" {
+		set.keys[""] = &Key{
+			Method:    jwt.SigningMethodHS256,
+			signKey:   []byte(secret),
+			verifyKey: []byte(secret),
+		}
+	}
+
+	// 2. Key asimetris dari file
+	var configs []keyConfig
+	if err := cfg.UnmarshalKey("jwt.keys", &configs); err != nil {
+		return nil, fmt.Errorf("jwt: invalid jwt.keys: %w", err)
+	}
+	for _, kc := range configs {
+		key, err := loadKey(kc)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("jwt: duplicate kid %q", key.ID)
+		}
+		set.keys[key.ID] = key
+	}
+
+	// 3. Pilih key penandatangan
+	signingKID := cfg.GetString("jwt.signing_kid")
+	signing, ok := set.keys[signingKID]
+	if !ok || signing.signKey == nil {
+		if signingKID != "" {
+			return nil, fmt.Errorf("jwt: signing_kid %q has no private key", signingKID)
+		}
+		return nil, ErrNoSigningKey
+	}
+	set.signing = signing
+
+	return set, nil
+}
+
+// NewHMAC membuat KeySet HS256 tunggal (tanpa kid), dipakai untuk test
+func NewHMAC(secret string) *KeySet {
+	key := &Key{Method: jwt.SigningMethodHS256, signKey: []byte(secret), verifyKey: []byte(secret)}
+	return &KeySet{signing: key, keys: map[string]*Key{"": key}}
+}
+
+func loadKey(kc keyConfig) (*Key, error) {
+	if kc.ID == "" {
+		return nil, errors.New("jwt: every entry in jwt.keys needs a kid")
+	}
+
+	key := &Key{ID: kc.ID}
+	switch kc.Algorithm {
+	case "RS256":
+		key.Method = jwt.SigningMethodRS256
+	case "EdDSA":
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("jwt: key %q: unsupported algorithm %q (use RS256 or EdDSA)", kc.ID, kc.Algorithm)
+	}
+
+	// Private key tersedia => key bisa menandatangani, public key diturunkan darinya
+	if kc.PrivateKeyFile != "" {
+		pem, err := os.ReadFile(kc.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: key %q: %w", kc.ID, err)
+		}
+		var signer crypto.Signer
+		if key.Method == jwt.SigningMethodRS256 {
+			signer, err = jwt.ParseRSAPrivateKeyFromPEM(pem)
+		} else {
+			var pk crypto.PrivateKey
+			pk, err = jwt.ParseEdPrivateKeyFromPEM(pem)
+			if err == nil {
+				signer, _ = pk.(ed25519.PrivateKey)
+			}
+		}
+		if err != nil || signer == nil {
+			return nil, fmt.Errorf("jwt: key %q: invalid private key: %v", kc.ID, err)
+		}
+		key.signKey = signer
+		key.verifyKey = signer.Public()
+		return key, nil
+	}
+
+	// Hanya public key => key lama yang masih diterima saat rotasi
+	if kc.PublicKeyFile == "" {
+		return nil, fmt.Errorf("jwt: key %q needs private_key_file or public_key_file", kc.ID)
+	}
+	pem, err := os.ReadFile(kc.PublicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: key %q: %w", kc.ID, err)
+	}
+	if key.Method == jwt.SigningMethodRS256 {
+		key.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+	} else {
+		key.verifyKey, err = jwt.ParseEdPublicKeyFromPEM(pem)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: key %q: invalid public key: %w", kc.ID, err)
+	}
+	return key, nil
+}
+
+// Sign menandatangani claims dengan key aktif, header "kid" diisi jika key punya ID
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signing.Method, claims)
+	if s.signing.ID != "" {
+		token.Header["kid"] = s.signing.ID
+	}
+	return token.SignedString(s.signing.signKey)
+}
+
+// Keyfunc untuk jwt.Parse: pilih key berdasarkan "kid" dan tolak algoritma yang tidak cocok
+// (mencegah token HS256 yang ditandatangani memakai public key RSA).
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, ErrUnexpectedAlg
+	}
+	return key.verifyKey, nil
+}
+
+// ValidMethods: semua algoritma yang dipakai key di set ini, untuk jwt.WithValidMethods
+func (s *KeySet) ValidMethods() []string {
+	seen := make(map[string]bool)
+	methods := make([]string, 0, len(s.keys))
+	for _, key := range s.keys {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
+	}
+	return methods
+}
+
+// publicKeys: key asimetris yang boleh dipublikasikan lewat JWKS (HS256 tidak pernah ikut)
+func (s *KeySet) publicKeys() []*Key {
+	keys := make([]*Key, 0, len(s.keys))
+	for _, key := range s.keys {
+		switch key.verifyKey.(type) {
+		case *rsa.PublicKey, ed25519.PublicKey:
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
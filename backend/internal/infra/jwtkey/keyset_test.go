@@ -0,0 +1,191 @@
+package jwtkey_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/jwtkey"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================================
+// 1. HELPER SETUP
+// ==========================================
+
+// writePEM menulis key ke file PEM sementara dan mengembalikan path-nya
+func writePEM(t *testing.T, name string, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err)
+	return path
+}
+
+func rsaKeyFiles(t *testing.T) (string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pub, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		writePEM(t, "rsa.pub.pem", "PUBLIC KEY", pub)
+}
+
+func edKeyFile(t *testing.T) string {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return writePEM(t, "ed.pem", "PRIVATE KEY", der)
+}
+
+func claims() jwt.MapClaims {
+	return jwt.MapClaims{"sub": "uuid-123", "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func parse(keys *jwtkey.KeySet, token string) (*jwt.Token, error) {
+	return jwt.Parse(token, keys.Keyfunc, jwt.WithValidMethods(keys.ValidMethods()))
+}
+
+// ==========================================
+// 2. GROUP: LOAD TESTS
+// ==========================================
+
+func TestLoad_MissingSecretAndKeys(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("jwt.secret", "")
+
+	keys, err := jwtkey.Load(cfg)
+
+	assert.ErrorIs(t, err, jwtkey.ErrNoSigningKey)
+	assert.Nil(t, keys)
+}
+
+func TestLoad_LegacySecretSignsWithoutKID(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("jwt.secret", "legacy-secret")
+
+	keys, err := jwtkey.Load(cfg)
+	require.NoError(t, err)
+
+	signed, err := keys.Sign(claims())
+	require.NoError(t, err)
+
+	token, err := parse(keys, signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "HS256", token.Method.Alg())
+	assert.NotContains(t, token.Header, "kid")
+	// Secret HS256 tidak boleh bocor lewat JWKS
+	assert.Empty(t, keys.JWKS().Keys)
+}
+
+func TestLoad_UnknownSigningKID(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("jwt.signing_kid", "missing")
+
+	_, err := jwtkey.Load(cfg)
+
+	assert.Error(t, err)
+}
+
+// ==========================================
+// 3. GROUP: ASYMMETRIC KEY & ROTATION TESTS
+// ==========================================
+
+func TestSign_RS256WithKID(t *testing.T) {
+	private, _ := rsaKeyFiles(t)
+	cfg := viper.New()
+	cfg.Set("jwt.signing_kid", "2026-01")
+	cfg.Set("jwt.keys", []map[string]interface{}{
+		{"kid": "2026-01", "algorithm": "RS256", "private_key_file": private},
+	})
+
+	keys, err := jwtkey.Load(cfg)
+	require.NoError(t, err)
+
+	signed, err := keys.Sign(claims())
+	require.NoError(t, err)
+
+	token, err := parse(keys, signed)
+	assert.NoError(t, err)
+	assert.Equal(t, "RS256", token.Method.Alg())
+	assert.Equal(t, "2026-01", token.Header["kid"])
+}
+
+func TestRotation_OldKeyStillVerifies(t *testing.T) {
+	oldPrivate, oldPublic := rsaKeyFiles(t)
+	newPrivate := edKeyFile(t)
+
+	// Sebelum rotasi: token ditandatangani key lama
+	before := viper.New()
+	before.Set("jwt.signing_kid", "old")
+	before.Set("jwt.keys", []map[string]interface{}{
+		{"kid": "old", "algorithm": "RS256", "private_key_file": oldPrivate},
+	})
+	oldKeys, err := jwtkey.Load(before)
+	require.NoError(t, err)
+	oldToken, err := oldKeys.Sign(claims())
+	require.NoError(t, err)
+
+	// Sesudah rotasi: key baru EdDSA menandatangani, key lama hanya public key
+	after := viper.New()
+	after.Set("jwt.signing_kid", "new")
+	after.Set("jwt.keys", []map[string]interface{}{
+		{"kid": "new", "algorithm": "EdDSA", "private_key_file": newPrivate},
+		{"kid": "old", "algorithm": "RS256", "public_key_file": oldPublic},
+	})
+	keys, err := jwtkey.Load(after)
+	require.NoError(t, err)
+
+	_, err = parse(keys, oldToken)
+	assert.NoError(t, err)
+
+	newToken, err := keys.Sign(claims())
+	require.NoError(t, err)
+	token, err := parse(keys, newToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "EdDSA", token.Method.Alg())
+
+	jwks := keys.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "Ed25519", jwks.Keys[0].Curve)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+}
+
+func TestKeyfunc_RejectsUnknownKIDAndAlgorithmMismatch(t *testing.T) {
+	private, public := rsaKeyFiles(t)
+	cfg := viper.New()
+	cfg.Set("jwt.signing_kid", "rsa")
+	cfg.Set("jwt.keys", []map[string]interface{}{
+		{"kid": "rsa", "algorithm": "RS256", "private_key_file": private},
+	})
+	keys, err := jwtkey.Load(cfg)
+	require.NoError(t, err)
+
+	// Token HS256 yang "ditandatangani" memakai isi public key RSA
+	publicPEM, err := os.ReadFile(public)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims())
+	forged.Header["kid"] = "rsa"
+	forgedString, err := forged.SignedString(publicPEM)
+	require.NoError(t, err)
+
+	_, err = parse(keys, forgedString)
+	assert.Error(t, err)
+
+	unknown := jwt.NewWithClaims(jwt.SigningMethodRS256, claims())
+	unknown.Header["kid"] = "nope"
+
+	_, err = keys.Keyfunc(unknown)
+	assert.ErrorIs(t, err, jwtkey.ErrUnknownKey)
+}
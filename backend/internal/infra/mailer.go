@@ -0,0 +1,26 @@
+package infra
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/mailer"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewMailer memilih implementasi Mailer berdasarkan mail.driver ("log" atau "file")
+func NewMailer(viper *viper.Viper, log *logrus.Logger) mailer.Mailer {
+	from := viper.GetString("mail.from")
+
+	switch driver := viper.GetString("mail.driver"); driver {
+	case "", "log":
+		return mailer.NewLogMailer(from, log)
+	case "file":
+		m, err := mailer.NewFileMailer(from, viper.GetString("mail.dir"))
+		if err != nil {
+			log.Fatalf("Failed to init file mailer: %v", err)
+		}
+		return m
+	default:
+		log.Fatalf("Unknown mail driver: %s", driver)
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Message: Email sederhana berbasis plain text
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer: Abstraksi pengiriman email, implementasi bisa diganti (SMTP, API provider, dll)
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// logMailer hanya menulis email ke log, cocok untuk development lokal
+type logMailer struct {
+	from string
+	log  *logrus.Logger
+}
+
+func NewLogMailer(from string, log *logrus.Logger) Mailer {
+	return &logMailer{from: from, log: log}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg *Message) error {
+	m.log.WithFields(logrus.Fields{
+		"from":    m.from,
+		"to":      msg.To,
+		"subject": msg.Subject,
+	}).Info(msg.Body)
+	return nil
+}
+
+// fileMailer menyimpan setiap email sebagai file .eml di sebuah folder
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+func NewFileMailer(from string, dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fileMailer{from: from, dir: dir}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg *Message) error {
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405"), uuid.New().String())
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(b.String()), 0o600)
+}
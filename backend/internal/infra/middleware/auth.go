@@ -1,60 +1,180 @@
 package middleware
 
 import (
+	"context"
 	"strings"
+	"time"
 
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/spf13/viper"
 )
 
-func AuthMiddleware(cfg *viper.Viper) fiber.Handler {
+// RevocationChecker: Sumber data token yang sudah dicabut (logout)
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (bool, error)
+}
+
+// SessionChecker: Sumber data sesi login; Touch juga memperbarui last-seen
+type SessionChecker interface {
+	Touch(ctx context.Context, sessionID string, userID string) (bool, error)
+}
+
+// TokenVerifier: Key verifikasi JWT (jwtkey.KeySet), dimuat sekali saat startup
+type TokenVerifier interface {
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	ValidMethods() []string
+}
+
+// UserChecker: Pemilik token harus masih ada, belum dihapus, dan tidak dinonaktifkan
+type UserChecker interface {
+	IsActive(ctx context.Context, userID string) (bool, error)
+}
+
+// APITokenAuthenticator: Validasi personal access token.
+// userID kosong berarti token tidak dikenal, sudah dicabut, atau kadaluarsa.
+type APITokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (userID string, readOnly bool, err error)
+}
+
+var (
+	ErrMissingToken      = apperror.Unauthorized("missing_token", "missing authorization header")
+	ErrInvalidAuthFormat = apperror.Unauthorized("invalid_authorization_format", "authorization header must use the Bearer scheme")
+	ErrInvalidToken      = apperror.Unauthorized("invalid_token", "invalid or expired token")
+	ErrInvalidClaims     = apperror.Unauthorized("invalid_token_claims", "invalid token claims")
+	ErrTokenRevoked      = apperror.Unauthorized("token_revoked", "token has been revoked")
+	ErrSessionTerminated = apperror.Unauthorized("session_terminated", "session has been terminated")
+	ErrUserInactive      = apperror.Unauthorized("user_inactive", "account no longer exists or has been disabled")
+	ErrReadOnlyToken     = apperror.Forbidden("read_only_token", "token is read-only")
+)
+
+// AuthOptions: Claim iss/aud yang wajib cocok (kosong = tidak dicek), toleransi
+// selisih jam antar server untuk exp/nbf/iat, dan prefix personal access token
+// (apitoken.TokenPrefix; kosong = hanya JWT yang diterima)
+type AuthOptions struct {
+	Issuer         string
+	Audience       string
+	Leeway         time.Duration
+	APITokenPrefix string
+}
+
+func AuthMiddleware(keys TokenVerifier, revocations RevocationChecker, sessions SessionChecker, apiTokens APITokenAuthenticator, users UserChecker, opts AuthOptions) fiber.Handler {
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(keys.ValidMethods()),
+		jwt.WithLeeway(opts.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if opts.Issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(opts.Audience))
+	}
+	parser := jwt.NewParser(parserOptions...)
+
 	return func(c *fiber.Ctx) error {
 		// 1. Ambil Header Authorization
 		autHeader := c.Get("Authorization")
 		if autHeader == "" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Missing Authorization header",
-			})
+			return ErrMissingToken
 		}
 
 		// 2. Format Harus "Bearer <Token>"
 		parts := strings.Split(autHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid Authorization Format",
-			})
+			return ErrInvalidAuthFormat
 		}
 		tokenString := parts[1]
 
-		// 3. Parse & Validasi Token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fiber.ErrUnauthorized
-			}
-			secret := cfg.GetString("jwt.secret")
-			return []byte(secret), nil
-		})
+		if opts.APITokenPrefix != "" && strings.HasPrefix(tokenString, opts.APITokenPrefix) {
+			return authenticateAPIToken(c, apiTokens, users, tokenString)
+		}
 
-		// 4. Cek Error Parse
+		// 3. Parse & Validasi Token (key dipilih berdasarkan header kid; exp/nbf/iat/iss/aud dicek parser)
+		var claims AccessClaims
+		token, err := parser.ParseWithClaims(tokenString, &claims, keys.Keyfunc)
 		if err != nil || !token.Valid {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid or Expired token",
-			})
+			return ErrInvalidToken
 		}
 
-		// 5. Ekstrak Claims (Data User)
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": "Invalid Token Claims",
-			})
+		// 4. Claim wajib yang dipakai pengecekan berikutnya
+		if claims.Subject == "" || claims.ID == "" || claims.SessionID == "" || claims.IssuedAt == nil {
+			return ErrInvalidClaims
 		}
 
-		// 6. Simpan USer ID ke Contex (Agar bisa di pakai di controller)
-		c.Locals("user_id", claims["sub"])
-		c.Locals("email", claims["email"])
+		// 5. Tolak token yang sudah dicabut (logout / logout semua device)
+		revoked, err := revocations.IsRevoked(c.Context(), claims.ID, claims.Subject, claims.IssuedAt.Time)
+		if err != nil {
+			return err
+		}
+		if revoked {
+			return ErrTokenRevoked
+		}
+
+		// 6. Tolak token dari sesi yang sudah diakhiri (logout device), sekaligus catat last-seen
+		active, err := sessions.Touch(c.Context(), claims.SessionID, claims.Subject)
+		if err != nil {
+			return err
+		}
+		if !active {
+			return ErrSessionTerminated
+		}
+
+		// 7. Pemilik token harus masih ada dan aktif
+		if err := checkUser(c, users, claims.Subject); err != nil {
+			return err
+		}
+
+		// 8. Simpan identitas ke Context (dibaca handler lewat CurrentPrincipal / CurrentUserID)
+		setPrincipal(c, &Principal{
+			UserID:     claims.Subject,
+			Email:      claims.Email,
+			Role:       claims.Role,
+			SessionID:  claims.SessionID,
+			TokenID:    claims.ID,
+			ExpiresAt:  claims.ExpiresAt.Time,
+			AuthMethod: AuthMethodJWT,
+		})
 
 		return c.Next()
 	}
 }
+
+// authenticateAPIToken: Personal access token menghasilkan Principal yang sama dengan JWT (tanpa sesi & role).
+// Token read-only hanya boleh dipakai untuk request baca.
+func authenticateAPIToken(c *fiber.Ctx, apiTokens APITokenAuthenticator, users UserChecker, tokenString string) error {
+	userID, readOnly, err := apiTokens.Authenticate(c.Context(), tokenString)
+	if err != nil {
+		return err
+	}
+	if userID == "" {
+		return ErrInvalidToken
+	}
+
+	if readOnly {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		default:
+			return ErrReadOnlyToken
+		}
+	}
+
+	if err := checkUser(c, users, userID); err != nil {
+		return err
+	}
+
+	setPrincipal(c, &Principal{UserID: userID, AuthMethod: AuthMethodAPIToken, ReadOnly: readOnly})
+	return c.Next()
+}
+
+func checkUser(c *fiber.Ctx, users UserChecker, userID string) error {
+	active, err := users.IsActive(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+	if !active {
+		return ErrUserInactive
+	}
+	return nil
+}
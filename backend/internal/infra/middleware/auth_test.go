@@ -0,0 +1,280 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/jwtkey"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================================
+// 1. HELPER SETUP
+// ==========================================
+
+const (
+	testUserID = "6f1c2a8e-8a43-4a4e-9a53-0c6f7d1e2b3c"
+	testIssuer = "finance-tracker-app"
+	testAud    = "finance-tracker-api"
+	testPrefix = "ftk_"
+)
+
+var keys = jwtkey.NewHMAC("secret_key_testing_123")
+
+type fakeRevocations struct{ revoked bool }
+
+func (f fakeRevocations) IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (bool, error) {
+	return f.revoked, nil
+}
+
+type fakeSessions struct{ active bool }
+
+func (f fakeSessions) Touch(ctx context.Context, sessionID string, userID string) (bool, error) {
+	return f.active, nil
+}
+
+type fakeAPITokens struct {
+	userID   string
+	readOnly bool
+}
+
+func (f fakeAPITokens) Authenticate(ctx context.Context, token string) (string, bool, error) {
+	return f.userID, f.readOnly, nil
+}
+
+type fakeUsers struct{ active map[string]bool }
+
+func (f fakeUsers) IsActive(ctx context.Context, userID string) (bool, error) {
+	return f.active[userID], nil
+}
+
+type deps struct {
+	revocations fakeRevocations
+	sessions    fakeSessions
+	apiTokens   fakeAPITokens
+	users       fakeUsers
+}
+
+func defaultDeps() *deps {
+	return &deps{
+		sessions:  fakeSessions{active: true},
+		apiTokens: fakeAPITokens{userID: testUserID},
+		users:     fakeUsers{active: map[string]bool{testUserID: true}},
+	}
+}
+
+// newApp memasang AuthMiddleware di depan handler yang mengembalikan Principal sebagai JSON
+func newApp(d *deps, extra ...fiber.Handler) *fiber.App {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			if appErr := apperror.From(err); appErr != nil {
+				return apperror.Write(c, appErr, nil)
+			}
+			return c.SendStatus(fiber.StatusInternalServerError)
+		},
+	})
+	auth := middleware.AuthMiddleware(keys, d.revocations, d.sessions, d.apiTokens, d.users, middleware.AuthOptions{
+		Issuer:         testIssuer,
+		Audience:       testAud,
+		Leeway:         30 * time.Second,
+		APITokenPrefix: testPrefix,
+	})
+	handlers := append([]fiber.Handler{auth}, extra...)
+	handlers = append(handlers, func(c *fiber.Ctx) error {
+		principal, ok := middleware.CurrentPrincipal(c)
+		if !ok {
+			return c.SendStatus(fiber.StatusTeapot)
+		}
+		return c.JSON(principal)
+	})
+	app.All("/", handlers...)
+	return app
+}
+
+func validClaims() middleware.AccessClaims {
+	now := time.Now()
+	return middleware.AccessClaims{
+		SessionID: "session-1",
+		Role:      "admin",
+		Email:     "user@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-1",
+			Subject:   testUserID,
+			Issuer:    testIssuer,
+			Audience:  jwt.ClaimStrings{testAud},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		},
+	}
+}
+
+func sign(t *testing.T, claims jwt.Claims) string {
+	token, err := keys.Sign(claims)
+	require.NoError(t, err)
+	return token
+}
+
+// call mengirim request dan mengembalikan status, code error (jika ada), dan Principal (jika sukses)
+func call(t *testing.T, app *fiber.App, method string, bearer string) (int, string, middleware.Principal) {
+	req := httptest.NewRequest(method, "/", nil)
+	if bearer != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+bearer)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	var principal middleware.Principal
+	var problem apperror.Problem
+	if resp.StatusCode == fiber.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&principal))
+	} else {
+		_ = json.NewDecoder(resp.Body).Decode(&problem)
+	}
+	return resp.StatusCode, problem.Code, principal
+}
+
+// ==========================================
+// 2. GROUP: JWT TESTS
+// ==========================================
+
+func TestAuth_ValidTokenSetsPrincipal(t *testing.T) {
+	status, _, principal := call(t, newApp(defaultDeps()), "GET", sign(t, validClaims()))
+
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, testUserID, principal.UserID)
+	assert.Equal(t, "session-1", principal.SessionID)
+	assert.Equal(t, "jti-1", principal.TokenID)
+	assert.Equal(t, "admin", principal.Role)
+	assert.Equal(t, middleware.AuthMethodJWT, principal.AuthMethod)
+}
+
+func TestAuth_MissingHeader(t *testing.T) {
+	status, code, _ := call(t, newApp(defaultDeps()), "GET", "")
+
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+	assert.Equal(t, "missing_token", code)
+}
+
+func TestAuth_MissingSubjectIsRejected(t *testing.T) {
+	claims := validClaims()
+	claims.Subject = ""
+
+	status, code, _ := call(t, newApp(defaultDeps()), "GET", sign(t, claims))
+
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+	assert.Equal(t, "invalid_token_claims", code)
+}
+
+func TestAuth_WrongIssuerOrAudience(t *testing.T) {
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "someone-else"
+	wrongAudience := validClaims()
+	wrongAudience.Audience = jwt.ClaimStrings{"other-api"}
+
+	for _, claims := range []middleware.AccessClaims{wrongIssuer, wrongAudience} {
+		status, code, _ := call(t, newApp(defaultDeps()), "GET", sign(t, claims))
+
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+		assert.Equal(t, "invalid_token", code)
+	}
+}
+
+func TestAuth_NotBeforeUsesLeeway(t *testing.T) {
+	withinLeeway := validClaims()
+	withinLeeway.NotBefore = jwt.NewNumericDate(time.Now().Add(10 * time.Second))
+	tooEarly := validClaims()
+	tooEarly.NotBefore = jwt.NewNumericDate(time.Now().Add(5 * time.Minute))
+
+	status, _, _ := call(t, newApp(defaultDeps()), "GET", sign(t, withinLeeway))
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status, code, _ := call(t, newApp(defaultDeps()), "GET", sign(t, tooEarly))
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+	assert.Equal(t, "invalid_token", code)
+}
+
+func TestAuth_ExpiredBeyondLeeway(t *testing.T) {
+	claims := validClaims()
+	claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+	claims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	status, code, _ := call(t, newApp(defaultDeps()), "GET", sign(t, claims))
+
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+	assert.Equal(t, "invalid_token", code)
+}
+
+func TestAuth_RevokedTokenAndTerminatedSession(t *testing.T) {
+	revoked := defaultDeps()
+	revoked.revocations.revoked = true
+	terminated := defaultDeps()
+	terminated.sessions.active = false
+
+	_, code, _ := call(t, newApp(revoked), "GET", sign(t, validClaims()))
+	assert.Equal(t, "token_revoked", code)
+
+	_, code, _ = call(t, newApp(terminated), "GET", sign(t, validClaims()))
+	assert.Equal(t, "session_terminated", code)
+}
+
+func TestAuth_DeletedOrDisabledUser(t *testing.T) {
+	d := defaultDeps()
+	d.users.active[testUserID] = false
+
+	status, code, _ := call(t, newApp(d), "GET", sign(t, validClaims()))
+
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+	assert.Equal(t, "user_inactive", code)
+}
+
+// ==========================================
+// 3. GROUP: API TOKEN & ROLE TESTS
+// ==========================================
+
+func TestAuth_APITokenPrincipal(t *testing.T) {
+	status, _, principal := call(t, newApp(defaultDeps()), "GET", testPrefix+"abc")
+
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, testUserID, principal.UserID)
+	assert.Equal(t, middleware.AuthMethodAPIToken, principal.AuthMethod)
+	assert.Empty(t, principal.SessionID)
+}
+
+func TestAuth_ReadOnlyAPITokenCannotWrite(t *testing.T) {
+	d := defaultDeps()
+	d.apiTokens.readOnly = true
+
+	status, code, _ := call(t, newApp(d), "POST", testPrefix+"abc")
+
+	assert.Equal(t, fiber.StatusForbidden, status)
+	assert.Equal(t, "read_only_token", code)
+}
+
+func TestAuth_APITokenOfDisabledUser(t *testing.T) {
+	d := defaultDeps()
+	d.users.active[testUserID] = false
+
+	_, code, _ := call(t, newApp(d), "GET", testPrefix+"abc")
+
+	assert.Equal(t, "user_inactive", code)
+}
+
+func TestRequireRole(t *testing.T) {
+	adminOnly := middleware.RequireRole("admin")
+
+	status, _, _ := call(t, newApp(defaultDeps(), adminOnly), "GET", sign(t, validClaims()))
+	assert.Equal(t, fiber.StatusOK, status)
+
+	// API token tidak membawa role
+	status, code, _ := call(t, newApp(defaultDeps(), adminOnly), "GET", testPrefix+"abc")
+	assert.Equal(t, fiber.StatusForbidden, status)
+	assert.Equal(t, "forbidden", code)
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AccessClaims: Isi access token JWT. Dipakai saat sign (module user) dan saat verifikasi (AuthMiddleware)
+// supaya kedua sisi selalu sepakat soal nama claim.
+type AccessClaims struct {
+	SessionID string `json:"sid"`
+	Role      string `json:"role,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Email     string `json:"email,omitempty"`
+	jwt.RegisteredClaims
+}
+
+const (
+	AuthMethodJWT      = "jwt"
+	AuthMethodAPIToken = "api_token"
+)
+
+// Principal: Identitas pemanggil yang sudah lolos AuthMiddleware
+type Principal struct {
+	UserID     string
+	Email      string
+	Role       string // kosong untuk API token
+	SessionID  string // kosong untuk API token
+	TokenID    string // jti, kosong untuk API token
+	ExpiresAt  time.Time
+	AuthMethod string
+	ReadOnly   bool
+}
+
+const principalKey = "principal"
+
+func setPrincipal(c *fiber.Ctx, principal *Principal) {
+	c.Locals(principalKey, principal)
+}
+
+// CurrentPrincipal membaca Principal yang diset AuthMiddleware.
+// ok=false berarti route tidak dipasang di belakang AuthMiddleware.
+func CurrentPrincipal(c *fiber.Ctx) (*Principal, bool) {
+	principal, ok := c.Locals(principalKey).(*Principal)
+	return principal, ok && principal != nil && principal.UserID != ""
+}
+
+// CurrentUserID: shortcut CurrentPrincipal untuk handler yang hanya butuh user id
+func CurrentUserID(c *fiber.Ctx) (string, bool) {
+	principal, ok := CurrentPrincipal(c)
+	if !ok {
+		return "", false
+	}
+	return principal.UserID, true
+}
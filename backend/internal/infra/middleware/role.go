@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"slices"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequireRole dipasang SETELAH AuthMiddleware.
+// Role dibaca dari claim JWT, jadi request dengan API token (tanpa role) selalu ditolak.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok || principal.Role == "" || !slices.Contains(roles, principal.Role) {
+			return apperror.ErrForbidden
+		}
+		return c.Next()
+	}
+}
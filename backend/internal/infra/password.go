@@ -0,0 +1,25 @@
+package infra
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/password"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewPasswordHasher membaca algoritma hash password; config yang salah menghentikan aplikasi
+func NewPasswordHasher(viper *viper.Viper, log *logrus.Logger) *password.Hasher {
+	hasher, err := password.Load(viper)
+	if err != nil {
+		log.Fatalf("Failed to load password hasher: %v", err)
+	}
+	return hasher
+}
+
+// NewPasswordPolicy membaca aturan password baru; config yang salah menghentikan aplikasi
+func NewPasswordPolicy(viper *viper.Viper, log *logrus.Logger) *password.Policy {
+	policy, err := password.LoadPolicy(viper)
+	if err != nil {
+		log.Fatalf("Failed to load password policy: %v", err)
+	}
+	return policy
+}
@@ -0,0 +1,199 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var (
+	ErrUnknownAlgorithm = errors.New("password: unknown hash algorithm")
+	ErrInvalidHash      = errors.New("password: invalid hash format")
+)
+
+// Argon2Params: parameter argon2id, default mengikuti rekomendasi minimum OWASP
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params: 19 MiB, 2 iterasi, 1 thread
+var DefaultArgon2Params = Argon2Params{Memory: 19 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// Hasher membuat hash password baru dengan algoritma dari config,
+// tapi tetap bisa memverifikasi hash lama (bcrypt / argon2id dengan parameter berbeda).
+type Hasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     Argon2Params
+	dummy      string
+}
+
+// NewHasher membuat Hasher; dummy hash dibuat sekali di sini untuk Verify dengan hash kosong
+func NewHasher(algorithm string, bcryptCost int, params Argon2Params) (*Hasher, error) {
+	if algorithm != AlgorithmArgon2id && algorithm != AlgorithmBcrypt {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, algorithm)
+	}
+	if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("password: bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	if params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 || params.SaltLength == 0 || params.KeyLength == 0 {
+		return nil, errors.New("password: argon2 parameters must be positive")
+	}
+
+	h := &Hasher{algorithm: algorithm, bcryptCost: bcryptCost, argon2: params}
+
+	dummy, err := h.Hash("dummy-password-for-constant-time")
+	if err != nil {
+		return nil, err
+	}
+	h.dummy = dummy
+	return h, nil
+}
+
+// Load membaca password.hash.* dari config:
+//   - algorithm: "argon2id" (default) atau "bcrypt"
+//   - bcrypt_cost: default bcrypt.DefaultCost
+//   - argon2.memory (KiB) / argon2.iterations / argon2.parallelism
+func Load(cfg *viper.Viper) (*Hasher, error) {
+	algorithm := cfg.GetString("password.hash.algorithm")
+	if algorithm == "" {
+		algorithm = AlgorithmArgon2id // Default value
+	}
+	cost := cfg.GetInt("password.hash.bcrypt_cost")
+	if cost == 0 {
+		cost = bcrypt.DefaultCost // Default value
+	}
+
+	params := DefaultArgon2Params
+	if v := cfg.GetUint32("password.hash.argon2.memory"); v > 0 {
+		params.Memory = v
+	}
+	if v := cfg.GetUint32("password.hash.argon2.iterations"); v > 0 {
+		params.Iterations = v
+	}
+	if v := cfg.GetUint8("password.hash.argon2.parallelism"); v > 0 {
+		params.Parallelism = v
+	}
+
+	return NewHasher(algorithm, cost, params)
+}
+
+// Hash membuat hash baru dengan algoritma yang dikonfigurasi.
+// Format argon2id mengikuti PHC string: $argon2id$v=19$m=...,t=...,p=...$salt$hash
+func (h *Hasher) Hash(plain string) (string, error) {
+	if h.algorithm == AlgorithmBcrypt {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(plain), h.bcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashed), nil
+	}
+
+	salt := make([]byte, h.argon2.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plain), salt, h.argon2.Iterations, h.argon2.Memory, h.argon2.Parallelism, h.argon2.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.argon2.Memory, h.argon2.Iterations, h.argon2.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify mencocokkan password dengan hash (bcrypt atau argon2id).
+// Hash kosong (user tidak ditemukan) tetap menjalankan perhitungan penuh terhadap dummy hash
+// lalu return false, supaya waktu respons tidak membocorkan keberadaan akun.
+func (h *Hasher) Verify(hash string, plain string) (bool, error) {
+	if hash == "" {
+		_, _ = h.Verify(h.dummy, plain)
+		return false, nil
+	}
+
+	if isBcrypt(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	params, salt, key, err := decodeArgon2(hash)
+	if err != nil {
+		return false, err
+	}
+	actual := argon2.IDKey([]byte(plain), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(actual, key) == 1, nil
+}
+
+// NeedsRehash: true jika hash memakai algoritma lain atau parameter yang lebih lemah dari config.
+// Dipanggil setelah login sukses, saat password plaintext tersedia untuk di-hash ulang.
+func (h *Hasher) NeedsRehash(hash string) bool {
+	if h.algorithm == AlgorithmBcrypt {
+		if !isBcrypt(hash) {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost < h.bcryptCost
+	}
+
+	params, _, key, err := decodeArgon2(hash)
+	if err != nil {
+		return true
+	}
+	return params.Memory < h.argon2.Memory ||
+		params.Iterations < h.argon2.Iterations ||
+		params.Parallelism < h.argon2.Parallelism ||
+		uint32(len(key)) < h.argon2.KeyLength
+}
+
+func isBcrypt(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// decodeArgon2 mem-parse PHC string argon2id
+func decodeArgon2(hash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrInvalidHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return params, nil, nil, ErrInvalidHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrInvalidHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
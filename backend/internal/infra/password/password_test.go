@@ -0,0 +1,172 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/password"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ==========================================
+// 1. HELPER SETUP
+// ==========================================
+
+// fastArgon2: parameter kecil supaya test cepat
+var fastArgon2 = password.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newHasher(t *testing.T, algorithm string) *password.Hasher {
+	hasher, err := password.NewHasher(algorithm, bcrypt.MinCost, fastArgon2)
+	require.NoError(t, err)
+	return hasher
+}
+
+// ==========================================
+// 2. GROUP: HASHER TESTS
+// ==========================================
+
+func TestHasher_Argon2idRoundTrip(t *testing.T) {
+	hasher := newHasher(t, password.AlgorithmArgon2id)
+
+	hash, err := hasher.Hash("rahasia-sekali")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+
+	ok, err := hasher.Verify(hash, "rahasia-sekali")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify(hash, "salah")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.False(t, hasher.NeedsRehash(hash))
+}
+
+func TestHasher_VerifiesLegacyBcrypt(t *testing.T) {
+	hasher := newHasher(t, password.AlgorithmArgon2id)
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("rahasia-sekali"), bcrypt.MinCost)
+
+	ok, err := hasher.Verify(string(legacy), "rahasia-sekali")
+
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, hasher.NeedsRehash(string(legacy)))
+}
+
+func TestHasher_WeakerArgon2ParamsNeedRehash(t *testing.T) {
+	weak := newHasher(t, password.AlgorithmArgon2id)
+	hash, err := weak.Hash("rahasia-sekali")
+	require.NoError(t, err)
+
+	stronger := fastArgon2
+	stronger.Iterations = 2
+	hasher, err := password.NewHasher(password.AlgorithmArgon2id, bcrypt.MinCost, stronger)
+	require.NoError(t, err)
+
+	assert.True(t, hasher.NeedsRehash(hash))
+	// Hash lama tetap bisa diverifikasi dengan parameter yang tersimpan di hash
+	ok, err := hasher.Verify(hash, "rahasia-sekali")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestHasher_BcryptCostUpgrade(t *testing.T) {
+	hasher, err := password.NewHasher(password.AlgorithmBcrypt, bcrypt.MinCost+1, fastArgon2)
+	require.NoError(t, err)
+	cheap, _ := bcrypt.GenerateFromPassword([]byte("rahasia-sekali"), bcrypt.MinCost)
+
+	assert.True(t, hasher.NeedsRehash(string(cheap)))
+}
+
+func TestHasher_EmptyHashNeverMatches(t *testing.T) {
+	hasher := newHasher(t, password.AlgorithmArgon2id)
+
+	ok, err := hasher.Verify("", "dummy-password-for-constant-time")
+
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_InvalidHash(t *testing.T) {
+	hasher := newHasher(t, password.AlgorithmArgon2id)
+
+	ok, err := hasher.Verify("$argon2id$v=19$broken", "x")
+
+	assert.ErrorIs(t, err, password.ErrInvalidHash)
+	assert.False(t, ok)
+}
+
+func TestLoad_UnknownAlgorithm(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("password.hash.algorithm", "md5")
+
+	_, err := password.Load(cfg)
+
+	assert.ErrorIs(t, err, password.ErrUnknownAlgorithm)
+}
+
+// ==========================================
+// 3. GROUP: POLICY TESTS
+// ==========================================
+
+func policyRule(t *testing.T, err error) string {
+	var policyErr *password.PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	return policyErr.Rule
+}
+
+func TestPolicy_Defaults(t *testing.T) {
+	policy := password.DefaultPolicy()
+
+	assert.NoError(t, policy.Check("tabungan-aman-2026", "budi", "budi@example.com"))
+	assert.Equal(t, password.RuleMinLength, policyRule(t, policy.Check("pendek")))
+	assert.Equal(t, password.RuleMaxLength, policyRule(t, policy.Check(strings.Repeat("a", 73))))
+	assert.Equal(t, password.RuleCommon, policyRule(t, policy.Check("Password123")))
+	assert.Equal(t, password.RulePersonal, policyRule(t, policy.Check("BUDI-tabungan", "budi")))
+	assert.Equal(t, password.RulePersonal, policyRule(t, policy.Check("siti.rahma-2026", "siti", "siti.rahma@example.com")))
+}
+
+func TestPolicy_ShortPersonalValuesIgnored(t *testing.T) {
+	policy := password.DefaultPolicy()
+
+	assert.NoError(t, policy.Check("tabungan-aman-2026", "ab", "ta@example.com"))
+}
+
+func TestPolicy_CharacterClasses(t *testing.T) {
+	policy := &password.Policy{MinLength: 8, MaxLength: 72, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+
+	assert.Equal(t, password.RuleUpper, policyRule(t, policy.Check("tabungan-2026")))
+	assert.Equal(t, password.RuleLower, policyRule(t, policy.Check("TABUNGAN-2026")))
+	assert.Equal(t, password.RuleDigit, policyRule(t, policy.Check("Tabungan-Aman")))
+	assert.Equal(t, password.RuleSymbol, policyRule(t, policy.Check("Tabungan2026")))
+	assert.NoError(t, policy.Check("Tabungan-2026"))
+}
+
+func TestLoadPolicy_FromConfig(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("password.policy.min_length", 12)
+	cfg.Set("password.policy.require_digit", true)
+	cfg.Set("password.policy.reject_common", false)
+
+	policy, err := password.LoadPolicy(cfg)
+
+	require.NoError(t, err)
+	assert.Equal(t, 12, policy.MinLength)
+	assert.Equal(t, 72, policy.MaxLength)
+	assert.True(t, policy.RequireDigit)
+	assert.False(t, policy.RejectCommon)
+	assert.True(t, policy.RejectPersonal)
+}
+
+func TestLoadPolicy_InvalidLengths(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set("password.policy.min_length", 20)
+	cfg.Set("password.policy.max_length", 10)
+
+	_, err := password.LoadPolicy(cfg)
+
+	assert.Error(t, err)
+}
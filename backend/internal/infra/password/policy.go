@@ -0,0 +1,181 @@
+package password
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+// Rule: nama aturan policy yang dilanggar, dipakai client untuk menampilkan pesan
+const (
+	RuleMinLength = "min_length"
+	RuleMaxLength = "max_length"
+	RuleUpper     = "uppercase"
+	RuleLower     = "lowercase"
+	RuleDigit     = "digit"
+	RuleSymbol    = "symbol"
+	RuleCommon    = "common"
+	RulePersonal  = "personal"
+)
+
+// minPersonalLength: potongan username / email yang lebih pendek dari ini tidak dicek (terlalu banyak false positive)
+const minPersonalLength = 3
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// commonPasswords: daftar password yang paling sering bocor, disimpan lowercase
+var commonPasswords = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordList, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			set[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return set
+}()
+
+// PolicyError: password ditolak karena melanggar satu aturan policy
+type PolicyError struct {
+	Field string // nama field JSON, diisi pemanggil (Check tidak tahu asal password)
+	Rule  string
+	Param int // panjang minimum / maksimum untuk RuleMinLength & RuleMaxLength
+}
+
+func (e *PolicyError) Error() string {
+	switch e.Rule {
+	case RuleMinLength:
+		return fmt.Sprintf("password must be at least %d characters", e.Param)
+	case RuleMaxLength:
+		return fmt.Sprintf("password must be at most %d bytes", e.Param)
+	case RuleUpper:
+		return "password must contain an uppercase letter"
+	case RuleLower:
+		return "password must contain a lowercase letter"
+	case RuleDigit:
+		return "password must contain a digit"
+	case RuleSymbol:
+		return "password must contain a symbol"
+	case RuleCommon:
+		return "password is too common"
+	case RulePersonal:
+		return "password must not contain your username or email"
+	}
+	return "password does not meet the password policy"
+}
+
+// Policy: aturan password baru (register, ganti password, reset password)
+type Policy struct {
+	MinLength      int // dihitung per karakter
+	MaxLength      int // dihitung per byte (bcrypt hanya membaca 72 byte pertama)
+	RequireUpper   bool
+	RequireLower   bool
+	RequireDigit   bool
+	RequireSymbol  bool
+	RejectCommon   bool
+	RejectPersonal bool
+}
+
+// DefaultPolicy: panjang 8-72 tanpa aturan komposisi (sesuai NIST 800-63B), tolak password umum & data pribadi
+func DefaultPolicy() *Policy {
+	return &Policy{MinLength: 8, MaxLength: 72, RejectCommon: true, RejectPersonal: true}
+}
+
+// LoadPolicy membaca password.policy.* dari config; key yang tidak diisi memakai DefaultPolicy
+func LoadPolicy(cfg *viper.Viper) (*Policy, error) {
+	policy := DefaultPolicy()
+	sub := cfg.Sub("password.policy")
+	if sub == nil {
+		return policy, nil
+	}
+
+	if sub.IsSet("min_length") {
+		policy.MinLength = sub.GetInt("min_length")
+	}
+	if sub.IsSet("max_length") {
+		policy.MaxLength = sub.GetInt("max_length")
+	}
+	if sub.IsSet("reject_common") {
+		policy.RejectCommon = sub.GetBool("reject_common")
+	}
+	if sub.IsSet("reject_personal") {
+		policy.RejectPersonal = sub.GetBool("reject_personal")
+	}
+	policy.RequireUpper = sub.GetBool("require_upper")
+	policy.RequireLower = sub.GetBool("require_lower")
+	policy.RequireDigit = sub.GetBool("require_digit")
+	policy.RequireSymbol = sub.GetBool("require_symbol")
+
+	if policy.MinLength < 1 {
+		return nil, errors.New("password: policy min_length must be at least 1")
+	}
+	if policy.MaxLength < policy.MinLength {
+		return nil, errors.New("password: policy max_length must not be less than min_length")
+	}
+	return policy, nil
+}
+
+// Check return *PolicyError untuk aturan pertama yang dilanggar.
+// personal: username / email pemilik akun; untuk email yang dicek bagian sebelum "@".
+func (p *Policy) Check(plain string, personal ...string) error {
+	// 1. Panjang
+	if utf8.RuneCountInString(plain) < p.MinLength {
+		return &PolicyError{Rule: RuleMinLength, Param: p.MinLength}
+	}
+	if len(plain) > p.MaxLength {
+		return &PolicyError{Rule: RuleMaxLength, Param: p.MaxLength}
+	}
+
+	// 2. Komposisi karakter
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range plain {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	switch {
+	case p.RequireUpper && !hasUpper:
+		return &PolicyError{Rule: RuleUpper}
+	case p.RequireLower && !hasLower:
+		return &PolicyError{Rule: RuleLower}
+	case p.RequireDigit && !hasDigit:
+		return &PolicyError{Rule: RuleDigit}
+	case p.RequireSymbol && !hasSymbol:
+		return &PolicyError{Rule: RuleSymbol}
+	}
+
+	// 3. Password umum
+	lower := strings.ToLower(plain)
+	if p.RejectCommon {
+		if _, found := commonPasswords[lower]; found {
+			return &PolicyError{Rule: RuleCommon}
+		}
+	}
+
+	// 4. Mengandung username / email
+	if p.RejectPersonal {
+		for _, value := range personal {
+			if at := strings.Index(value, "@"); at >= 0 {
+				value = value[:at]
+			}
+			value = strings.ToLower(strings.TrimSpace(value))
+			if utf8.RuneCountInString(value) >= minPersonalLength && strings.Contains(lower, value) {
+				return &PolicyError{Rule: RulePersonal}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,19 @@
+package validation
+
+import (
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+)
+
+// ParseDateQuery: query param tanggal opsional, format ISO8601 / RFC3339 (kosong = nil)
+func ParseDateQuery(name string, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, apperror.BadRequest("invalid_date", name+" must be an RFC3339 timestamp")
+	}
+	return &t, nil
+}
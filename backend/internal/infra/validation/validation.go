@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/password"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
+	"github.com/gofiber/fiber/v2"
+)
+
+// FieldError: Satu field yang gagal validasi, dikirim ke client di list "errors"
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// translators: bahasa yang didukung, English sebagai fallback
+var translators = ut.New(en.New(), en.New(), id.New())
+
+// Pesan tambahan yang tidak ada di translasi bawaan validator: {0} = nama field, {1} = parameter
+var extraMessages = map[string]map[string]string{
+	"en": {
+		"invalid":                            "{0} is invalid",
+		"password." + password.RuleMinLength: "{0} must be at least {1} characters long",
+		"password." + password.RuleMaxLength: "{0} must be at most {1} bytes long",
+		"password." + password.RuleUpper:     "{0} must contain an uppercase letter",
+		"password." + password.RuleLower:     "{0} must contain a lowercase letter",
+		"password." + password.RuleDigit:     "{0} must contain a digit",
+		"password." + password.RuleSymbol:    "{0} must contain a symbol",
+		"password." + password.RuleCommon:    "{0} is too common",
+		"password." + password.RulePersonal:  "{0} must not contain your username or email",
+	},
+	"id": {
+		"invalid":                            "{0} tidak valid",
+		"password." + password.RuleMinLength: "panjang {0} minimal {1} karakter",
+		"password." + password.RuleMaxLength: "panjang {0} maksimal {1} byte",
+		"password." + password.RuleUpper:     "{0} harus mengandung huruf besar",
+		"password." + password.RuleLower:     "{0} harus mengandung huruf kecil",
+		"password." + password.RuleDigit:     "{0} harus mengandung angka",
+		"password." + password.RuleSymbol:    "{0} harus mengandung simbol",
+		"password." + password.RuleCommon:    "{0} terlalu umum dan mudah ditebak",
+		"password." + password.RulePersonal:  "{0} tidak boleh mengandung username atau email",
+	},
+}
+
+var (
+	validate     *validator.Validate
+	validateErr  error
+	validateOnce sync.Once
+)
+
+// New mengembalikan validator aplikasi. Selalu instance yang sama, karena terjemahan
+// hanya bisa didaftarkan sekali per translator (dan validator menyarankan satu instance):
+//   - nama field di error memakai tag json (atau query), bukan nama field Go
+//   - pesan error bawaan validator diterjemahkan ke English & Bahasa Indonesia
+func New() (*validator.Validate, error) {
+	validateOnce.Do(func() {
+		v := validator.New()
+		v.RegisterTagNameFunc(fieldName)
+		if validateErr = registerTranslations(v); validateErr == nil {
+			validate = v
+		}
+	})
+	return validate, validateErr
+}
+
+func registerTranslations(v *validator.Validate) error {
+	enTrans, _ := translators.GetTranslator("en")
+	if err := enTranslations.RegisterDefaultTranslations(v, enTrans); err != nil {
+		return err
+	}
+	idTrans, _ := translators.GetTranslator("id")
+	if err := idTranslations.RegisterDefaultTranslations(v, idTrans); err != nil {
+		return err
+	}
+
+	for locale, messages := range extraMessages {
+		trans, _ := translators.GetTranslator(locale)
+		for key, text := range messages {
+			if err := trans.Add(key, text, true); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// fieldName: `json:"new_password,omitempty"` => "new_password"; fallback ke tag query
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "query"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// Errors mengubah error validasi (validator.ValidationErrors / *password.PolicyError)
+// menjadi list FieldError dalam bahasa dari header Accept-Language.
+// ok=false jika err bukan error validasi.
+func Errors(c *fiber.Ctx, err error) ([]FieldError, bool) {
+	trans := Translator(c.Get(fiber.HeaderAcceptLanguage))
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			message := fe.Translate(trans)
+			if message == fe.Error() {
+				// Tag tanpa terjemahan, jangan bocorkan pesan internal validator
+				message, _ = trans.T("invalid", fe.Field())
+			}
+			fields = append(fields, FieldError{Field: fe.Field(), Rule: fe.Tag(), Message: message})
+		}
+		return fields, true
+	}
+
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		field := policyErr.Field
+		if field == "" {
+			field = "password"
+		}
+		message, tErr := trans.T("password."+policyErr.Rule, field, strconv.Itoa(policyErr.Param))
+		if tErr != nil {
+			message = policyErr.Error()
+		}
+		return []FieldError{{Field: field, Rule: policyErr.Rule, Message: message}}, true
+	}
+
+	return nil, false
+}
+
+// Translator memilih bahasa dari Accept-Language (mis. "id-ID,id;q=0.9,en;q=0.8"), fallback English
+func Translator(acceptLanguage string) ut.Translator {
+	type candidate struct {
+		tag     string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if parsed, err := strconv.ParseFloat(q, 64); err == nil {
+				quality = parsed
+			}
+		}
+		candidates = append(candidates, candidate{tag: tag, quality: quality})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+	locales := make([]string, 0, len(candidates)*2)
+	for _, cand := range candidates {
+		// "id-ID" => coba "id_id" lalu bahasa dasarnya "id"
+		base, _, _ := strings.Cut(cand.tag, "-")
+		locales = append(locales, strings.ReplaceAll(cand.tag, "-", "_"), base)
+	}
+
+	trans, _ := translators.FindTranslator(locales...)
+	return trans
+}
@@ -0,0 +1,147 @@
+package validation_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/password"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/validation"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ==========================================
+// 1. HELPER SETUP
+// ==========================================
+
+type signupRequest struct {
+	Username string `json:"username" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
+	Code     string `json:"code,omitempty" validate:"omitempty,kode"`
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+}
+
+type errorBody struct {
+	Errors []validation.FieldError `json:"errors"`
+}
+
+// respond menjalankan err lewat validation.Errors di dalam request Fiber sungguhan
+func respond(t *testing.T, err error, acceptLanguage string) (int, errorBody) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		if fields, ok := validation.Errors(c, err); ok {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"errors": fields})
+		}
+		return c.SendStatus(fiber.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	if acceptLanguage != "" {
+		req.Header.Set(fiber.HeaderAcceptLanguage, acceptLanguage)
+	}
+	resp, testErr := app.Test(req)
+	require.NoError(t, testErr)
+
+	var body errorBody
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func newValidator(t *testing.T) *validator.Validate {
+	v, err := validation.New()
+	require.NoError(t, err)
+	require.NoError(t, v.RegisterValidation("kode", func(fl validator.FieldLevel) bool { return fl.Field().String() == "ok" }))
+	return v
+}
+
+// ==========================================
+// 2. GROUP: VALIDATION ERROR TESTS
+// ==========================================
+
+func TestErrors_EnglishByDefault(t *testing.T) {
+	err := newValidator(t).Struct(&signupRequest{Email: "bukan-email"})
+
+	status, body := respond(t, err, "")
+
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, []validation.FieldError{
+		{Field: "username", Rule: "required", Message: "username is a required field"},
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+	}, body.Errors)
+}
+
+func TestErrors_IndonesianFromAcceptLanguage(t *testing.T) {
+	err := newValidator(t).Struct(&signupRequest{Email: "budi@example.com"})
+
+	_, body := respond(t, err, "id-ID,id;q=0.9,en;q=0.8")
+
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "username wajib diisi", body.Errors[0].Message)
+}
+
+func TestErrors_UsesQualityOrder(t *testing.T) {
+	err := newValidator(t).Struct(&signupRequest{Email: "budi@example.com"})
+
+	_, body := respond(t, err, "fr;q=1.0, en;q=0.2, id;q=0.7")
+
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "username wajib diisi", body.Errors[0].Message)
+}
+
+func TestErrors_QueryTagAndUntranslatedRule(t *testing.T) {
+	err := newValidator(t).Struct(&signupRequest{Username: "budi", Email: "budi@example.com", Code: "x", Page: -1})
+
+	_, body := respond(t, err, "id")
+
+	assert.Equal(t, []validation.FieldError{
+		{Field: "code", Rule: "kode", Message: "code tidak valid"},
+		{Field: "page", Rule: "min", Message: "page harus 1 atau lebih besar"},
+	}, body.Errors)
+}
+
+func TestErrors_PasswordPolicy(t *testing.T) {
+	err := &password.PolicyError{Field: "new_password", Rule: password.RuleMinLength, Param: 8}
+
+	_, en := respond(t, err, "en-US")
+	_, id := respond(t, err, "id")
+
+	assert.Equal(t, []validation.FieldError{{Field: "new_password", Rule: "min_length", Message: "new_password must be at least 8 characters long"}}, en.Errors)
+	assert.Equal(t, "panjang new_password minimal 8 karakter", id.Errors[0].Message)
+}
+
+func TestErrors_NotAValidationError(t *testing.T) {
+	status, _ := respond(t, assert.AnError, "id")
+
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+}
+
+// ==========================================
+// 3. GROUP: DATE QUERY TESTS
+// ==========================================
+
+func TestParseDateQuery_EmptyIsNil(t *testing.T) {
+	date, err := validation.ParseDateQuery("date_from", "")
+
+	assert.NoError(t, err)
+	assert.Nil(t, date)
+}
+
+func TestParseDateQuery_RFC3339(t *testing.T) {
+	date, err := validation.ParseDateQuery("date_from", "2026-01-31T17:00:00Z")
+
+	require.NoError(t, err)
+	assert.True(t, date.Equal(time.Date(2026, 1, 31, 17, 0, 0, 0, time.UTC)))
+}
+
+func TestParseDateQuery_InvalidFormat(t *testing.T) {
+	_, err := validation.ParseDateQuery("date_to", "31-01-2026")
+
+	var appErr *apperror.Error
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, "invalid_date", appErr.Code)
+}
@@ -1,10 +1,17 @@
 package infra
 
 import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/validation"
 	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
-func NewValidator(viper *viper.Viper) *validator.Validate {
-	return validator.New()
+// NewValidator: nama field di error mengikuti tag json dan pesannya bisa diterjemahkan (lihat package validation)
+func NewValidator(viper *viper.Viper, log *logrus.Logger) *validator.Validate {
+	validate, err := validation.New()
+	if err != nil {
+		log.Fatalf("Failed to register validator translations: %v", err)
+	}
+	return validate
 }
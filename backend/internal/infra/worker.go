@@ -0,0 +1,45 @@
+package infra
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Worker menjalankan sebuah task secara berkala di background sampai ctx selesai
+type Worker struct {
+	name     string
+	interval time.Duration
+	task     func(ctx context.Context) error
+	log      *logrus.Logger
+}
+
+func NewWorker(name string, interval time.Duration, task func(ctx context.Context) error, log *logrus.Logger) *Worker {
+	return &Worker{
+		name:     name,
+		interval: interval,
+		task:     task,
+		log:      log,
+	}
+}
+
+// Run memblokir; panggil dengan `go worker.Run(ctx)`
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	w.log.Infof("Worker %s started (interval %s)", w.name, w.interval)
+	for {
+		if err := w.task(ctx); err != nil {
+			w.log.WithError(err).Errorf("Worker %s failed", w.name)
+		}
+
+		select {
+		case <-ctx.Done():
+			w.log.Infof("Worker %s stopped", w.name)
+			return
+		case <-ticker.C:
+		}
+	}
+}
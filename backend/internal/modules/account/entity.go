@@ -0,0 +1,133 @@
+package account
+
+import "time"
+
+// Jenis akun yang didukung (harus sama dengan CHECK di migrasi create_accounts)
+const (
+	TypeBank       = "bank"
+	TypeCash       = "cash"
+	TypeEWallet    = "e_wallet"
+	TypeCreditCard = "credit_card"
+)
+
+// DefaultAccountName: akun cash default, harus sama dengan seed di migrasi create_accounts (untuk user lama)
+const DefaultAccountName = "Cash"
+
+// Account: Sumber / tujuan dana. Kartu kredit bersaldo negatif saat ada tagihan.
+// Balance diisi saat dibaca: opening_balance + pemasukan - pengeluaran + transfer masuk - transfer keluar.
+type Account struct {
+	ID             string
+	UserID         string
+	Name           string
+	Type           string
+	OpeningBalance float64
+	IsDefault      bool
+	CreatedAt      time.Time
+	Balance        float64
+}
+
+// AccountResponse: Format standar data akun untuk output JSON
+type AccountResponse struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	OpeningBalance float64   `json:"opening_balance"`
+	Balance        float64   `json:"balance"`
+	IsDefault      bool      `json:"is_default"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Transfer: Pindah dana antar akun milik user yang sama; tidak dihitung sebagai pengeluaran
+type Transfer struct {
+	ID            string
+	UserID        string
+	FromAccountID string
+	ToAccountID   string
+	Amount        float64
+	Date          time.Time
+	Note          *string
+	CreatedAt     time.Time
+}
+
+// TransferResponse: Format standar data transfer untuk output JSON
+type TransferResponse struct {
+	ID            string    `json:"id"`
+	FromAccountID string    `json:"from_account_id"`
+	ToAccountID   string    `json:"to_account_id"`
+	Amount        float64   `json:"amount"`
+	Date          time.Time `json:"date"`
+	Note          *string   `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Jenis baris di ledger akun
+const (
+	EntryIncome      = "income"
+	EntryExpense     = "expense"
+	EntryTransferIn  = "transfer_in"
+	EntryTransferOut = "transfer_out"
+)
+
+// LedgerEntry: Satu mutasi akun beserta saldo berjalan setelah mutasi itu.
+// Amount bertanda: positif = dana masuk, negatif = dana keluar.
+type LedgerEntry struct {
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"`
+	Amount      float64   `json:"amount"`
+	Date        time.Time `json:"date"`
+	Description *string   `json:"description"`
+	Balance     float64   `json:"balance"`
+}
+
+// CreateAccountRequest: opening_balance boleh negatif (mis. tagihan kartu kredit yang sudah ada)
+type CreateAccountRequest struct {
+	Name           string  `json:"name" validate:"required,max=50"`
+	Type           string  `json:"type" validate:"required,oneof=bank cash e_wallet credit_card"`
+	OpeningBalance float64 `json:"opening_balance"`
+}
+
+// UpdateAccountRequest: Semua field opsional (PATCH)
+type UpdateAccountRequest struct {
+	Name           *string  `json:"name" validate:"omitempty,min=1,max=50"`
+	Type           *string  `json:"type" validate:"omitempty,oneof=bank cash e_wallet credit_card"`
+	OpeningBalance *float64 `json:"opening_balance"`
+}
+
+// CreateTransferRequest: Akun asal dan tujuan harus berbeda
+type CreateTransferRequest struct {
+	FromAccountID string     `json:"from_account_id" validate:"required,uuid"`
+	ToAccountID   string     `json:"to_account_id" validate:"required,uuid,nefield=FromAccountID"`
+	Amount        float64    `json:"amount" validate:"required,gt=0"`
+	Date          *time.Time `json:"date" validate:"required"`
+	Note          *string    `json:"note" validate:"omitempty,max=255"`
+}
+
+// ListRequest: Filter rentang tanggal (opsional) untuk ledger dan daftar transfer
+type ListRequest struct {
+	DateFrom *time.Time
+	DateTo   *time.Time
+}
+
+func toResponse(a *Account) *AccountResponse {
+	return &AccountResponse{
+		ID:             a.ID,
+		Name:           a.Name,
+		Type:           a.Type,
+		OpeningBalance: a.OpeningBalance,
+		Balance:        a.Balance,
+		IsDefault:      a.IsDefault,
+		CreatedAt:      a.CreatedAt,
+	}
+}
+
+func toTransferResponse(t *Transfer) *TransferResponse {
+	return &TransferResponse{
+		ID:            t.ID,
+		FromAccountID: t.FromAccountID,
+		ToAccountID:   t.ToAccountID,
+		Amount:        t.Amount,
+		Date:          t.Date,
+		Note:          t.Note,
+		CreatedAt:     t.CreatedAt,
+	}
+}
@@ -0,0 +1,195 @@
+package account
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	useCase UseCase
+}
+
+func NewHandler(useCase UseCase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req CreateAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Create(c.Context(), userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.List(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.Get(c.Context(), userID, c.Params("account_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req UpdateAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Update(c.Context(), userID, c.Params("account_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.Delete(c.Context(), userID, c.Params("account_id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) Ledger(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	req, err := parseListRequest(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.useCase.Ledger(c.Context(), userID, c.Params("account_id"), req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) CreateTransfer(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req CreateTransferRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.CreateTransfer(c.Context(), userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) ListTransfers(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	req, err := parseListRequest(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.useCase.ListTransfers(c.Context(), userID, req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) DeleteTransfer(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.DeleteTransfer(c.Context(), userID, c.Params("transfer_id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+// parseListRequest: Query param date_from & date_to (format ISO8601 / RFC3339)
+func parseListRequest(c *fiber.Ctx) (*ListRequest, error) {
+	var req ListRequest
+	var err error
+	if req.DateFrom, err = validation.ParseDateQuery("date_from", c.Query("date_from")); err != nil {
+		return nil, err
+	}
+	if req.DateTo, err = validation.ParseDateQuery("date_to", c.Query("date_to")); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler) {
+	accounts := app.Group("/api/accounts")
+
+	accounts.Post("/", authMiddleware, h.Create)
+	accounts.Get("/", authMiddleware, h.List)
+	accounts.Get("/:account_id", authMiddleware, h.Get)
+	accounts.Patch("/:account_id", authMiddleware, h.Update)
+	accounts.Delete("/:account_id", authMiddleware, h.Delete)
+	accounts.Get("/:account_id/ledger", authMiddleware, h.Ledger)
+
+	transfers := app.Group("/api/transfers")
+
+	transfers.Post("/", authMiddleware, h.CreateTransfer)
+	transfers.Get("/", authMiddleware, h.ListTransfers)
+	transfers.Delete("/:transfer_id", authMiddleware, h.DeleteTransfer)
+}
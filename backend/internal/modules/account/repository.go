@@ -0,0 +1,267 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrAccountNotFound  = apperror.NotFound("account_not_found", "account not found")
+	ErrAccountNameTaken = apperror.Conflict("account_name_taken", "account name already used")
+	ErrAccountInUse     = apperror.Conflict("account_in_use", "account still has transactions or transfers")
+	ErrTransferNotFound = apperror.NotFound("transfer_not_found", "transfer not found")
+)
+
+// Repository: Semua query WAJIB di-scope ke user_id pemilik akun
+type Repository interface {
+	Save(ctx context.Context, account *Account) error
+	FindByID(ctx context.Context, id string, userID string) (*Account, error)
+	FindAllByUserID(ctx context.Context, userID string) ([]Account, error)
+	Update(ctx context.Context, account *Account) error
+	// Delete: ErrAccountInUse jika masih ada history, pemasukan atau transfer di akun ini
+	Delete(ctx context.Context, id string, userID string) error
+	// EnsureDefault mengembalikan akun default user, dibuat dulu jika belum ada
+	EnsureDefault(ctx context.Context, userID string) (*Account, error)
+
+	SaveTransfer(ctx context.Context, transfer *Transfer) error
+	FindTransfers(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]Transfer, error)
+	DeleteTransfer(ctx context.Context, id string, userID string) error
+
+	// Ledger: semua mutasi akun urut tanggal, saldo berjalan dihitung dari opening_balance.
+	// Filter tanggal hanya membatasi baris yang dikembalikan, bukan perhitungan saldo.
+	Ledger(ctx context.Context, accountID string, dateFrom, dateTo *time.Time) ([]LedgerEntry, error)
+}
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &repository{db: db}
+}
+
+// accountSelect: Urutan kolom harus sama dengan urutan scanAccount; saldo dihitung dari semua mutasi
+const accountSelect = `
+	SELECT a.id, a.user_id, a.name, a.type, a.opening_balance, a.is_default, a.created_at,
+		a.opening_balance
+		+ COALESCE((SELECT SUM(amount) FROM incomes WHERE account_id = a.id), 0)
+		- COALESCE((SELECT SUM(amount) FROM histories WHERE account_id = a.id), 0)
+		+ COALESCE((SELECT SUM(amount) FROM account_transfers WHERE to_account_id = a.id), 0)
+		- COALESCE((SELECT SUM(amount) FROM account_transfers WHERE from_account_id = a.id), 0)
+	FROM accounts a`
+
+func scanAccount(row pgx.Row, a *Account) error {
+	return row.Scan(&a.ID, &a.UserID, &a.Name, &a.Type, &a.OpeningBalance, &a.IsDefault, &a.CreatedAt, &a.Balance)
+}
+
+const transferColumns = `id, user_id, from_account_id, to_account_id, amount, date, note, created_at`
+
+func (r *repository) Save(ctx context.Context, account *Account) error {
+	query := `
+		INSERT INTO accounts (id, user_id, name, type, opening_balance, is_default, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(ctx, query,
+		account.ID, account.UserID, account.Name, account.Type, account.OpeningBalance, account.IsDefault, account.CreatedAt,
+	)
+	return mapUniqueViolation(err)
+}
+
+func (r *repository) FindByID(ctx context.Context, id string, userID string) (*Account, error) {
+	return r.findOne(ctx, accountSelect+` WHERE a.id = $1 AND a.user_id = $2`, id, userID)
+}
+
+func (r *repository) FindAllByUserID(ctx context.Context, userID string) ([]Account, error) {
+	// Default dulu, lalu urut nama
+	query := accountSelect + ` WHERE a.user_id = $1 ORDER BY a.is_default DESC, lower(a.name)`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := make([]Account, 0)
+	for rows.Next() {
+		var account Account
+		if err := scanAccount(rows, &account); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+func (r *repository) Update(ctx context.Context, account *Account) error {
+	query := `UPDATE accounts SET name = $1, type = $2, opening_balance = $3 WHERE id = $4 AND user_id = $5`
+
+	tag, err := r.db.Exec(ctx, query, account.Name, account.Type, account.OpeningBalance, account.ID, account.UserID)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAccountNotFound
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string, userID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM accounts WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		// 23503 foreign_key_violation: masih direferensikan histories / incomes / transfers
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrAccountInUse
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAccountNotFound
+	}
+	return nil
+}
+
+func (r *repository) EnsureDefault(ctx context.Context, userID string) (*Account, error) {
+	query := accountSelect + ` WHERE a.user_id = $1 AND a.is_default`
+
+	account, err := r.findOne(ctx, query, userID)
+	if err != nil || account != nil {
+		return account, err
+	}
+
+	// Request paralel aman: unique index accounts_user_default membuat insert kedua diabaikan
+	insert := `
+		INSERT INTO accounts (id, user_id, name, type, opening_balance, is_default, created_at)
+		VALUES ($1, $2, $3, $4, 0, TRUE, $5)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := r.db.Exec(ctx, insert, uuid.New().String(), userID, DefaultAccountName, TypeCash, time.Now()); err != nil {
+		return nil, err
+	}
+
+	account, err = r.findOne(ctx, query, userID)
+	if err == nil && account == nil {
+		// Nama "Cash" sudah dipakai akun lain sehingga default tidak bisa dibuat
+		return nil, ErrAccountNameTaken
+	}
+	return account, err
+}
+
+func (r *repository) SaveTransfer(ctx context.Context, transfer *Transfer) error {
+	query := `
+		INSERT INTO account_transfers (` + transferColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(ctx, query,
+		transfer.ID, transfer.UserID, transfer.FromAccountID, transfer.ToAccountID,
+		transfer.Amount, transfer.Date, transfer.Note, transfer.CreatedAt,
+	)
+	return err
+}
+
+func (r *repository) FindTransfers(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]Transfer, error) {
+	// Filter tanggal opsional: NULL berarti tidak dibatasi
+	query := `
+		SELECT ` + transferColumns + ` FROM account_transfers
+		WHERE user_id = $1
+		  AND ($2::timestamptz IS NULL OR date >= $2)
+		  AND ($3::timestamptz IS NULL OR date <= $3)
+		ORDER BY date DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	transfers := make([]Transfer, 0)
+	for rows.Next() {
+		var t Transfer
+		if err := rows.Scan(&t.ID, &t.UserID, &t.FromAccountID, &t.ToAccountID, &t.Amount, &t.Date, &t.Note, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+	return transfers, rows.Err()
+}
+
+func (r *repository) DeleteTransfer(ctx context.Context, id string, userID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM account_transfers WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTransferNotFound
+	}
+	return nil
+}
+
+func (r *repository) Ledger(ctx context.Context, accountID string, dateFrom, dateTo *time.Time) ([]LedgerEntry, error) {
+	// Saldo berjalan dihitung di subquery sebelum filter tanggal supaya tetap memperhitungkan mutasi sebelumnya
+	query := `
+		SELECT id, kind, amount, date, description, balance FROM (
+			SELECT e.id, e.kind, e.amount, e.date, e.description, e.created_at,
+				a.opening_balance + SUM(e.amount) OVER (ORDER BY e.date, e.created_at, e.id) AS balance
+			FROM (
+				SELECT id, 'income' AS kind, amount, date, description, created_at
+				FROM incomes WHERE account_id = $1
+				UNION ALL
+				SELECT h.id, 'expense', -h.amount, h.date, c.name, h.created_at
+				FROM histories h LEFT JOIN categories c ON c.id = h.category_id
+				WHERE h.account_id = $1
+				UNION ALL
+				SELECT id, 'transfer_in', amount, date, note, created_at
+				FROM account_transfers WHERE to_account_id = $1
+				UNION ALL
+				SELECT id, 'transfer_out', -amount, date, note, created_at
+				FROM account_transfers WHERE from_account_id = $1
+			) e
+			JOIN accounts a ON a.id = $1
+		) ledger
+		WHERE ($2::timestamptz IS NULL OR date >= $2)
+		  AND ($3::timestamptz IS NULL OR date <= $3)
+		ORDER BY date, created_at, id
+	`
+	rows, err := r.db.Query(ctx, query, accountID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]LedgerEntry, 0)
+	for rows.Next() {
+		var e LedgerEntry
+		if err := rows.Scan(&e.ID, &e.Kind, &e.Amount, &e.Date, &e.Description, &e.Balance); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+func (r *repository) findOne(ctx context.Context, query string, args ...any) (*Account, error) {
+	var account Account
+	if err := scanAccount(r.db.QueryRow(ctx, query, args...), &account); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &account, nil
+}
+
+// mapUniqueViolation: nama akun bentrok dengan akun lain milik user yang sama
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrAccountNameTaken
+	}
+	return err
+}
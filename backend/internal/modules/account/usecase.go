@@ -0,0 +1,291 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInternalServer = apperror.ErrInternal
+	ErrInvalidDate    = apperror.BadRequest("invalid_date_range", "date_from must be before date_to")
+	ErrDefaultAccount = apperror.Conflict("default_account", "the default account cannot be deleted")
+)
+
+type UseCase interface {
+	Create(ctx context.Context, userID string, req *CreateAccountRequest) (*AccountResponse, error)
+	List(ctx context.Context, userID string) ([]AccountResponse, error)
+	Get(ctx context.Context, userID string, accountID string) (*AccountResponse, error)
+	Update(ctx context.Context, userID string, accountID string, req *UpdateAccountRequest) (*AccountResponse, error)
+	Delete(ctx context.Context, userID string, accountID string) error
+	// Ledger: mutasi akun dengan saldo berjalan
+	Ledger(ctx context.Context, userID string, accountID string, req *ListRequest) ([]LedgerEntry, error)
+
+	// Transfer antar akun tidak dihitung sebagai pengeluaran maupun pemasukan
+	CreateTransfer(ctx context.Context, userID string, req *CreateTransferRequest) (*TransferResponse, error)
+	ListTransfers(ctx context.Context, userID string, req *ListRequest) ([]TransferResponse, error)
+	DeleteTransfer(ctx context.Context, userID string, transferID string) error
+}
+
+type useCase struct {
+	repo     Repository
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewUseCase(repo Repository, log *logrus.Logger, validate *validator.Validate) UseCase {
+	return &useCase{
+		repo:     repo,
+		log:      log,
+		validate: validate,
+	}
+}
+
+func (u *useCase) Create(ctx context.Context, userID string, req *CreateAccountRequest) (*AccountResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Akun default dibuat lebih dulu supaya namanya tidak diambil akun baru
+	if _, err := u.repo.EnsureDefault(ctx, userID); err != nil {
+		u.log.WithError(err).Error("Failed to ensure default account")
+		return nil, ErrInternalServer
+	}
+
+	// 3. Construct Entity (user_id selalu dari token, bukan dari body)
+	newAccount := &Account{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		Name:           strings.TrimSpace(req.Name),
+		Type:           req.Type,
+		OpeningBalance: req.OpeningBalance,
+		CreatedAt:      time.Now(),
+	}
+	newAccount.Balance = newAccount.OpeningBalance
+
+	// 4. Simpan ke DB
+	if err := u.repo.Save(ctx, newAccount); err != nil {
+		if errors.Is(err, ErrAccountNameTaken) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to save account")
+		return nil, ErrInternalServer
+	}
+
+	return toResponse(newAccount), nil
+}
+
+func (u *useCase) List(ctx context.Context, userID string) ([]AccountResponse, error) {
+	// User lama / baru selalu melihat akun default-nya
+	if _, err := u.repo.EnsureDefault(ctx, userID); err != nil {
+		u.log.WithError(err).Error("Failed to ensure default account")
+		return nil, ErrInternalServer
+	}
+
+	accounts, err := u.repo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list accounts")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]AccountResponse, 0, len(accounts))
+	for i := range accounts {
+		resp = append(resp, *toResponse(&accounts[i]))
+	}
+	return resp, nil
+}
+
+func (u *useCase) Get(ctx context.Context, userID string, accountID string) (*AccountResponse, error) {
+	account, err := u.findOwned(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(account), nil
+}
+
+func (u *useCase) Update(ctx context.Context, userID string, accountID string, req *UpdateAccountRequest) (*AccountResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan akun milik user
+	account, err := u.findOwned(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Terapkan perubahan parsial (saldo bergeser sebesar perubahan opening_balance)
+	if req.Name != nil {
+		account.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Type != nil {
+		account.Type = *req.Type
+	}
+	if req.OpeningBalance != nil {
+		account.Balance += *req.OpeningBalance - account.OpeningBalance
+		account.OpeningBalance = *req.OpeningBalance
+	}
+
+	// 4. Simpan perubahan
+	if err := u.repo.Update(ctx, account); err != nil {
+		if errors.Is(err, ErrAccountNotFound) || errors.Is(err, ErrAccountNameTaken) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to update account")
+		return nil, ErrInternalServer
+	}
+
+	return toResponse(account), nil
+}
+
+func (u *useCase) Delete(ctx context.Context, userID string, accountID string) error {
+	account, err := u.findOwned(ctx, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if account.IsDefault {
+		return ErrDefaultAccount
+	}
+
+	// Akun yang masih punya transaksi ditolak oleh FK (ErrAccountInUse)
+	if err := u.repo.Delete(ctx, account.ID, userID); err != nil {
+		if errors.Is(err, ErrAccountNotFound) || errors.Is(err, ErrAccountInUse) {
+			return err
+		}
+		u.log.WithError(err).Error("Failed to delete account")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+func (u *useCase) Ledger(ctx context.Context, userID string, accountID string, req *ListRequest) ([]LedgerEntry, error) {
+	if req.DateFrom != nil && req.DateTo != nil && req.DateFrom.After(*req.DateTo) {
+		return nil, ErrInvalidDate
+	}
+
+	account, err := u.findOwned(ctx, userID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := u.repo.Ledger(ctx, account.ID, req.DateFrom, req.DateTo)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to load account ledger")
+		return nil, ErrInternalServer
+	}
+	return entries, nil
+}
+
+func (u *useCase) CreateTransfer(ctx context.Context, userID string, req *CreateTransferRequest) (*TransferResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Kedua akun harus milik user
+	if _, err := u.findOwned(ctx, userID, req.FromAccountID); err != nil {
+		return nil, err
+	}
+	if _, err := u.findOwned(ctx, userID, req.ToAccountID); err != nil {
+		return nil, err
+	}
+
+	// 3. Construct Entity & simpan
+	transfer := &Transfer{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		Date:          *req.Date,
+		Note:          req.Note,
+		CreatedAt:     time.Now(),
+	}
+	if err := u.repo.SaveTransfer(ctx, transfer); err != nil {
+		u.log.WithError(err).Error("Failed to save transfer")
+		return nil, ErrInternalServer
+	}
+
+	return toTransferResponse(transfer), nil
+}
+
+func (u *useCase) ListTransfers(ctx context.Context, userID string, req *ListRequest) ([]TransferResponse, error) {
+	if req.DateFrom != nil && req.DateTo != nil && req.DateFrom.After(*req.DateTo) {
+		return nil, ErrInvalidDate
+	}
+
+	transfers, err := u.repo.FindTransfers(ctx, userID, req.DateFrom, req.DateTo)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list transfers")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]TransferResponse, 0, len(transfers))
+	for i := range transfers {
+		resp = append(resp, *toTransferResponse(&transfers[i]))
+	}
+	return resp, nil
+}
+
+func (u *useCase) DeleteTransfer(ctx context.Context, userID string, transferID string) error {
+	if _, err := uuid.Parse(transferID); err != nil {
+		return ErrTransferNotFound
+	}
+
+	if err := u.repo.DeleteTransfer(ctx, transferID, userID); err != nil {
+		if errors.Is(err, ErrTransferNotFound) {
+			return err
+		}
+		u.log.WithError(err).Error("Failed to delete transfer")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// findOwned mengambil akun berdasarkan ID dan memastikan pemiliknya adalah userID
+func (u *useCase) findOwned(ctx context.Context, userID string, accountID string) (*Account, error) {
+	// ID bukan UUID pasti tidak ada (hindari error cast dari Postgres)
+	if _, err := uuid.Parse(accountID); err != nil {
+		return nil, ErrAccountNotFound
+	}
+
+	account, err := u.repo.FindByID(ctx, accountID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find account")
+		return nil, ErrInternalServer
+	}
+	if account == nil {
+		return nil, ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// Resolve menentukan akun transaksi milik module lain (history, pemasukan, template berulang):
+// akun yang diisi harus milik user (ErrAccountNotFound), kosong berarti akun default user.
+// Error repository dikembalikan apa adanya agar pemanggil yang mencatat log-nya.
+func Resolve(ctx context.Context, repo Repository, userID string, accountID *string) (string, error) {
+	if accountID == nil {
+		def, err := repo.EnsureDefault(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		return def.ID, nil
+	}
+
+	owned, err := repo.FindByID(ctx, *accountID, userID)
+	if err != nil {
+		return "", err
+	}
+	if owned == nil {
+		return "", ErrAccountNotFound
+	}
+	return owned.ID, nil
+}
@@ -0,0 +1,318 @@
+package account_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/account"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==========================================
+// 1. MOCK OBJECTS
+// ==========================================
+
+// MockRepository memalsukan behavior account.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, a *account.Account) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id string, userID string) (*account.Account, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*account.Account), args.Error(1)
+}
+
+func (m *MockRepository) FindAllByUserID(ctx context.Context, userID string) ([]account.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.Account), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, a *account.Account) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) EnsureDefault(ctx context.Context, userID string) (*account.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*account.Account), args.Error(1)
+}
+
+func (m *MockRepository) SaveTransfer(ctx context.Context, t *account.Transfer) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindTransfers(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]account.Transfer, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.Transfer), args.Error(1)
+}
+
+func (m *MockRepository) DeleteTransfer(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Ledger(ctx context.Context, accountID string, dateFrom, dateTo *time.Time) ([]account.LedgerEntry, error) {
+	args := m.Called(ctx, accountID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.LedgerEntry), args.Error(1)
+}
+
+// ==========================================
+// 2. HELPER SETUP
+// ==========================================
+
+const (
+	ownerID          = "11111111-1111-1111-1111-111111111111"
+	defaultAccountID = "22222222-2222-2222-2222-222222222222"
+	bankAccountID    = "33333333-3333-3333-3333-333333333333"
+	transferID       = "44444444-4444-4444-4444-444444444444"
+)
+
+func setupTest() (account.UseCase, *MockRepository) {
+	mockRepo := new(MockRepository)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return account.NewUseCase(mockRepo, log, validator.New()), mockRepo
+}
+
+func defaultAccount() *account.Account {
+	return &account.Account{ID: defaultAccountID, UserID: ownerID, Name: account.DefaultAccountName, Type: account.TypeCash, IsDefault: true}
+}
+
+func bankAccount() *account.Account {
+	return &account.Account{ID: bankAccountID, UserID: ownerID, Name: "BCA", Type: account.TypeBank, OpeningBalance: 1000, Balance: 1500}
+}
+
+// ==========================================
+// 3. GROUP: ACCOUNT TESTS
+// ==========================================
+
+func TestCreate_EnsuresDefaultFirst(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("EnsureDefault", mock.Anything, ownerID).Return(defaultAccount(), nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(a *account.Account) bool {
+		return a.UserID == ownerID && a.Name == "BCA" && !a.IsDefault
+	})).Return(nil)
+
+	resp, err := u.Create(context.Background(), ownerID, &account.CreateAccountRequest{Name: "  BCA ", Type: account.TypeBank, OpeningBalance: 250})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "BCA", resp.Name)
+	assert.Equal(t, 250.0, resp.Balance)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreate_UnknownType(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	resp, err := u.Create(context.Background(), ownerID, &account.CreateAccountRequest{Name: "Crypto", Type: "crypto"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_NameTaken(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("EnsureDefault", mock.Anything, ownerID).Return(defaultAccount(), nil)
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(account.ErrAccountNameTaken)
+
+	resp, err := u.Create(context.Background(), ownerID, &account.CreateAccountRequest{Name: "cash", Type: account.TypeCash})
+
+	assert.Equal(t, account.ErrAccountNameTaken, err)
+	assert.Nil(t, resp)
+}
+
+func TestUpdate_OpeningBalanceShiftsBalance(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	opening := 1200.0
+	mockRepo.On("FindByID", mock.Anything, bankAccountID, ownerID).Return(bankAccount(), nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := u.Update(context.Background(), ownerID, bankAccountID, &account.UpdateAccountRequest{OpeningBalance: &opening})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1200.0, resp.OpeningBalance)
+	assert.Equal(t, 1700.0, resp.Balance)
+}
+
+func TestDelete_DefaultAccountRejected(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, defaultAccountID, ownerID).Return(defaultAccount(), nil)
+
+	err := u.Delete(context.Background(), ownerID, defaultAccountID)
+
+	assert.Equal(t, account.ErrDefaultAccount, err)
+	mockRepo.AssertNotCalled(t, "Delete")
+}
+
+func TestDelete_AccountInUse(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, bankAccountID, ownerID).Return(bankAccount(), nil)
+	mockRepo.On("Delete", mock.Anything, bankAccountID, ownerID).Return(account.ErrAccountInUse)
+
+	err := u.Delete(context.Background(), ownerID, bankAccountID)
+
+	assert.Equal(t, account.ErrAccountInUse, err)
+}
+
+func TestLedger_InvalidRange(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	from := time.Now()
+	to := from.AddDate(0, 0, -1)
+	entries, err := u.Ledger(context.Background(), ownerID, bankAccountID, &account.ListRequest{DateFrom: &from, DateTo: &to})
+
+	assert.Equal(t, account.ErrInvalidDate, err)
+	assert.Nil(t, entries)
+	mockRepo.AssertNotCalled(t, "Ledger")
+}
+
+func TestLedger_AccountOfOtherUser(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, bankAccountID, ownerID).Return(nil, nil)
+
+	entries, err := u.Ledger(context.Background(), ownerID, bankAccountID, &account.ListRequest{})
+
+	assert.Equal(t, account.ErrAccountNotFound, err)
+	assert.Nil(t, entries)
+	mockRepo.AssertNotCalled(t, "Ledger")
+}
+
+// ==========================================
+// 4. GROUP: TRANSFER TESTS
+// ==========================================
+
+func TestCreateTransfer_Success(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	date := time.Now()
+	mockRepo.On("FindByID", mock.Anything, bankAccountID, ownerID).Return(bankAccount(), nil)
+	mockRepo.On("FindByID", mock.Anything, defaultAccountID, ownerID).Return(defaultAccount(), nil)
+	mockRepo.On("SaveTransfer", mock.Anything, mock.MatchedBy(func(tr *account.Transfer) bool {
+		return tr.UserID == ownerID && tr.FromAccountID == bankAccountID && tr.ToAccountID == defaultAccountID
+	})).Return(nil)
+
+	resp, err := u.CreateTransfer(context.Background(), ownerID, &account.CreateTransferRequest{
+		FromAccountID: bankAccountID, ToAccountID: defaultAccountID, Amount: 300, Date: &date,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 300.0, resp.Amount)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreateTransfer_SameAccount(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	date := time.Now()
+	resp, err := u.CreateTransfer(context.Background(), ownerID, &account.CreateTransferRequest{
+		FromAccountID: bankAccountID, ToAccountID: bankAccountID, Amount: 300, Date: &date,
+	})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "SaveTransfer")
+}
+
+func TestCreateTransfer_DestinationNotOwned(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	date := time.Now()
+	mockRepo.On("FindByID", mock.Anything, bankAccountID, ownerID).Return(bankAccount(), nil)
+	mockRepo.On("FindByID", mock.Anything, defaultAccountID, ownerID).Return(nil, nil)
+
+	resp, err := u.CreateTransfer(context.Background(), ownerID, &account.CreateTransferRequest{
+		FromAccountID: bankAccountID, ToAccountID: defaultAccountID, Amount: 300, Date: &date,
+	})
+
+	assert.Equal(t, account.ErrAccountNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "SaveTransfer")
+}
+
+func TestDeleteTransfer_RepositoryError(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("DeleteTransfer", mock.Anything, transferID, ownerID).Return(errors.New("db down"))
+
+	err := u.DeleteTransfer(context.Background(), ownerID, transferID)
+
+	assert.Equal(t, account.ErrInternalServer, err)
+}
+
+// ==========================================
+// 5. GROUP: RESOLVE TESTS
+// ==========================================
+
+func TestResolve_EmptyUsesDefault(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("EnsureDefault", mock.Anything, ownerID).Return(defaultAccount(), nil)
+
+	id, err := account.Resolve(context.Background(), mockRepo, ownerID, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaultAccountID, id)
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolve_AccountOfOtherUser(t *testing.T) {
+	mockRepo := new(MockRepository)
+	accountID := bankAccountID
+	mockRepo.On("FindByID", mock.Anything, accountID, ownerID).Return(nil, nil)
+
+	id, err := account.Resolve(context.Background(), mockRepo, ownerID, &accountID)
+
+	assert.Empty(t, id)
+	assert.Equal(t, account.ErrAccountNotFound, err)
+}
+
+func TestResolve_RepositoryErrorReturnedAsIs(t *testing.T) {
+	mockRepo := new(MockRepository)
+	accountID := bankAccountID
+	dbErr := errors.New("db down")
+	mockRepo.On("FindByID", mock.Anything, accountID, ownerID).Return(nil, dbErr)
+
+	_, err := account.Resolve(context.Background(), mockRepo, ownerID, &accountID)
+
+	assert.Equal(t, dbErr, err)
+}
@@ -0,0 +1,58 @@
+package apitoken
+
+import "time"
+
+// Scope token: full = semua endpoint, read = hanya request baca (GET/HEAD)
+const (
+	ScopeFull = "full"
+	ScopeRead = "read"
+)
+
+type APIToken struct {
+	ID         string
+	UserID     string
+	Name       string
+	Prefix     string
+	TokenHash  string
+	Scope      string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// APITokenResponse: Data token untuk output JSON (tanpa hash)
+type APITokenResponse struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scope      string     `json:"scope"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreatedAPITokenResponse: Token plaintext hanya ditampilkan SEKALI saat dibuat
+type CreatedAPITokenResponse struct {
+	APITokenResponse
+	Token string `json:"token"`
+}
+
+// CreateAPITokenRequest: Scope default full, expires_at opsional (tanpa kadaluarsa)
+type CreateAPITokenRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scope     string     `json:"scope" validate:"omitempty,oneof=full read"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func toResponse(t *APIToken) *APITokenResponse {
+	return &APITokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scope:      t.Scope,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
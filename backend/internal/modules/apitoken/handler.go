@@ -0,0 +1,73 @@
+package apitoken
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	useCase UseCase
+}
+
+func NewHandler(useCase UseCase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+	// Token yang bocor tidak boleh dipakai untuk menerbitkan token baru
+	if principal.AuthMethod == middleware.AuthMethodAPIToken {
+		return ErrLoginRequired
+	}
+
+	var req CreateAPITokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Create(c.Context(), principal.UserID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.List(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Revoke(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.Revoke(c.Context(), userID, c.Params("token_id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler) {
+	api := app.Group("/api/users/current/tokens")
+
+	api.Post("/", authMiddleware, h.Create)
+	api.Get("/", authMiddleware, h.List)
+	api.Delete("/:token_id", authMiddleware, h.Revoke)
+}
@@ -0,0 +1,136 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrAPITokenNotFound = apperror.NotFound("api_token_not_found", "api token not found")
+)
+
+// Repository: Query list/revoke WAJIB di-scope ke user_id pemilik token
+type Repository interface {
+	Save(ctx context.Context, token *APIToken) error
+	FindActiveByHash(ctx context.Context, tokenHash string) (*APIToken, error)
+	FindAllByUserID(ctx context.Context, userID string) ([]APIToken, error)
+	Revoke(ctx context.Context, id string, userID string) error
+	RevokeAllByUserID(ctx context.Context, userID string) error
+	TouchLastUsed(ctx context.Context, id string) error
+}
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &repository{db: db}
+}
+
+const tokenColumns = `t.id, t.user_id, t.name, t.prefix, t.token_hash, t.scope, t.expires_at, t.last_used_at, t.revoked_at, t.created_at`
+
+func scanToken(row pgx.Row) (*APIToken, error) {
+	var token APIToken
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.Name, &token.Prefix, &token.TokenHash, &token.Scope,
+		&token.ExpiresAt, &token.LastUsedAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *repository) Save(ctx context.Context, token *APIToken) error {
+	query := `
+		INSERT INTO api_tokens (id, user_id, name, prefix, token_hash, scope, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(ctx, query,
+		token.ID, token.UserID, token.Name, token.Prefix, token.TokenHash, token.Scope, token.ExpiresAt, token.CreatedAt,
+	)
+	return err
+}
+
+// FindActiveByHash: hanya token yang belum dicabut, belum kadaluarsa, dan pemiliknya masih aktif
+// (termasuk tidak sedang diwajibkan reset password oleh admin)
+func (r *repository) FindActiveByHash(ctx context.Context, tokenHash string) (*APIToken, error) {
+	query := `
+		SELECT ` + tokenColumns + ` FROM api_tokens t
+		JOIN users u ON u.id = t.user_id
+		WHERE t.token_hash = $1
+		  AND t.revoked_at IS NULL
+		  AND (t.expires_at IS NULL OR t.expires_at > NOW())
+		  AND u.deleted_at IS NULL
+		  AND u.disabled_at IS NULL
+		  AND u.password_reset_required = FALSE
+	`
+	token, err := scanToken(r.db.QueryRow(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// FindAllByUserID: token yang sudah dicabut tidak ditampilkan lagi
+func (r *repository) FindAllByUserID(ctx context.Context, userID string) ([]APIToken, error) {
+	query := `
+		SELECT ` + tokenColumns + ` FROM api_tokens t
+		WHERE t.user_id = $1 AND t.revoked_at IS NULL
+		ORDER BY t.created_at DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := make([]APIToken, 0)
+	for rows.Next() {
+		token, err := scanToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *repository) Revoke(ctx context.Context, id string, userID string) error {
+	query := `UPDATE api_tokens SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAPITokenNotFound
+	}
+	return nil
+}
+
+// RevokeAllByUserID dipanggil module user saat logout semua device, ganti/reset password,
+// dan tindakan admin terhadap akun
+func (r *repository) RevokeAllByUserID(ctx context.Context, userID string) error {
+	query := `UPDATE api_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
+
+// TouchLastUsed mencatat waktu pemakaian terakhir, maksimal sekali per menit per token
+// supaya tidak ada write ke DB di setiap request.
+func (r *repository) TouchLastUsed(ctx context.Context, id string) error {
+	query := `
+		UPDATE api_tokens SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+	`
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
@@ -0,0 +1,164 @@
+package apitoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInternalServer = apperror.ErrInternal
+	ErrInvalidExpiry  = apperror.BadRequest("invalid_expiry", "expires_at must be in the future")
+	ErrLoginRequired  = apperror.Forbidden("login_required", "api tokens can only be created from a login session")
+)
+
+// TokenPrefix menandai personal access token, diteruskan ke AuthMiddleware
+// (AuthOptions.APITokenPrefix) untuk membedakannya dari JWT.
+const TokenPrefix = "ftk_"
+
+// displayPrefixLength: panjang awal token yang disimpan untuk ditampilkan ke user
+const displayPrefixLength = len(TokenPrefix) + 6
+
+type UseCase interface {
+	Create(ctx context.Context, userID string, req *CreateAPITokenRequest) (*CreatedAPITokenResponse, error)
+	List(ctx context.Context, userID string) ([]APITokenResponse, error)
+	Revoke(ctx context.Context, userID string, tokenID string) error
+	Authenticate(ctx context.Context, token string) (string, bool, error)
+}
+
+type useCase struct {
+	repo     Repository
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewUseCase(repo Repository, log *logrus.Logger, validate *validator.Validate) UseCase {
+	return &useCase{
+		repo:     repo,
+		log:      log,
+		validate: validate,
+	}
+}
+
+func (u *useCase) Create(ctx context.Context, userID string, req *CreateAPITokenRequest) (*CreatedAPITokenResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidExpiry
+	}
+
+	scope := req.Scope
+	if scope == "" {
+		scope = ScopeFull // Default value
+	}
+
+	// 2. Generate token acak, yang disimpan hanya hash-nya
+	plain, err := generateToken()
+	if err != nil {
+		u.log.WithError(err).Error("Failed to generate api token")
+		return nil, ErrInternalServer
+	}
+
+	newToken := &APIToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    plain[:displayPrefixLength],
+		TokenHash: hashToken(plain),
+		Scope:     scope,
+		ExpiresAt: req.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	// 3. Simpan ke DB
+	if err := u.repo.Save(ctx, newToken); err != nil {
+		u.log.WithError(err).Error("Failed to save api token")
+		return nil, ErrInternalServer
+	}
+
+	return &CreatedAPITokenResponse{
+		APITokenResponse: *toResponse(newToken),
+		Token:            plain,
+	}, nil
+}
+
+func (u *useCase) List(ctx context.Context, userID string) ([]APITokenResponse, error) {
+	tokens, err := u.repo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list api tokens")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]APITokenResponse, 0, len(tokens))
+	for i := range tokens {
+		resp = append(resp, *toResponse(&tokens[i]))
+	}
+	return resp, nil
+}
+
+func (u *useCase) Revoke(ctx context.Context, userID string, tokenID string) error {
+	// ID bukan UUID pasti tidak ada (hindari error cast dari Postgres)
+	if _, err := uuid.Parse(tokenID); err != nil {
+		return ErrAPITokenNotFound
+	}
+
+	if err := u.repo.Revoke(ctx, tokenID, userID); err != nil {
+		if errors.Is(err, ErrAPITokenNotFound) {
+			return err
+		}
+		u.log.WithError(err).Error("Failed to revoke api token")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// Authenticate dipanggil AuthMiddleware untuk setiap request ber-token API.
+// Return user_id pemilik dan apakah token read-only; user_id kosong berarti token tidak valid.
+func (u *useCase) Authenticate(ctx context.Context, token string) (string, bool, error) {
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return "", false, nil
+	}
+
+	stored, err := u.repo.FindActiveByHash(ctx, hashToken(token))
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find api token")
+		return "", false, ErrInternalServer
+	}
+	if stored == nil {
+		return "", false, nil
+	}
+
+	// Gagal mencatat last_used tidak boleh menggagalkan request
+	if err := u.repo.TouchLastUsed(ctx, stored.ID); err != nil {
+		u.log.WithError(err).Warn("Failed to update api token last_used_at")
+	}
+
+	return stored.UserID, stored.Scope == ScopeRead, nil
+}
+
+// generateToken menghasilkan TokenPrefix + 32 byte acak dalam format base64url
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return TokenPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken: SHA-256 hex, dipakai untuk menyimpan & mencari token di DB
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,217 @@
+package apitoken_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/apitoken"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==========================================
+// 1. MOCK OBJECTS
+// ==========================================
+
+// MockRepository memalsukan behavior Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, t *apitoken.APIToken) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindActiveByHash(ctx context.Context, tokenHash string) (*apitoken.APIToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*apitoken.APIToken), args.Error(1)
+}
+
+func (m *MockRepository) FindAllByUserID(ctx context.Context, userID string) ([]apitoken.APIToken, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]apitoken.APIToken), args.Error(1)
+}
+
+func (m *MockRepository) Revoke(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RevokeAllByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) TouchLastUsed(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// ==========================================
+// 2. HELPER SETUP
+// ==========================================
+
+const (
+	ownerID = "11111111-1111-1111-1111-111111111111"
+	tokenID = "33333333-3333-3333-3333-333333333333"
+)
+
+func setupTest() (apitoken.UseCase, *MockRepository) {
+	mockRepo := new(MockRepository)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return apitoken.NewUseCase(mockRepo, log, validator.New()), mockRepo
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// ==========================================
+// 3. GROUP: CREATE TESTS
+// ==========================================
+
+func TestCreate_StoresOnlyHash(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	var saved *apitoken.APIToken
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(t *apitoken.APIToken) bool {
+		saved = t
+		return true
+	})).Return(nil)
+
+	resp, err := u.Create(context.Background(), ownerID, &apitoken.CreateAPITokenRequest{Name: "cron import"})
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.Token, apitoken.TokenPrefix))
+	assert.Equal(t, apitoken.ScopeFull, resp.Scope)
+	assert.Equal(t, ownerID, saved.UserID)
+	assert.Equal(t, sha256Hex(resp.Token), saved.TokenHash)
+	assert.True(t, strings.HasPrefix(resp.Token, saved.Prefix))
+	assert.Nil(t, saved.ExpiresAt)
+}
+
+func TestCreate_ExpiryInPast(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	past := time.Now().Add(-time.Hour)
+	resp, err := u.Create(context.Background(), ownerID, &apitoken.CreateAPITokenRequest{Name: "old", ExpiresAt: &past})
+
+	assert.Equal(t, apitoken.ErrInvalidExpiry, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestCreate_InvalidScope(t *testing.T) {
+	u, _ := setupTest()
+
+	resp, err := u.Create(context.Background(), ownerID, &apitoken.CreateAPITokenRequest{Name: "x", Scope: "admin"})
+
+	var validationErrs validator.ValidationErrors
+	assert.ErrorAs(t, err, &validationErrs)
+	assert.Nil(t, resp)
+}
+
+// ==========================================
+// 4. GROUP: LIST & REVOKE TESTS
+// ==========================================
+
+func TestList_HidesHash(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindAllByUserID", mock.Anything, ownerID).Return([]apitoken.APIToken{
+		{ID: tokenID, UserID: ownerID, Name: "cron", Prefix: "ftk_abcdef", TokenHash: "secret-hash", Scope: apitoken.ScopeRead},
+	}, nil)
+
+	resp, err := u.List(context.Background(), ownerID)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, "ftk_abcdef", resp[0].Prefix)
+	assert.Equal(t, apitoken.ScopeRead, resp[0].Scope)
+}
+
+func TestRevoke_NotOwned(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("Revoke", mock.Anything, tokenID, ownerID).Return(apitoken.ErrAPITokenNotFound)
+
+	err := u.Revoke(context.Background(), ownerID, tokenID)
+
+	assert.Equal(t, apitoken.ErrAPITokenNotFound, err)
+}
+
+func TestRevoke_InvalidID(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	err := u.Revoke(context.Background(), ownerID, "not-a-uuid")
+
+	assert.Equal(t, apitoken.ErrAPITokenNotFound, err)
+	mockRepo.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ==========================================
+// 5. GROUP: AUTHENTICATE TESTS
+// ==========================================
+
+func TestAuthenticate_ValidReadOnlyToken(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	plain := apitoken.TokenPrefix + "abc"
+	mockRepo.On("FindActiveByHash", mock.Anything, sha256Hex(plain)).Return(&apitoken.APIToken{
+		ID: tokenID, UserID: ownerID, Scope: apitoken.ScopeRead,
+	}, nil)
+	mockRepo.On("TouchLastUsed", mock.Anything, tokenID).Return(nil)
+
+	userID, readOnly, err := u.Authenticate(context.Background(), plain)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ownerID, userID)
+	assert.True(t, readOnly)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthenticate_UnknownOrRevokedToken(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindActiveByHash", mock.Anything, mock.Anything).Return(nil, nil)
+
+	userID, _, err := u.Authenticate(context.Background(), apitoken.TokenPrefix+"revoked")
+
+	assert.NoError(t, err)
+	assert.Empty(t, userID)
+	mockRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate_TouchFailureDoesNotFail(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindActiveByHash", mock.Anything, mock.Anything).Return(&apitoken.APIToken{
+		ID: tokenID, UserID: ownerID, Scope: apitoken.ScopeFull,
+	}, nil)
+	mockRepo.On("TouchLastUsed", mock.Anything, tokenID).Return(errors.New("db down"))
+
+	userID, readOnly, err := u.Authenticate(context.Background(), apitoken.TokenPrefix+"abc")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ownerID, userID)
+	assert.False(t, readOnly)
+}
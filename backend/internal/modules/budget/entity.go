@@ -0,0 +1,206 @@
+package budget
+
+import "time"
+
+type Budget struct {
+	ID       string
+	UserID   string
+	Budget   float64
+	Date     time.Time
+	Rollover RolloverSettings
+	// IncomePercent: jika diisi, Budget dihitung dari persentase pemasukan bulan yang sama
+	IncomePercent *float64
+	// CarriedOver: sisa (+) atau kekurangan (-) dari bulan sebelumnya, terpisah dari Budget
+	CarriedOver      float64
+	PreviousBudgetID *string
+	ClosedAt         *time.Time
+	CreatedAt        time.Time
+}
+
+// Available: dana yang bisa dipakai bulan ini (budget dasar + carry-over)
+func (b *Budget) Available() float64 {
+	return b.Budget + b.CarriedOver
+}
+
+// RolloverSettings: Apa yang dibawa ke bulan berikutnya saat budget ditutup.
+// Cap membatasi nilai absolut carry-over (nil = tanpa batas).
+type RolloverSettings struct {
+	CarrySurplus bool     `json:"carry_surplus"`
+	CarryDeficit bool     `json:"carry_deficit"`
+	Cap          *float64 `json:"cap" validate:"omitempty,gte=0"`
+}
+
+// BudgetResponse: Format standar data budget untuk output JSON
+type BudgetResponse struct {
+	ID               string           `json:"id"`
+	UserID           string           `json:"user_id"`
+	Budget           float64          `json:"budget"`
+	IncomePercent    *float64         `json:"income_percent"`
+	CarriedOver      float64          `json:"carried_over"`
+	Date             time.Time        `json:"date"`
+	Rollover         RolloverSettings `json:"rollover"`
+	PreviousBudgetID *string          `json:"previous_budget_id"`
+	ClosedAt         *time.Time       `json:"closed_at"`
+	CreatedAt        time.Time        `json:"created_at"`
+}
+
+// CategoryTotal: Total pengeluaran satu kategori dalam satu budget (CategoryID nil = tanpa kategori)
+type CategoryTotal struct {
+	CategoryID *string
+	Name       *string
+	Color      *string
+	Icon       *string
+	Total      float64
+}
+
+// CategoryTotalResponse: Rincian pengeluaran per kategori di detail budget
+type CategoryTotalResponse struct {
+	CategoryID *string `json:"category_id"`
+	Name       *string `json:"name"`
+	Color      *string `json:"color"`
+	Icon       *string `json:"icon"`
+	Total      float64 `json:"total"`
+}
+
+// Allocation: Jatah (envelope) satu kategori di dalam satu budget bulanan.
+// Name, Color & Icon diisi dari tabel categories saat dibaca (FindAllocations).
+type Allocation struct {
+	BudgetID   string
+	CategoryID string
+	Amount     float64
+	UpdatedAt  time.Time
+	Name       string
+	Color      *string
+	Icon       *string
+}
+
+// EnvelopeResponse: Jatah vs realisasi pengeluaran satu kategori
+type EnvelopeResponse struct {
+	CategoryID string  `json:"category_id"`
+	Name       string  `json:"name"`
+	Color      *string `json:"color"`
+	Icon       *string `json:"icon"`
+	Allocated  float64 `json:"allocated"`
+	Spent      float64 `json:"spent"`
+	Remaining  float64 `json:"remaining"`
+	Overspent  bool    `json:"overspent"`
+}
+
+// BudgetDetailResponse: GET /api/budgets/{budget_id}, budget beserta ringkasan pengeluarannya
+type BudgetDetailResponse struct {
+	BudgetResponse
+	Spent              float64                 `json:"spent"`
+	Remaining          float64                 `json:"remaining"`
+	Allocated          float64                 `json:"allocated"`
+	Unallocated        float64                 `json:"unallocated"`
+	OverAllocated      bool                    `json:"over_allocated"`
+	OverspentEnvelopes int                     `json:"overspent_envelopes"`
+	Envelopes          []EnvelopeResponse      `json:"envelopes"`
+	CategoryTotals     []CategoryTotalResponse `json:"category_totals"`
+}
+
+// CreateBudgetRequest: Validasi input saat membuat budget bulanan (rollover opsional, default tidak membawa apa pun).
+// Isi salah satu: budget (nilai tetap) atau income_percent (persentase pemasukan bulan itu).
+type CreateBudgetRequest struct {
+	Budget        float64           `json:"budget" validate:"required_without=IncomePercent,excluded_with=IncomePercent,omitempty,gt=0"`
+	IncomePercent *float64          `json:"income_percent" validate:"omitempty,gt=0,lte=100"`
+	Date          *time.Time        `json:"date" validate:"required"`
+	Rollover      *RolloverSettings `json:"rollover"`
+}
+
+// UpdateBudgetRequest: Semua field opsional (PATCH). Rollover diganti utuh jika dikirim.
+// Mengirim budget mengubahnya menjadi nilai tetap (income_percent dikosongkan).
+type UpdateBudgetRequest struct {
+	Budget        *float64          `json:"budget" validate:"omitempty,gt=0,excluded_with=IncomePercent"`
+	IncomePercent *float64          `json:"income_percent" validate:"omitempty,gt=0,lte=100"`
+	Date          *time.Time        `json:"date"`
+	Rollover      *RolloverSettings `json:"rollover"`
+}
+
+// CloseBudgetResponse: Hasil tutup bulan, budget yang ditutup dan budget bulan berikutnya
+type CloseBudgetResponse struct {
+	Closed      BudgetResponse `json:"closed"`
+	Next        BudgetResponse `json:"next"`
+	CarriedOver float64        `json:"carried_over"`
+}
+
+// SetAllocationRequest: Jatah kategori dalam budget (0 = kosongkan envelope tanpa menghapusnya)
+type SetAllocationRequest struct {
+	Amount *float64 `json:"amount" validate:"required,gte=0"`
+}
+
+// MoveAllocationRequest: Pindahkan sebagian jatah dari satu envelope ke envelope lain
+type MoveAllocationRequest struct {
+	FromCategoryID string  `json:"from_category_id" validate:"required,uuid"`
+	ToCategoryID   string  `json:"to_category_id" validate:"required,uuid,nefield=FromCategoryID"`
+	Amount         float64 `json:"amount" validate:"required,gt=0"`
+}
+
+// ListBudgetRequest: Filter rentang tanggal (opsional)
+type ListBudgetRequest struct {
+	DateFrom *time.Time
+	DateTo   *time.Time
+}
+
+func toResponse(b *Budget) *BudgetResponse {
+	return &BudgetResponse{
+		ID:               b.ID,
+		UserID:           b.UserID,
+		Budget:           b.Budget,
+		IncomePercent:    b.IncomePercent,
+		CarriedOver:      b.CarriedOver,
+		Date:             b.Date,
+		Rollover:         b.Rollover,
+		PreviousBudgetID: b.PreviousBudgetID,
+		ClosedAt:         b.ClosedAt,
+		CreatedAt:        b.CreatedAt,
+	}
+}
+
+func toDetailResponse(b *Budget, totals []CategoryTotal, allocations []Allocation) *BudgetDetailResponse {
+	resp := &BudgetDetailResponse{
+		BudgetResponse: *toResponse(b),
+		Envelopes:      make([]EnvelopeResponse, 0, len(allocations)),
+		CategoryTotals: make([]CategoryTotalResponse, 0, len(totals)),
+	}
+
+	spentByCategory := make(map[string]float64, len(totals))
+	for _, t := range totals {
+		if t.CategoryID != nil {
+			spentByCategory[*t.CategoryID] = t.Total
+		}
+		resp.Spent += t.Total
+		resp.CategoryTotals = append(resp.CategoryTotals, CategoryTotalResponse{
+			CategoryID: t.CategoryID,
+			Name:       t.Name,
+			Color:      t.Color,
+			Icon:       t.Icon,
+			Total:      t.Total,
+		})
+	}
+	resp.Remaining = b.Available() - resp.Spent
+
+	// Envelope overspent jika pengeluaran kategorinya melebihi jatah
+	for _, a := range allocations {
+		spent := spentByCategory[a.CategoryID]
+		envelope := EnvelopeResponse{
+			CategoryID: a.CategoryID,
+			Name:       a.Name,
+			Color:      a.Color,
+			Icon:       a.Icon,
+			Allocated:  a.Amount,
+			Spent:      spent,
+			Remaining:  a.Amount - spent,
+			Overspent:  spent > a.Amount,
+		}
+		if envelope.Overspent {
+			resp.OverspentEnvelopes++
+		}
+		resp.Allocated += a.Amount
+		resp.Envelopes = append(resp.Envelopes, envelope)
+	}
+	resp.Unallocated = b.Available() - resp.Allocated
+	// Bisa terjadi pada budget persentase saat pemasukan bulan itu turun
+	resp.OverAllocated = resp.Unallocated < 0
+	return resp
+}
@@ -0,0 +1,186 @@
+package budget
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	useCase UseCase
+}
+
+func NewHandler(useCase UseCase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req CreateBudgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Create(c.Context(), userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	// Query param date_from & date_to (format ISO8601 / RFC3339)
+	var req ListBudgetRequest
+	var err error
+	if req.DateFrom, err = validation.ParseDateQuery("date_from", c.Query("date_from")); err != nil {
+		return err
+	}
+	if req.DateTo, err = validation.ParseDateQuery("date_to", c.Query("date_to")); err != nil {
+		return err
+	}
+
+	resp, err := h.useCase.List(c.Context(), userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.Get(c.Context(), userID, c.Params("budget_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req UpdateBudgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Update(c.Context(), userID, c.Params("budget_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.Delete(c.Context(), userID, c.Params("budget_id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) SetAllocation(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req SetAllocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.SetAllocation(c.Context(), userID, c.Params("budget_id"), c.Params("category_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) DeleteAllocation(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.DeleteAllocation(c.Context(), userID, c.Params("budget_id"), c.Params("category_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) MoveAllocation(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req MoveAllocationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.MoveAllocation(c.Context(), userID, c.Params("budget_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Close(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.Close(c.Context(), userID, c.Params("budget_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler) {
+	api := app.Group("/api/budgets")
+
+	api.Post("/", authMiddleware, h.Create)
+	api.Get("/", authMiddleware, h.List)
+	api.Get("/:budget_id", authMiddleware, h.Get)
+	api.Patch("/:budget_id", authMiddleware, h.Update)
+	api.Delete("/:budget_id", authMiddleware, h.Delete)
+	api.Post("/:budget_id/close", authMiddleware, h.Close)
+
+	api.Post("/:budget_id/allocations/move", authMiddleware, h.MoveAllocation)
+	api.Put("/:budget_id/allocations/:category_id", authMiddleware, h.SetAllocation)
+	api.Delete("/:budget_id/allocations/:category_id", authMiddleware, h.DeleteAllocation)
+}
@@ -0,0 +1,478 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrBudgetNotFound          = apperror.NotFound("budget_not_found", "budget not found")
+	ErrAllocationNotFound      = apperror.NotFound("allocation_not_found", "category has no allocation in this budget")
+	ErrAllocationExceedsBudget = apperror.BadRequest("allocation_exceeds_budget", "total allocations cannot exceed the budget")
+	ErrInsufficientAllocation  = apperror.BadRequest("insufficient_allocation", "source envelope does not have enough allocation")
+	ErrBudgetClosed            = apperror.Conflict("budget_closed", "budget has already been closed")
+	ErrBudgetMonthExists       = apperror.Conflict("budget_month_exists", "a budget already exists for this month")
+	ErrNextBudgetClosed        = apperror.Conflict("next_budget_closed", "next month's budget has already been closed")
+)
+
+// Repository: Semua query WAJIB di-scope ke user_id pemilik budget
+// Satu budget per user per bulan (UTC): Save, Update & Close mengembalikan ErrBudgetMonthExists jika bentrok
+type Repository interface {
+	Save(ctx context.Context, budget *Budget) error
+	FindByID(ctx context.Context, id string, userID string) (*Budget, error)
+	FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]Budget, error)
+	// FindByMonth: budget user yang tanggalnya jatuh di bulan monthStart (nil jika belum ada)
+	FindByMonth(ctx context.Context, userID string, monthStart time.Time) (*Budget, error)
+	// Update: ErrAllocationExceedsBudget jika budget diturunkan di bawah total jatah, ErrBudgetClosed jika sudah ditutup
+	Update(ctx context.Context, budget *Budget) error
+	// SetIncomeBudget menghitung ulang budget persentase dari total pemasukan bulannya (income_percent yang tersimpan),
+	// hanya kolom budget yang diubah. Budget tetap / yang sudah ditutup dilewati.
+	// Return true jika total jatah sekarang melebihi budget (tidak ditolak, pemasukan memang turun).
+	SetIncomeBudget(ctx context.Context, id string, income float64) (bool, error)
+	Delete(ctx context.Context, id string, userID string) error
+	// SumByCategory: total histories per kategori, terbesar dulu
+	SumByCategory(ctx context.Context, budgetID string) ([]CategoryTotal, error)
+
+	// FindAllocations: envelope milik budget beserta nama kategorinya, urut nama
+	FindAllocations(ctx context.Context, budgetID string) ([]Allocation, error)
+	// SetAllocation membuat / mengganti jatah kategori; ErrAllocationExceedsBudget jika total melebihi budget.
+	// SetAllocation & MoveAllocation mengembalikan ErrBudgetClosed jika budget sudah ditutup.
+	SetAllocation(ctx context.Context, allocation *Allocation) error
+	// DeleteAllocation tidak mengubah budget yang sudah ditutup (dilaporkan sebagai not found)
+	DeleteAllocation(ctx context.Context, budgetID string, categoryID string) error
+	// MoveAllocation memindahkan amount dari envelope fromCategoryID ke toCategoryID dalam satu transaksi
+	MoveAllocation(ctx context.Context, budgetID string, fromCategoryID string, toCategoryID string, amount float64, at time.Time) error
+
+	// Close menandai budget ditutup lalu menyimpan carry-over ke next dalam satu transaksi.
+	// next dibuat jika createNext, selain itu carried_over & previous_budget_id-nya diperbarui.
+	// ErrBudgetClosed jika budget sudah pernah ditutup, ErrNextBudgetClosed jika next yang sudah ditutup;
+	// ErrAllocationExceedsBudget jika jatah next
+	// melebihi budget-nya setelah carry-over (carry-over defisit).
+	Close(ctx context.Context, budget *Budget, next *Budget, createNext bool) error
+}
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &repository{db: db}
+}
+
+// budgetColumns: Urutan kolom harus sama dengan urutan scanBudget
+const budgetColumns = `id, user_id, budget, income_percent, date, rollover_surplus, rollover_deficit, rollover_cap, carried_over, previous_budget_id, closed_at, created_at`
+
+func scanBudget(row pgx.Row, budget *Budget) error {
+	return row.Scan(
+		&budget.ID, &budget.UserID, &budget.Budget, &budget.IncomePercent, &budget.Date,
+		&budget.Rollover.CarrySurplus, &budget.Rollover.CarryDeficit, &budget.Rollover.Cap,
+		&budget.CarriedOver, &budget.PreviousBudgetID, &budget.ClosedAt, &budget.CreatedAt,
+	)
+}
+
+func (r *repository) Save(ctx context.Context, budget *Budget) error {
+	return insertBudget(ctx, r.db, budget)
+}
+
+// insertBudget dipakai Save dan Close (budget bulan berikutnya dibuat di dalam transaksi)
+func insertBudget(ctx context.Context, db database.Execer, budget *Budget) error {
+	query := `
+		INSERT INTO monthly_budgets (` + budgetColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err := db.Exec(ctx, query,
+		budget.ID, budget.UserID, budget.Budget, budget.IncomePercent, budget.Date,
+		budget.Rollover.CarrySurplus, budget.Rollover.CarryDeficit, budget.Rollover.Cap,
+		budget.CarriedOver, budget.PreviousBudgetID, budget.ClosedAt, budget.CreatedAt,
+	)
+	return mapUniqueViolation(err)
+}
+
+func (r *repository) FindByID(ctx context.Context, id string, userID string) (*Budget, error) {
+	query := `SELECT ` + budgetColumns + ` FROM monthly_budgets WHERE id = $1 AND user_id = $2`
+
+	var budget Budget
+	if err := scanBudget(r.db.QueryRow(ctx, query, id, userID), &budget); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func (r *repository) FindByMonth(ctx context.Context, userID string, monthStart time.Time) (*Budget, error) {
+	query := `
+		SELECT ` + budgetColumns + ` FROM monthly_budgets
+		WHERE user_id = $1 AND date >= $2 AND date < $3
+	`
+
+	var budget Budget
+	if err := scanBudget(r.db.QueryRow(ctx, query, userID, monthStart, monthStart.AddDate(0, 1, 0)), &budget); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func (r *repository) FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]Budget, error) {
+	// Filter tanggal opsional: NULL berarti tidak dibatasi
+	query := `
+		SELECT ` + budgetColumns + ` FROM monthly_budgets
+		WHERE user_id = $1
+		  AND ($2::timestamptz IS NULL OR date >= $2)
+		  AND ($3::timestamptz IS NULL OR date <= $3)
+		ORDER BY date DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := make([]Budget, 0)
+	for rows.Next() {
+		var budget Budget
+		if err := scanBudget(rows, &budget); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, budget)
+	}
+	return budgets, rows.Err()
+}
+
+func (r *repository) Update(ctx context.Context, budget *Budget) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Kunci baris budget supaya pengecekan jatah tidak balapan dengan SetAllocation / MoveAllocation
+	if err := lockBudget(ctx, tx, budget.ID); err != nil {
+		return err
+	}
+
+	// Budget hanya boleh diturunkan selama total jatah masih muat (dibandingkan dalam NUMERIC, bukan float)
+	var exceeds bool
+	check := `
+		SELECT $2::numeric < b.budget AND COALESCE(SUM(a.amount), 0) > $2::numeric + b.carried_over
+		FROM monthly_budgets b
+		LEFT JOIN budget_allocations a ON a.budget_id = b.id
+		WHERE b.id = $1
+		GROUP BY b.budget, b.carried_over
+	`
+	if err := tx.QueryRow(ctx, check, budget.ID, budget.Budget).Scan(&exceeds); err != nil {
+		return err
+	}
+	if exceeds {
+		return ErrAllocationExceedsBudget
+	}
+
+	query := `
+		UPDATE monthly_budgets
+		SET budget = $1, income_percent = $2, date = $3, rollover_surplus = $4, rollover_deficit = $5, rollover_cap = $6
+		WHERE id = $7 AND user_id = $8
+	`
+	tag, err := tx.Exec(ctx, query,
+		budget.Budget, budget.IncomePercent, budget.Date, budget.Rollover.CarrySurplus, budget.Rollover.CarryDeficit, budget.Rollover.Cap,
+		budget.ID, budget.UserID,
+	)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBudgetNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *repository) SetIncomeBudget(ctx context.Context, id string, income float64) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Kunci dulu supaya pengecekan jatah di bawah melihat perubahan jatah yang baru di-commit
+	var eligible bool
+	lock := `SELECT income_percent IS NOT NULL AND closed_at IS NULL FROM monthly_budgets WHERE id = $1 FOR UPDATE`
+	if err := tx.QueryRow(ctx, lock, id).Scan(&eligible); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !eligible {
+		return false, nil
+	}
+
+	// income * persen / 100, dibulatkan ke sen
+	update := `UPDATE monthly_budgets SET budget = ROUND($1::numeric * income_percent / 100, 2) WHERE id = $2`
+	if _, err := tx.Exec(ctx, update, income, id); err != nil {
+		return false, err
+	}
+
+	var overAllocated bool
+	check := `
+		SELECT COALESCE(SUM(a.amount), 0) > b.budget + b.carried_over
+		FROM monthly_budgets b
+		LEFT JOIN budget_allocations a ON a.budget_id = b.id
+		WHERE b.id = $1
+		GROUP BY b.budget, b.carried_over
+	`
+	if err := tx.QueryRow(ctx, check, id).Scan(&overAllocated); err != nil {
+		return false, err
+	}
+
+	return overAllocated, tx.Commit(ctx)
+}
+
+func (r *repository) Delete(ctx context.Context, id string, userID string) error {
+	query := `DELETE FROM monthly_budgets WHERE id = $1 AND user_id = $2`
+
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+func (r *repository) SumByCategory(ctx context.Context, budgetID string) ([]CategoryTotal, error) {
+	query := `
+		SELECT h.category_id, c.name, c.color, c.icon, SUM(h.amount) AS total
+		FROM histories h
+		LEFT JOIN categories c ON c.id = h.category_id
+		WHERE h.budget_id = $1
+		GROUP BY h.category_id, c.name, c.color, c.icon
+		ORDER BY total DESC
+	`
+	rows, err := r.db.Query(ctx, query, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make([]CategoryTotal, 0)
+	for rows.Next() {
+		var total CategoryTotal
+		if err := rows.Scan(&total.CategoryID, &total.Name, &total.Color, &total.Icon, &total.Total); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+	return totals, rows.Err()
+}
+
+func (r *repository) FindAllocations(ctx context.Context, budgetID string) ([]Allocation, error) {
+	query := `
+		SELECT a.budget_id, a.category_id, a.amount, a.updated_at, c.name, c.color, c.icon
+		FROM budget_allocations a
+		JOIN categories c ON c.id = a.category_id
+		WHERE a.budget_id = $1
+		ORDER BY lower(c.name)
+	`
+	rows, err := r.db.Query(ctx, query, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	allocations := make([]Allocation, 0)
+	for rows.Next() {
+		var a Allocation
+		if err := rows.Scan(&a.BudgetID, &a.CategoryID, &a.Amount, &a.UpdatedAt, &a.Name, &a.Color, &a.Icon); err != nil {
+			return nil, err
+		}
+		allocations = append(allocations, a)
+	}
+	return allocations, rows.Err()
+}
+
+func (r *repository) SetAllocation(ctx context.Context, allocation *Allocation) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Kunci baris budget supaya dua perubahan jatah paralel tidak sama-sama lolos pengecekan total
+	if err := lockBudget(ctx, tx, allocation.BudgetID); err != nil {
+		return err
+	}
+
+	var exceeds bool
+	check := `
+		SELECT COALESCE(SUM(a.amount) FILTER (WHERE a.category_id <> $2), 0) + $3::numeric > b.budget + b.carried_over
+		FROM monthly_budgets b
+		LEFT JOIN budget_allocations a ON a.budget_id = b.id
+		WHERE b.id = $1
+		GROUP BY b.budget, b.carried_over
+	`
+	if err := tx.QueryRow(ctx, check, allocation.BudgetID, allocation.CategoryID, allocation.Amount).Scan(&exceeds); err != nil {
+		return err
+	}
+	if exceeds {
+		return ErrAllocationExceedsBudget
+	}
+
+	upsert := `
+		INSERT INTO budget_allocations (budget_id, category_id, amount, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (budget_id, category_id) DO UPDATE SET amount = EXCLUDED.amount, updated_at = EXCLUDED.updated_at
+	`
+	if _, err := tx.Exec(ctx, upsert, allocation.BudgetID, allocation.CategoryID, allocation.Amount, allocation.UpdatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *repository) DeleteAllocation(ctx context.Context, budgetID string, categoryID string) error {
+	query := `
+		DELETE FROM budget_allocations
+		WHERE budget_id = $1 AND category_id = $2
+		  AND EXISTS (SELECT 1 FROM monthly_budgets WHERE id = $1 AND closed_at IS NULL)
+	`
+
+	tag, err := r.db.Exec(ctx, query, budgetID, categoryID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAllocationNotFound
+	}
+	return nil
+}
+
+func (r *repository) MoveAllocation(ctx context.Context, budgetID string, fromCategoryID string, toCategoryID string, amount float64, at time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockBudget(ctx, tx, budgetID); err != nil {
+		return err
+	}
+
+	var enough bool
+	query := `SELECT amount >= $3::numeric FROM budget_allocations WHERE budget_id = $1 AND category_id = $2`
+	if err := tx.QueryRow(ctx, query, budgetID, fromCategoryID, amount).Scan(&enough); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAllocationNotFound
+		}
+		return err
+	}
+	if !enough {
+		return ErrInsufficientAllocation
+	}
+
+	// Envelope sumber tetap ada walau jatahnya menjadi 0; total budget tidak berubah
+	debit := `UPDATE budget_allocations SET amount = amount - $1, updated_at = $2 WHERE budget_id = $3 AND category_id = $4`
+	if _, err := tx.Exec(ctx, debit, amount, at, budgetID, fromCategoryID); err != nil {
+		return err
+	}
+	credit := `
+		INSERT INTO budget_allocations (budget_id, category_id, amount, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (budget_id, category_id) DO UPDATE SET amount = budget_allocations.amount + EXCLUDED.amount, updated_at = EXCLUDED.updated_at
+	`
+	if _, err := tx.Exec(ctx, credit, budgetID, toCategoryID, amount, at); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Close(ctx context.Context, budget *Budget, next *Budget, createNext bool) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// closed_at IS NULL menjaga tutup bulan tetap sekali walau dipanggil paralel
+	query := `UPDATE monthly_budgets SET closed_at = $1 WHERE id = $2 AND user_id = $3 AND closed_at IS NULL`
+	tag, err := tx.Exec(ctx, query, budget.ClosedAt, budget.ID, budget.UserID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrBudgetClosed
+	}
+
+	if createNext {
+		if err := insertBudget(ctx, tx, next); err != nil {
+			return err
+		}
+	} else {
+		// Jatah bulan berikutnya bisa sudah dibagi; carry-over defisit tidak boleh membuatnya melebihi budget
+		if err := lockBudget(ctx, tx, next.ID); err != nil {
+			if errors.Is(err, ErrBudgetClosed) {
+				return ErrNextBudgetClosed
+			}
+			return err
+		}
+		var exceeds bool
+		check := `
+			SELECT COALESCE(SUM(a.amount), 0) > b.budget + $2::numeric
+			FROM monthly_budgets b
+			LEFT JOIN budget_allocations a ON a.budget_id = b.id
+			WHERE b.id = $1
+			GROUP BY b.budget
+		`
+		if err := tx.QueryRow(ctx, check, next.ID, next.CarriedOver).Scan(&exceeds); err != nil {
+			return err
+		}
+		if exceeds {
+			return ErrAllocationExceedsBudget
+		}
+
+		query := `UPDATE monthly_budgets SET carried_over = $1, previous_budget_id = $2 WHERE id = $3 AND user_id = $4`
+		if _, err := tx.Exec(ctx, query, next.CarriedOver, next.PreviousBudgetID, next.ID, next.UserID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockBudget mengunci baris budget (SELECT ... FOR UPDATE) sampai transaksi selesai.
+// ErrBudgetClosed jika budget sudah ditutup.
+func lockBudget(ctx context.Context, tx pgx.Tx, budgetID string) error {
+	var closed bool
+	err := tx.QueryRow(ctx, `SELECT closed_at IS NOT NULL FROM monthly_budgets WHERE id = $1 FOR UPDATE`, budgetID).Scan(&closed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrBudgetNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if closed {
+		return ErrBudgetClosed
+	}
+	return nil
+}
+
+// mapUniqueViolation: user sudah punya budget lain di bulan yang sama
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrBudgetMonthExists
+	}
+	return err
+}
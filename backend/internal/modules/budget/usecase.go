@@ -0,0 +1,461 @@
+package budget
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/category"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInternalServer = apperror.ErrInternal
+	ErrInvalidDate    = apperror.BadRequest("invalid_date_range", "date_from must be before date_to")
+)
+
+// IncomeSource: Total pemasukan user dalam satu bulan (module income)
+type IncomeSource interface {
+	SumByMonth(ctx context.Context, userID string, monthStart time.Time) (float64, error)
+}
+
+type UseCase interface {
+	Create(ctx context.Context, userID string, req *CreateBudgetRequest) (*BudgetResponse, error)
+	List(ctx context.Context, userID string, req *ListBudgetRequest) ([]BudgetResponse, error)
+	Get(ctx context.Context, userID string, budgetID string) (*BudgetDetailResponse, error)
+	Update(ctx context.Context, userID string, budgetID string, req *UpdateBudgetRequest) (*BudgetResponse, error)
+	Delete(ctx context.Context, userID string, budgetID string) error
+
+	// Envelope: jatah per kategori di dalam budget
+	SetAllocation(ctx context.Context, userID string, budgetID string, categoryID string, req *SetAllocationRequest) (*BudgetDetailResponse, error)
+	DeleteAllocation(ctx context.Context, userID string, budgetID string, categoryID string) (*BudgetDetailResponse, error)
+	MoveAllocation(ctx context.Context, userID string, budgetID string, req *MoveAllocationRequest) (*BudgetDetailResponse, error)
+
+	// Close menutup bulan: hitung carry-over dari histories lalu siapkan budget bulan berikutnya
+	Close(ctx context.Context, userID string, budgetID string) (*CloseBudgetResponse, error)
+
+	// SyncIncome menghitung ulang budget persentase di bulan monthStart setelah pemasukan bulan itu berubah
+	SyncIncome(ctx context.Context, userID string, monthStart time.Time) error
+}
+
+type useCase struct {
+	repo         Repository
+	categoryRepo category.Repository
+	incomes      IncomeSource
+	log          *logrus.Logger
+	validate     *validator.Validate
+}
+
+func NewUseCase(repo Repository, categoryRepo category.Repository, incomes IncomeSource, log *logrus.Logger, validate *validator.Validate) UseCase {
+	return &useCase{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		incomes:      incomes,
+		log:          log,
+		validate:     validate,
+	}
+}
+
+func (u *useCase) Create(ctx context.Context, userID string, req *CreateBudgetRequest) (*BudgetResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Construct Entity (user_id selalu dari token, bukan dari body)
+	newBudget := &Budget{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		Budget:        req.Budget,
+		IncomePercent: req.IncomePercent,
+		Date:          *req.Date,
+		CreatedAt:     time.Now(),
+	}
+	if req.Rollover != nil {
+		newBudget.Rollover = *req.Rollover
+	}
+
+	// 3. Budget persentase dihitung dari pemasukan yang sudah tercatat di bulan itu
+	if newBudget.IncomePercent != nil {
+		amount, err := u.fromIncome(ctx, newBudget)
+		if err != nil {
+			u.log.WithError(err).Error("Failed to sum income")
+			return nil, ErrInternalServer
+		}
+		newBudget.Budget = amount
+	}
+
+	// 4. Simpan ke DB (satu budget per bulan)
+	if err := u.repo.Save(ctx, newBudget); err != nil {
+		if errors.Is(err, ErrBudgetMonthExists) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to save budget")
+		return nil, ErrInternalServer
+	}
+
+	return toResponse(newBudget), nil
+}
+
+func (u *useCase) List(ctx context.Context, userID string, req *ListBudgetRequest) ([]BudgetResponse, error) {
+	if req.DateFrom != nil && req.DateTo != nil && req.DateFrom.After(*req.DateTo) {
+		return nil, ErrInvalidDate
+	}
+
+	budgets, err := u.repo.FindAllByUserID(ctx, userID, req.DateFrom, req.DateTo)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list budgets")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]BudgetResponse, 0, len(budgets))
+	for i := range budgets {
+		resp = append(resp, *toResponse(&budgets[i]))
+	}
+	return resp, nil
+}
+
+func (u *useCase) Get(ctx context.Context, userID string, budgetID string) (*BudgetDetailResponse, error) {
+	budget, err := u.findOwned(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.detail(ctx, budget)
+}
+
+func (u *useCase) Update(ctx context.Context, userID string, budgetID string, req *UpdateBudgetRequest) (*BudgetResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan budget milik user dan belum ditutup
+	budget, err := u.findOpen(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Terapkan perubahan parsial
+	if req.Date != nil {
+		budget.Date = *req.Date
+	}
+	if req.Rollover != nil {
+		budget.Rollover = *req.Rollover
+	}
+	amount := req.Budget
+	if req.Budget != nil {
+		budget.IncomePercent = nil // Budget tetap menggantikan persentase
+	}
+	if req.IncomePercent != nil {
+		budget.IncomePercent = req.IncomePercent
+	}
+	// Budget persentase dihitung ulang jika persentase atau bulannya berubah
+	if budget.IncomePercent != nil && (req.IncomePercent != nil || req.Date != nil) {
+		computed, err := u.fromIncome(ctx, budget)
+		if err != nil {
+			u.log.WithError(err).Error("Failed to sum income")
+			return nil, ErrInternalServer
+		}
+		amount = &computed
+	}
+
+	if amount != nil {
+		budget.Budget = *amount
+	}
+
+	// 4. Simpan perubahan; budget tidak boleh lebih kecil dari total jatah envelope (dicek di dalam transaksi)
+	if err := u.repo.Update(ctx, budget); err != nil {
+		if errors.Is(err, ErrBudgetNotFound) || errors.Is(err, ErrAllocationExceedsBudget) || errors.Is(err, ErrBudgetClosed) || errors.Is(err, ErrBudgetMonthExists) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to update budget")
+		return nil, ErrInternalServer
+	}
+
+	return toResponse(budget), nil
+}
+
+func (u *useCase) Delete(ctx context.Context, userID string, budgetID string) error {
+	if _, err := uuid.Parse(budgetID); err != nil {
+		return ErrBudgetNotFound
+	}
+
+	if err := u.repo.Delete(ctx, budgetID, userID); err != nil {
+		if errors.Is(err, ErrBudgetNotFound) {
+			return err
+		}
+		u.log.WithError(err).Error("Failed to delete budget")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+func (u *useCase) SetAllocation(ctx context.Context, userID string, budgetID string, categoryID string, req *SetAllocationRequest) (*BudgetDetailResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan budget (belum ditutup) dan kategori milik user
+	budget, err := u.findOpen(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkCategory(ctx, userID, categoryID); err != nil {
+		return nil, err
+	}
+
+	// 3. Simpan jatah (total dicek ulang di dalam transaksi)
+	allocation := &Allocation{
+		BudgetID:   budget.ID,
+		CategoryID: categoryID,
+		Amount:     *req.Amount,
+		UpdatedAt:  time.Now(),
+	}
+	if err := u.repo.SetAllocation(ctx, allocation); err != nil {
+		if errors.Is(err, ErrAllocationExceedsBudget) || errors.Is(err, ErrBudgetNotFound) || errors.Is(err, ErrBudgetClosed) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to set allocation")
+		return nil, ErrInternalServer
+	}
+
+	return u.detail(ctx, budget)
+}
+
+func (u *useCase) DeleteAllocation(ctx context.Context, userID string, budgetID string, categoryID string) (*BudgetDetailResponse, error) {
+	budget, err := u.findOpen(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(categoryID); err != nil {
+		return nil, ErrAllocationNotFound
+	}
+
+	if err := u.repo.DeleteAllocation(ctx, budget.ID, categoryID); err != nil {
+		if errors.Is(err, ErrAllocationNotFound) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to delete allocation")
+		return nil, ErrInternalServer
+	}
+
+	return u.detail(ctx, budget)
+}
+
+func (u *useCase) MoveAllocation(ctx context.Context, userID string, budgetID string, req *MoveAllocationRequest) (*BudgetDetailResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan budget (belum ditutup) dan kategori tujuan milik user (sumber sudah pasti milik user jika punya envelope)
+	budget, err := u.findOpen(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkCategory(ctx, userID, req.ToCategoryID); err != nil {
+		return nil, err
+	}
+
+	// 3. Pindahkan jatah; total budget tidak berubah
+	if err := u.repo.MoveAllocation(ctx, budget.ID, req.FromCategoryID, req.ToCategoryID, req.Amount, time.Now()); err != nil {
+		if errors.Is(err, ErrAllocationNotFound) || errors.Is(err, ErrInsufficientAllocation) || errors.Is(err, ErrBudgetNotFound) || errors.Is(err, ErrBudgetClosed) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to move allocation")
+		return nil, ErrInternalServer
+	}
+
+	return u.detail(ctx, budget)
+}
+
+func (u *useCase) Close(ctx context.Context, userID string, budgetID string) (*CloseBudgetResponse, error) {
+	// 1. Pastikan budget milik user dan belum ditutup
+	budget, err := u.findOpen(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Hitung carry-over dari total pengeluaran bulan ini
+	totals, err := u.repo.SumByCategory(ctx, budget.ID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to sum histories by category")
+		return nil, ErrInternalServer
+	}
+	var spent float64
+	for _, t := range totals {
+		spent += t.Total
+	}
+	carry := carryOver(budget.Rollover, budget.Available()-spent)
+
+	// 3. Pakai budget bulan berikutnya jika sudah ada, selain itu buat dari budget dasar & setting rollover bulan ini
+	now := time.Now()
+	date := budget.Date.UTC()
+	nextMonth := time.Date(date.Year(), date.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	next, err := u.repo.FindByMonth(ctx, userID, nextMonth)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find next month budget")
+		return nil, ErrInternalServer
+	}
+	if next != nil && next.ClosedAt != nil {
+		return nil, ErrNextBudgetClosed
+	}
+	createNext := next == nil
+	if createNext {
+		next = &Budget{
+			ID:            uuid.New().String(),
+			UserID:        userID,
+			Budget:        budget.Budget,
+			IncomePercent: budget.IncomePercent,
+			Date:          nextMonth,
+			Rollover:      budget.Rollover,
+			CreatedAt:     now,
+		}
+		if next.IncomePercent != nil {
+			if next.Budget, err = u.fromIncome(ctx, next); err != nil {
+				u.log.WithError(err).Error("Failed to sum income")
+				return nil, ErrInternalServer
+			}
+		}
+	}
+	next.CarriedOver = carry
+	next.PreviousBudgetID = &budget.ID
+	budget.ClosedAt = &now
+
+	// 4. Simpan dalam satu transaksi (jatah bulan berikutnya dicek ulang terhadap budget + carry-over)
+	if err := u.repo.Close(ctx, budget, next, createNext); err != nil {
+		if errors.Is(err, ErrBudgetClosed) || errors.Is(err, ErrNextBudgetClosed) || errors.Is(err, ErrAllocationExceedsBudget) || errors.Is(err, ErrBudgetMonthExists) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to close budget")
+		return nil, ErrInternalServer
+	}
+
+	return &CloseBudgetResponse{
+		Closed:      *toResponse(budget),
+		Next:        *toResponse(next),
+		CarriedOver: carry,
+	}, nil
+}
+
+func (u *useCase) SyncIncome(ctx context.Context, userID string, monthStart time.Time) error {
+	// Hanya budget persentase yang belum ditutup yang mengikuti pemasukan
+	budget, err := u.repo.FindByMonth(ctx, userID, monthStart)
+	if err != nil {
+		return err
+	}
+	if budget == nil || budget.IncomePercent == nil || budget.ClosedAt != nil {
+		return nil
+	}
+
+	income, err := u.incomes.SumByMonth(ctx, userID, monthStart)
+	if err != nil {
+		return err
+	}
+
+	// Persentase & status tutup dicek ulang di bawah lock, hanya kolom budget yang diubah
+	overAllocated, err := u.repo.SetIncomeBudget(ctx, budget.ID, income)
+	if err != nil {
+		return err
+	}
+	if overAllocated {
+		u.log.WithField("budget_id", budget.ID).Warn("Income-based budget dropped below its allocations")
+	}
+	return nil
+}
+
+// fromIncome: IncomePercent dari total pemasukan di bulan budget (UTC, sama dengan ringkasan bulanan)
+func (u *useCase) fromIncome(ctx context.Context, budget *Budget) (float64, error) {
+	date := budget.Date.UTC()
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	income, err := u.incomes.SumByMonth(ctx, budget.UserID, monthStart)
+	if err != nil {
+		return 0, err
+	}
+	// income * persen / 100, dibulatkan ke sen
+	return math.Round(income*(*budget.IncomePercent)) / 100, nil
+}
+
+// carryOver menerapkan setting rollover ke sisa budget (leftover negatif = overspent)
+func carryOver(settings RolloverSettings, leftover float64) float64 {
+	var carry float64
+	switch {
+	case leftover > 0 && settings.CarrySurplus:
+		carry = leftover
+	case leftover < 0 && settings.CarryDeficit:
+		carry = leftover
+	}
+
+	if settings.Cap != nil && math.Abs(carry) > *settings.Cap {
+		carry = math.Copysign(*settings.Cap, carry)
+	}
+	return carry
+}
+
+// detail menyusun BudgetDetailResponse: total per kategori dan status setiap envelope
+func (u *useCase) detail(ctx context.Context, budget *Budget) (*BudgetDetailResponse, error) {
+	totals, err := u.repo.SumByCategory(ctx, budget.ID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to sum histories by category")
+		return nil, ErrInternalServer
+	}
+	allocations, err := u.repo.FindAllocations(ctx, budget.ID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find allocations")
+		return nil, ErrInternalServer
+	}
+	return toDetailResponse(budget, totals, allocations), nil
+}
+
+// checkCategory: envelope hanya boleh dibuat untuk kategori milik user yang sama
+func (u *useCase) checkCategory(ctx context.Context, userID string, categoryID string) error {
+	if _, err := uuid.Parse(categoryID); err != nil {
+		return category.ErrCategoryNotFound
+	}
+
+	owned, err := u.categoryRepo.FindByID(ctx, categoryID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find category")
+		return ErrInternalServer
+	}
+	if owned == nil {
+		return category.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// findOwned mengambil budget berdasarkan ID dan memastikan pemiliknya adalah userID
+func (u *useCase) findOwned(ctx context.Context, userID string, budgetID string) (*Budget, error) {
+	// ID bukan UUID pasti tidak ada (hindari error cast dari Postgres)
+	if _, err := uuid.Parse(budgetID); err != nil {
+		return nil, ErrBudgetNotFound
+	}
+
+	budget, err := u.repo.FindByID(ctx, budgetID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find budget")
+		return nil, ErrInternalServer
+	}
+	if budget == nil {
+		return nil, ErrBudgetNotFound
+	}
+	return budget, nil
+}
+
+// findOpen: findOwned untuk operasi yang mengubah budget; budget yang sudah ditutup tidak boleh diubah lagi
+func (u *useCase) findOpen(ctx context.Context, userID string, budgetID string) (*Budget, error) {
+	budget, err := u.findOwned(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if budget.ClosedAt != nil {
+		return nil, ErrBudgetClosed
+	}
+	return budget, nil
+}
@@ -0,0 +1,882 @@
+package budget_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/category"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==========================================
+// 1. MOCK OBJECTS
+// ==========================================
+
+// MockRepository memalsukan behavior Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, b *budget.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id string, userID string) (*budget.Budget, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*budget.Budget), args.Error(1)
+}
+
+func (m *MockRepository) FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]budget.Budget, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.Budget), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, b *budget.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetIncomeBudget(ctx context.Context, id string, income float64) (bool, error) {
+	args := m.Called(ctx, id, income)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SumByCategory(ctx context.Context, budgetID string) ([]budget.CategoryTotal, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.CategoryTotal), args.Error(1)
+}
+
+func (m *MockRepository) FindAllocations(ctx context.Context, budgetID string) ([]budget.Allocation, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.Allocation), args.Error(1)
+}
+
+func (m *MockRepository) SetAllocation(ctx context.Context, a *budget.Allocation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteAllocation(ctx context.Context, budgetID string, categoryID string) error {
+	args := m.Called(ctx, budgetID, categoryID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MoveAllocation(ctx context.Context, budgetID string, fromCategoryID string, toCategoryID string, amount float64, at time.Time) error {
+	args := m.Called(ctx, budgetID, fromCategoryID, toCategoryID, amount, at)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByMonth(ctx context.Context, userID string, monthStart time.Time) (*budget.Budget, error) {
+	args := m.Called(ctx, userID, monthStart)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*budget.Budget), args.Error(1)
+}
+
+func (m *MockRepository) Close(ctx context.Context, b *budget.Budget, next *budget.Budget, createNext bool) error {
+	args := m.Called(ctx, b, next, createNext)
+	return args.Error(0)
+}
+
+// MockCategoryRepository memalsukan behavior category.Repository
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Save(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) SaveAll(ctx context.Context, categories []category.Category) error {
+	args := m.Called(ctx, categories)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id string, userID string) (*category.Category, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindAllByUserID(ctx context.Context, userID string) ([]category.Category, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// MockIncomeSource memalsukan behavior budget.IncomeSource
+type MockIncomeSource struct {
+	mock.Mock
+}
+
+func (m *MockIncomeSource) SumByMonth(ctx context.Context, userID string, monthStart time.Time) (float64, error) {
+	args := m.Called(ctx, userID, monthStart)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+// ==========================================
+// 2. HELPER SETUP
+// ==========================================
+
+const (
+	ownerID     = "11111111-1111-1111-1111-111111111111"
+	budgetID    = "22222222-2222-2222-2222-222222222222"
+	foodID      = "44444444-4444-4444-4444-444444444444"
+	transportID = "55555555-5555-5555-5555-555555555555"
+)
+
+func setupTest() (budget.UseCase, *MockRepository) {
+	u, mockRepo, _ := setupTestWithCategories()
+	return u, mockRepo
+}
+
+func setupTestWithCategories() (budget.UseCase, *MockRepository, *MockCategoryRepository) {
+	u, mockRepo, mockCategoryRepo, _ := setupTestWithIncome()
+	return u, mockRepo, mockCategoryRepo
+}
+
+func setupTestWithIncome() (budget.UseCase, *MockRepository, *MockCategoryRepository, *MockIncomeSource) {
+	mockRepo := new(MockRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockIncomes := new(MockIncomeSource)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return budget.NewUseCase(mockRepo, mockCategoryRepo, mockIncomes, log, validator.New()), mockRepo, mockCategoryRepo, mockIncomes
+}
+
+func ownedBudget() *budget.Budget {
+	return &budget.Budget{ID: budgetID, UserID: ownerID, Budget: 1000}
+}
+
+func closedBudget() *budget.Budget {
+	b := ownedBudget()
+	closedAt := time.Now()
+	b.ClosedAt = &closedAt
+	return b
+}
+
+// ==========================================
+// 3. GROUP: CREATE TESTS
+// ==========================================
+
+func TestCreate_Success(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := &budget.CreateBudgetRequest{Budget: 5000000, Date: &date}
+
+	// Expectation: user_id diambil dari token, bukan dari request
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(b *budget.Budget) bool {
+		return b.UserID == ownerID && b.Budget == req.Budget && b.ID != ""
+	})).Return(nil)
+
+	resp, err := u.Create(context.Background(), ownerID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ownerID, resp.UserID)
+	assert.Equal(t, date, resp.Date)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreate_ValidationError(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	req := &budget.CreateBudgetRequest{Budget: -10} // Invalid: negatif & tanpa tanggal
+
+	resp, err := u.Create(context.Background(), ownerID, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_RepositoryError(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	date := time.Now()
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	resp, err := u.Create(context.Background(), ownerID, &budget.CreateBudgetRequest{Budget: 100, Date: &date})
+
+	assert.Equal(t, budget.ErrInternalServer, err)
+	assert.Nil(t, resp)
+}
+
+func TestCreate_MonthAlreadyHasBudget(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	date := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(budget.ErrBudgetMonthExists)
+
+	resp, err := u.Create(context.Background(), ownerID, &budget.CreateBudgetRequest{Budget: 100, Date: &date})
+
+	assert.Equal(t, budget.ErrBudgetMonthExists, err)
+	assert.Nil(t, resp)
+}
+
+// ==========================================
+// 4. GROUP: LIST & GET TESTS
+// ==========================================
+
+func TestList_InvalidRange(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	from := time.Now()
+	to := from.Add(-time.Hour)
+
+	resp, err := u.List(context.Background(), ownerID, &budget.ListBudgetRequest{DateFrom: &from, DateTo: &to})
+
+	assert.Equal(t, budget.ErrInvalidDate, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "FindAllByUserID")
+}
+
+func TestList_Success(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindAllByUserID", mock.Anything, ownerID, (*time.Time)(nil), (*time.Time)(nil)).
+		Return([]budget.Budget{{ID: budgetID, UserID: ownerID, Budget: 100}}, nil)
+
+	resp, err := u.List(context.Background(), ownerID, &budget.ListBudgetRequest{})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, budgetID, resp[0].ID)
+}
+
+func TestGet_OtherUsersBudget(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	// Budget milik user lain tidak akan ditemukan karena query di-scope ke user_id
+	mockRepo.On("FindByID", mock.Anything, budgetID, "intruder").Return(nil, nil)
+
+	resp, err := u.Get(context.Background(), "intruder", budgetID)
+
+	assert.Equal(t, budget.ErrBudgetNotFound, err)
+	assert.Nil(t, resp)
+}
+
+func TestGet_IncludesCategoryTotals(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	food := "Food & Drinks"
+	categoryID := foodID
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{
+		{CategoryID: &categoryID, Name: &food, Total: 300},
+		{Total: 50}, // history tanpa kategori
+	}, nil)
+	mockRepo.On("FindAllocations", mock.Anything, budgetID).Return([]budget.Allocation{}, nil)
+
+	resp, err := u.Get(context.Background(), ownerID, budgetID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(350), resp.Spent)
+	assert.Equal(t, float64(650), resp.Remaining)
+	assert.Len(t, resp.CategoryTotals, 2)
+	assert.Equal(t, &food, resp.CategoryTotals[0].Name)
+	assert.Nil(t, resp.CategoryTotals[1].CategoryID)
+}
+
+func TestGet_InvalidID(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	resp, err := u.Get(context.Background(), ownerID, "not-a-uuid")
+
+	assert.Equal(t, budget.ErrBudgetNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "FindByID")
+}
+
+// ==========================================
+// 5. GROUP: UPDATE & DELETE TESTS
+// ==========================================
+
+func TestUpdate_PartialSuccess(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	existing := &budget.Budget{ID: budgetID, UserID: ownerID, Budget: 100, Date: date}
+	newAmount := 250.0
+
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(b *budget.Budget) bool {
+		return b.Budget == newAmount && b.Date.Equal(date)
+	})).Return(nil)
+
+	resp, err := u.Update(context.Background(), ownerID, budgetID, &budget.UpdateBudgetRequest{Budget: &newAmount})
+
+	assert.NoError(t, err)
+	assert.Equal(t, newAmount, resp.Budget)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdate_BudgetBelowAllocations(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	newAmount := 250.0
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	// Total jatah dicek repository di dalam transaksi yang mengunci budget
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(b *budget.Budget) bool {
+		return b.Budget == newAmount
+	})).Return(budget.ErrAllocationExceedsBudget)
+
+	resp, err := u.Update(context.Background(), ownerID, budgetID, &budget.UpdateBudgetRequest{Budget: &newAmount})
+
+	assert.Equal(t, budget.ErrAllocationExceedsBudget, err)
+	assert.Nil(t, resp)
+}
+
+func TestUpdate_MoveToMonthWithBudget(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	february := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(budget.ErrBudgetMonthExists)
+
+	resp, err := u.Update(context.Background(), ownerID, budgetID, &budget.UpdateBudgetRequest{Date: &february})
+
+	assert.Equal(t, budget.ErrBudgetMonthExists, err)
+	assert.Nil(t, resp)
+}
+
+func TestUpdate_ClosedBudgetRejected(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	newAmount := 250.0
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(closedBudget(), nil)
+
+	resp, err := u.Update(context.Background(), ownerID, budgetID, &budget.UpdateBudgetRequest{Budget: &newAmount})
+
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestDelete_NotFound(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("Delete", mock.Anything, budgetID, ownerID).Return(budget.ErrBudgetNotFound)
+
+	err := u.Delete(context.Background(), ownerID, budgetID)
+
+	assert.Equal(t, budget.ErrBudgetNotFound, err)
+}
+
+func TestDelete_RepositoryError(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("Delete", mock.Anything, budgetID, ownerID).Return(errors.New("db down"))
+
+	err := u.Delete(context.Background(), ownerID, budgetID)
+
+	assert.Equal(t, budget.ErrInternalServer, err)
+}
+
+// ==========================================
+// 6. GROUP: ENVELOPE TESTS
+// ==========================================
+
+func TestGet_FlagsOverspentEnvelopes(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	food, transport := foodID, transportID
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{
+		{CategoryID: &food, Total: 450},
+		{CategoryID: &transport, Total: 100},
+	}, nil)
+	mockRepo.On("FindAllocations", mock.Anything, budgetID).Return([]budget.Allocation{
+		{CategoryID: foodID, Name: "Food & Drinks", Amount: 400},
+		{CategoryID: transportID, Name: "Transportation", Amount: 300},
+	}, nil)
+
+	resp, err := u.Get(context.Background(), ownerID, budgetID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(700), resp.Allocated)
+	assert.Equal(t, float64(300), resp.Unallocated)
+	assert.Equal(t, 1, resp.OverspentEnvelopes)
+	assert.True(t, resp.Envelopes[0].Overspent)
+	assert.Equal(t, float64(-50), resp.Envelopes[0].Remaining)
+	assert.False(t, resp.Envelopes[1].Overspent)
+	assert.Equal(t, float64(200), resp.Envelopes[1].Remaining)
+}
+
+func TestSetAllocation_Success(t *testing.T) {
+	u, mockRepo, mockCategoryRepo := setupTestWithCategories()
+
+	amount := 400.0
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockCategoryRepo.On("FindByID", mock.Anything, foodID, ownerID).Return(&category.Category{ID: foodID, UserID: ownerID}, nil)
+	mockRepo.On("SetAllocation", mock.Anything, mock.MatchedBy(func(a *budget.Allocation) bool {
+		return a.BudgetID == budgetID && a.CategoryID == foodID && a.Amount == amount
+	})).Return(nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{}, nil)
+	mockRepo.On("FindAllocations", mock.Anything, budgetID).Return([]budget.Allocation{{CategoryID: foodID, Amount: amount}}, nil)
+
+	resp, err := u.SetAllocation(context.Background(), ownerID, budgetID, foodID, &budget.SetAllocationRequest{Amount: &amount})
+
+	assert.NoError(t, err)
+	assert.Equal(t, amount, resp.Allocated)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSetAllocation_ExceedsBudget(t *testing.T) {
+	u, mockRepo, mockCategoryRepo := setupTestWithCategories()
+
+	amount := 5000.0
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockCategoryRepo.On("FindByID", mock.Anything, foodID, ownerID).Return(&category.Category{ID: foodID, UserID: ownerID}, nil)
+	mockRepo.On("SetAllocation", mock.Anything, mock.Anything).Return(budget.ErrAllocationExceedsBudget)
+
+	resp, err := u.SetAllocation(context.Background(), ownerID, budgetID, foodID, &budget.SetAllocationRequest{Amount: &amount})
+
+	assert.Equal(t, budget.ErrAllocationExceedsBudget, err)
+	assert.Nil(t, resp)
+}
+
+func TestSetAllocation_CategoryNotOwned(t *testing.T) {
+	u, mockRepo, mockCategoryRepo := setupTestWithCategories()
+
+	amount := 100.0
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockCategoryRepo.On("FindByID", mock.Anything, foodID, ownerID).Return(nil, nil)
+
+	resp, err := u.SetAllocation(context.Background(), ownerID, budgetID, foodID, &budget.SetAllocationRequest{Amount: &amount})
+
+	assert.Equal(t, category.ErrCategoryNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "SetAllocation")
+}
+
+func TestMoveAllocation_SameEnvelopeIsRejected(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	req := &budget.MoveAllocationRequest{FromCategoryID: foodID, ToCategoryID: foodID, Amount: 50}
+
+	resp, err := u.MoveAllocation(context.Background(), ownerID, budgetID, req)
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "MoveAllocation")
+}
+
+func TestMoveAllocation_InsufficientAllocation(t *testing.T) {
+	u, mockRepo, mockCategoryRepo := setupTestWithCategories()
+
+	req := &budget.MoveAllocationRequest{FromCategoryID: foodID, ToCategoryID: transportID, Amount: 500}
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockCategoryRepo.On("FindByID", mock.Anything, transportID, ownerID).Return(&category.Category{ID: transportID, UserID: ownerID}, nil)
+	mockRepo.On("MoveAllocation", mock.Anything, budgetID, foodID, transportID, 500.0, mock.Anything).Return(budget.ErrInsufficientAllocation)
+
+	resp, err := u.MoveAllocation(context.Background(), ownerID, budgetID, req)
+
+	assert.Equal(t, budget.ErrInsufficientAllocation, err)
+	assert.Nil(t, resp)
+}
+
+func TestDeleteAllocation_NotFound(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("DeleteAllocation", mock.Anything, budgetID, foodID).Return(budget.ErrAllocationNotFound)
+
+	resp, err := u.DeleteAllocation(context.Background(), ownerID, budgetID, foodID)
+
+	assert.Equal(t, budget.ErrAllocationNotFound, err)
+	assert.Nil(t, resp)
+}
+
+func TestAllocations_ClosedBudgetRejected(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	amount := 100.0
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(closedBudget(), nil)
+
+	_, err := u.SetAllocation(context.Background(), ownerID, budgetID, foodID, &budget.SetAllocationRequest{Amount: &amount})
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+
+	_, err = u.DeleteAllocation(context.Background(), ownerID, budgetID, foodID)
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+
+	_, err = u.MoveAllocation(context.Background(), ownerID, budgetID, &budget.MoveAllocationRequest{FromCategoryID: foodID, ToCategoryID: transportID, Amount: 50})
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+
+	mockRepo.AssertNotCalled(t, "SetAllocation")
+	mockRepo.AssertNotCalled(t, "DeleteAllocation")
+	mockRepo.AssertNotCalled(t, "MoveAllocation")
+}
+
+// ==========================================
+// 7. GROUP: ROLLOVER & CLOSE TESTS
+// ==========================================
+
+func januaryBudget(rollover budget.RolloverSettings) *budget.Budget {
+	return &budget.Budget{
+		ID:       budgetID,
+		UserID:   ownerID,
+		Budget:   1000,
+		Date:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Rollover: rollover,
+	}
+}
+
+func TestClose_CarriesSurplusIntoNewBudget(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	february := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(januaryBudget(budget.RolloverSettings{CarrySurplus: true}), nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{{Total: 700}}, nil)
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, february).Return(nil, nil)
+	mockRepo.On("Close", mock.Anything, mock.MatchedBy(func(b *budget.Budget) bool {
+		return b.ClosedAt != nil
+	}), mock.MatchedBy(func(next *budget.Budget) bool {
+		// Budget dasar disalin, carry-over disimpan terpisah
+		return next.Budget == 1000 && next.CarriedOver == 300 && next.Date.Equal(february) &&
+			*next.PreviousBudgetID == budgetID && next.Rollover.CarrySurplus
+	}), true).Return(nil)
+
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(300), resp.CarriedOver)
+	assert.Equal(t, float64(1000), resp.Next.Budget)
+	assert.Equal(t, float64(300), resp.Next.CarriedOver)
+	assert.NotNil(t, resp.Closed.ClosedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestClose_DeficitIsCappedAndUpdatesExistingBudget(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	limit := 100.0
+	existing := &budget.Budget{ID: "66666666-6666-6666-6666-666666666666", UserID: ownerID, Budget: 800}
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(januaryBudget(budget.RolloverSettings{CarryDeficit: true, Cap: &limit}), nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{{Total: 1250}}, nil)
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(existing, nil)
+	mockRepo.On("Close", mock.Anything, mock.Anything, existing, false).Return(nil)
+
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	// Overspent 250, tapi hanya -100 yang dibawa karena cap
+	assert.NoError(t, err)
+	assert.Equal(t, float64(-100), resp.CarriedOver)
+	assert.Equal(t, float64(800), resp.Next.Budget)
+	assert.Equal(t, float64(-100), existing.CarriedOver)
+}
+
+func TestClose_NextBudgetAlreadyClosed(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	closedAt := time.Now()
+	existing := &budget.Budget{ID: "66666666-6666-6666-6666-666666666666", UserID: ownerID, Budget: 800, ClosedAt: &closedAt}
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(januaryBudget(budget.RolloverSettings{CarrySurplus: true}), nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{}, nil)
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(existing, nil)
+
+	// Budget Januari sendiri masih terbuka, yang sudah ditutup adalah Februari
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	assert.Equal(t, budget.ErrNextBudgetClosed, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Close", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestClose_NextBudgetClosedConcurrently(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	existing := &budget.Budget{ID: "66666666-6666-6666-6666-666666666666", UserID: ownerID, Budget: 800}
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(januaryBudget(budget.RolloverSettings{CarrySurplus: true}), nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{}, nil)
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(existing, nil)
+	mockRepo.On("Close", mock.Anything, mock.Anything, existing, false).Return(budget.ErrNextBudgetClosed)
+
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	assert.Equal(t, budget.ErrNextBudgetClosed, err)
+	assert.Nil(t, resp)
+}
+
+func TestClose_DeficitBelowNextAllocationsRejected(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	existing := &budget.Budget{ID: "66666666-6666-6666-6666-666666666666", UserID: ownerID, Budget: 800}
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(januaryBudget(budget.RolloverSettings{CarryDeficit: true}), nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{{Total: 1250}}, nil)
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(existing, nil)
+	// Jatah Februari sudah 700, budget + carry-over hanya 550
+	mockRepo.On("Close", mock.Anything, mock.Anything, existing, false).Return(budget.ErrAllocationExceedsBudget)
+
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	assert.Equal(t, budget.ErrAllocationExceedsBudget, err)
+	assert.Nil(t, resp)
+}
+
+func TestClose_SurplusDroppedWhenNotCarried(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(januaryBudget(budget.RolloverSettings{CarryDeficit: true}), nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{{Total: 200}}, nil)
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(nil, nil)
+	mockRepo.On("Close", mock.Anything, mock.Anything, mock.Anything, true).Return(nil)
+
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	assert.NoError(t, err)
+	assert.Zero(t, resp.CarriedOver)
+}
+
+func TestClose_NextMonthFromUTCDate(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	// Budget Februari yang terbaca di zona UTC-5 tampil sebagai 31 Januari
+	february := januaryBudget(budget.RolloverSettings{})
+	february.Date = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC).In(time.FixedZone("EST", -5*60*60))
+	march := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(february, nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{}, nil)
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, march).Return(nil, nil)
+	mockRepo.On("Close", mock.Anything, mock.Anything, mock.Anything, true).Return(nil)
+
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	assert.NoError(t, err)
+	assert.True(t, resp.Next.Date.Equal(march))
+}
+
+func TestClose_AlreadyClosed(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	closed := januaryBudget(budget.RolloverSettings{})
+	closedAt := time.Now()
+	closed.ClosedAt = &closedAt
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(closed, nil)
+
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Close")
+}
+
+func TestGet_RemainingIncludesCarriedOver(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	withCarry := ownedBudget()
+	withCarry.CarriedOver = -200
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(withCarry, nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{{Total: 300}}, nil)
+	mockRepo.On("FindAllocations", mock.Anything, budgetID).Return([]budget.Allocation{}, nil)
+
+	resp, err := u.Get(context.Background(), ownerID, budgetID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1000), resp.Budget)
+	assert.Equal(t, float64(-200), resp.CarriedOver)
+	assert.Equal(t, float64(500), resp.Remaining)
+}
+
+// ==========================================
+// 8. GROUP: INCOME-BASED BUDGET TESTS
+// ==========================================
+
+func TestCreate_FromIncomePercent(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	date := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	percent := 70.0
+	mockIncomes.On("SumByMonth", mock.Anything, ownerID, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).Return(8500000.5, nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(b *budget.Budget) bool {
+		return b.Budget == 5950000.35 && *b.IncomePercent == percent
+	})).Return(nil)
+
+	resp, err := u.Create(context.Background(), ownerID, &budget.CreateBudgetRequest{IncomePercent: &percent, Date: &date})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 5950000.35, resp.Budget)
+	assert.Equal(t, percent, *resp.IncomePercent)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreate_FromIncomePercentUsesUTCMonth(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	// 1 Februari 03:00 WIB masih 31 Januari di UTC
+	date := time.Date(2026, 2, 1, 3, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	percent := 50.0
+	mockIncomes.On("SumByMonth", mock.Anything, ownerID, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)).Return(1000.0, nil)
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := u.Create(context.Background(), ownerID, &budget.CreateBudgetRequest{IncomePercent: &percent, Date: &date})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 500.0, resp.Budget)
+	mockIncomes.AssertExpectations(t)
+}
+
+func TestCreate_BudgetAndIncomePercentAreExclusive(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	date := time.Now()
+	percent := 50.0
+	resp, err := u.Create(context.Background(), ownerID, &budget.CreateBudgetRequest{Budget: 100, IncomePercent: &percent, Date: &date})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockIncomes.AssertNotCalled(t, "SumByMonth")
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestUpdate_FixedBudgetReplacesIncomePercent(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	percent := 60.0
+	existing := ownedBudget()
+	existing.IncomePercent = &percent
+	fixed := 750.0
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(b *budget.Budget) bool {
+		return b.Budget == fixed && b.IncomePercent == nil
+	})).Return(nil)
+
+	resp, err := u.Update(context.Background(), ownerID, budgetID, &budget.UpdateBudgetRequest{Budget: &fixed})
+
+	assert.NoError(t, err)
+	assert.Nil(t, resp.IncomePercent)
+	mockIncomes.AssertNotCalled(t, "SumByMonth")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdate_IncomePercentBelowAllocations(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	percent := 10.0
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(januaryBudget(budget.RolloverSettings{}), nil)
+	mockIncomes.On("SumByMonth", mock.Anything, ownerID, mock.Anything).Return(5000.0, nil)
+	// 10% dari 5000 = 500, lebih kecil dari jatah envelope 800
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(b *budget.Budget) bool {
+		return b.Budget == 500
+	})).Return(budget.ErrAllocationExceedsBudget)
+
+	resp, err := u.Update(context.Background(), ownerID, budgetID, &budget.UpdateBudgetRequest{IncomePercent: &percent})
+
+	assert.Equal(t, budget.ErrAllocationExceedsBudget, err)
+	assert.Nil(t, resp)
+}
+
+func TestSyncIncome_RecalculatesOpenPercentBudget(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	january := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	percent := 80.0
+	existing := januaryBudget(budget.RolloverSettings{})
+	existing.IncomePercent = &percent
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, january).Return(existing, nil)
+	mockIncomes.On("SumByMonth", mock.Anything, ownerID, january).Return(2000.0, nil)
+	// Persentase diterapkan repository di bawah lock; hanya kolom budget yang berubah
+	mockRepo.On("SetIncomeBudget", mock.Anything, budgetID, 2000.0).Return(false, nil)
+
+	err := u.SyncIncome(context.Background(), ownerID, january)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestSyncIncome_OverAllocationIsNotAnError(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	percent := 80.0
+	existing := januaryBudget(budget.RolloverSettings{})
+	existing.IncomePercent = &percent
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(existing, nil)
+	mockIncomes.On("SumByMonth", mock.Anything, ownerID, mock.Anything).Return(100.0, nil)
+	mockRepo.On("SetIncomeBudget", mock.Anything, budgetID, 100.0).Return(true, nil)
+
+	assert.NoError(t, u.SyncIncome(context.Background(), ownerID, existing.Date))
+}
+
+func TestSyncIncome_IgnoresFixedAndClosedBudgets(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	percent := 80.0
+	closedAt := time.Now()
+	closed := januaryBudget(budget.RolloverSettings{})
+	closed.IncomePercent = &percent
+	closed.ClosedAt = &closedAt
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(ownedBudget(), nil).Once()
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(closed, nil).Once()
+
+	assert.NoError(t, u.SyncIncome(context.Background(), ownerID, time.Now()))
+	assert.NoError(t, u.SyncIncome(context.Background(), ownerID, time.Now()))
+
+	mockIncomes.AssertNotCalled(t, "SumByMonth")
+	mockRepo.AssertNotCalled(t, "SetIncomeBudget")
+}
+
+func TestClose_PercentBudgetUsesNextMonthIncome(t *testing.T) {
+	u, mockRepo, _, mockIncomes := setupTestWithIncome()
+
+	february := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	percent := 50.0
+	january := januaryBudget(budget.RolloverSettings{})
+	january.IncomePercent = &percent
+	mockRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(january, nil)
+	mockRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{}, nil)
+	mockRepo.On("FindByMonth", mock.Anything, ownerID, february).Return(nil, nil)
+	mockIncomes.On("SumByMonth", mock.Anything, ownerID, february).Return(3000.0, nil)
+	mockRepo.On("Close", mock.Anything, mock.Anything, mock.MatchedBy(func(next *budget.Budget) bool {
+		return next.Budget == 1500 && *next.IncomePercent == percent
+	}), true).Return(nil)
+
+	resp, err := u.Close(context.Background(), ownerID, budgetID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1500), resp.Next.Budget)
+	mockRepo.AssertExpectations(t)
+}
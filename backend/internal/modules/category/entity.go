@@ -0,0 +1,69 @@
+package category
+
+import "time"
+
+type Category struct {
+	ID        string
+	UserID    string
+	Name      string
+	Color     *string
+	Icon      *string
+	IsDefault bool
+	CreatedAt time.Time
+}
+
+// DefaultCategory: Template kategori yang dibuat untuk setiap user baru
+type DefaultCategory struct {
+	Name  string
+	Color string
+	Icon  string
+}
+
+// DefaultCategories: harus sama dengan seed di migrasi create_categories (untuk user lama)
+var DefaultCategories = []DefaultCategory{
+	{Name: "Food & Drinks", Color: "#F97316", Icon: "utensils"},
+	{Name: "Groceries", Color: "#84CC16", Icon: "shopping-basket"},
+	{Name: "Transportation", Color: "#0EA5E9", Icon: "car"},
+	{Name: "Housing", Color: "#8B5CF6", Icon: "home"},
+	{Name: "Bills & Utilities", Color: "#EAB308", Icon: "receipt"},
+	{Name: "Health", Color: "#EF4444", Icon: "heart-pulse"},
+	{Name: "Entertainment", Color: "#EC4899", Icon: "film"},
+	{Name: "Shopping", Color: "#14B8A6", Icon: "shopping-bag"},
+	{Name: "Education", Color: "#6366F1", Icon: "graduation-cap"},
+	{Name: "Others", Color: "#64748B", Icon: "circle-ellipsis"},
+}
+
+// CategoryResponse: Format standar data kategori untuk output JSON
+type CategoryResponse struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Color     *string   `json:"color"`
+	Icon      *string   `json:"icon"`
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateCategoryRequest: Color dalam format hex (#RRGGBB / #RGB), icon bebas (nama icon di frontend)
+type CreateCategoryRequest struct {
+	Name  string  `json:"name" validate:"required,max=50"`
+	Color *string `json:"color" validate:"omitempty,hexcolor,max=7"`
+	Icon  *string `json:"icon" validate:"omitempty,max=50"`
+}
+
+// UpdateCategoryRequest: Semua field opsional (PATCH)
+type UpdateCategoryRequest struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color *string `json:"color" validate:"omitempty,hexcolor,max=7"`
+	Icon  *string `json:"icon" validate:"omitempty,max=50"`
+}
+
+func toResponse(c *Category) *CategoryResponse {
+	return &CategoryResponse{
+		ID:        c.ID,
+		Name:      c.Name,
+		Color:     c.Color,
+		Icon:      c.Icon,
+		IsDefault: c.IsDefault,
+		CreatedAt: c.CreatedAt,
+	}
+}
@@ -0,0 +1,104 @@
+package category
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	useCase UseCase
+}
+
+func NewHandler(useCase UseCase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req CreateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Create(c.Context(), userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.List(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.Get(c.Context(), userID, c.Params("category_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req UpdateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Update(c.Context(), userID, c.Params("category_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.Delete(c.Context(), userID, c.Params("category_id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler) {
+	api := app.Group("/api/categories")
+
+	api.Post("/", authMiddleware, h.Create)
+	api.Get("/", authMiddleware, h.List)
+	api.Get("/:category_id", authMiddleware, h.Get)
+	api.Patch("/:category_id", authMiddleware, h.Update)
+	api.Delete("/:category_id", authMiddleware, h.Delete)
+}
@@ -0,0 +1,131 @@
+package category
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrCategoryNotFound  = apperror.NotFound("category_not_found", "category not found")
+	ErrCategoryNameTaken = apperror.Conflict("category_name_taken", "category name already used")
+)
+
+// Repository: Semua query WAJIB di-scope ke user_id pemilik kategori
+type Repository interface {
+	Save(ctx context.Context, category *Category) error
+	// SaveAll menyimpan banyak kategori sekaligus; nama yang sudah ada dilewati
+	SaveAll(ctx context.Context, categories []Category) error
+	FindByID(ctx context.Context, id string, userID string) (*Category, error)
+	FindAllByUserID(ctx context.Context, userID string) ([]Category, error)
+	Update(ctx context.Context, category *Category) error
+	Delete(ctx context.Context, id string, userID string) error
+}
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &repository{db: db}
+}
+
+const categoryColumns = `id, user_id, name, color, icon, is_default, created_at`
+
+func (r *repository) Save(ctx context.Context, category *Category) error {
+	query := `
+		INSERT INTO categories (id, user_id, name, color, icon, is_default, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := r.db.Exec(ctx, query, category.ID, category.UserID, category.Name, category.Color, category.Icon, category.IsDefault, category.CreatedAt)
+	return mapUniqueViolation(err)
+}
+
+func (r *repository) SaveAll(ctx context.Context, categories []Category) error {
+	query := `
+		INSERT INTO categories (id, user_id, name, color, icon, is_default, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT DO NOTHING
+	`
+	batch := &pgx.Batch{}
+	for _, c := range categories {
+		batch.Queue(query, c.ID, c.UserID, c.Name, c.Color, c.Icon, c.IsDefault, c.CreatedAt)
+	}
+	return r.db.SendBatch(ctx, batch).Close()
+}
+
+func (r *repository) FindByID(ctx context.Context, id string, userID string) (*Category, error) {
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE id = $1 AND user_id = $2`
+
+	var category Category
+	err := r.db.QueryRow(ctx, query, id, userID).Scan(
+		&category.ID, &category.UserID, &category.Name, &category.Color, &category.Icon, &category.IsDefault, &category.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *repository) FindAllByUserID(ctx context.Context, userID string) ([]Category, error) {
+	// Default dulu, lalu kategori buatan user; masing-masing urut nama
+	query := `SELECT ` + categoryColumns + ` FROM categories WHERE user_id = $1 ORDER BY is_default DESC, lower(name)`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	categories := make([]Category, 0)
+	for rows.Next() {
+		var category Category
+		if err := rows.Scan(&category.ID, &category.UserID, &category.Name, &category.Color, &category.Icon, &category.IsDefault, &category.CreatedAt); err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+func (r *repository) Update(ctx context.Context, category *Category) error {
+	query := `UPDATE categories SET name = $1, color = $2, icon = $3 WHERE id = $4 AND user_id = $5`
+
+	tag, err := r.db.Exec(ctx, query, category.Name, category.Color, category.Icon, category.ID, category.UserID)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string, userID string) error {
+	query := `DELETE FROM categories WHERE id = $1 AND user_id = $2`
+
+	tag, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrCategoryNotFound
+	}
+	return nil
+}
+
+// mapUniqueViolation: nama kategori bentrok dengan kategori lain milik user yang sama
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrCategoryNameTaken
+	}
+	return err
+}
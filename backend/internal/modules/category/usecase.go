@@ -0,0 +1,208 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInternalServer  = apperror.ErrInternal
+	ErrDefaultCategory = apperror.Conflict("default_category", "default categories cannot be deleted")
+)
+
+type UseCase interface {
+	Create(ctx context.Context, userID string, req *CreateCategoryRequest) (*CategoryResponse, error)
+	List(ctx context.Context, userID string) ([]CategoryResponse, error)
+	Get(ctx context.Context, userID string, categoryID string) (*CategoryResponse, error)
+	Update(ctx context.Context, userID string, categoryID string, req *UpdateCategoryRequest) (*CategoryResponse, error)
+	Delete(ctx context.Context, userID string, categoryID string) error
+	// SeedDefaults membuat DefaultCategories untuk user (dipanggil saat Register, aman dipanggil ulang)
+	SeedDefaults(ctx context.Context, userID string) error
+}
+
+type useCase struct {
+	repo     Repository
+	log      *logrus.Logger
+	validate *validator.Validate
+}
+
+func NewUseCase(repo Repository, log *logrus.Logger, validate *validator.Validate) UseCase {
+	return &useCase{
+		repo:     repo,
+		log:      log,
+		validate: validate,
+	}
+}
+
+func (u *useCase) Create(ctx context.Context, userID string, req *CreateCategoryRequest) (*CategoryResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Construct Entity (user_id selalu dari token, bukan dari body)
+	newCategory := &Category{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Color:     normalizeColor(req.Color),
+		Icon:      req.Icon,
+		CreatedAt: time.Now(),
+	}
+
+	// 3. Simpan ke DB
+	if err := u.repo.Save(ctx, newCategory); err != nil {
+		if errors.Is(err, ErrCategoryNameTaken) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to save category")
+		return nil, ErrInternalServer
+	}
+
+	return toResponse(newCategory), nil
+}
+
+func (u *useCase) List(ctx context.Context, userID string) ([]CategoryResponse, error) {
+	categories, err := u.repo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list categories")
+		return nil, ErrInternalServer
+	}
+
+	// Kategori default tidak bisa dihapus, jadi list kosong berarti seed saat Register gagal: ulangi sekarang
+	if len(categories) == 0 {
+		if err := u.SeedDefaults(ctx, userID); err != nil {
+			return nil, err
+		}
+		if categories, err = u.repo.FindAllByUserID(ctx, userID); err != nil {
+			u.log.WithError(err).Error("Failed to list categories")
+			return nil, ErrInternalServer
+		}
+	}
+
+	resp := make([]CategoryResponse, 0, len(categories))
+	for i := range categories {
+		resp = append(resp, *toResponse(&categories[i]))
+	}
+	return resp, nil
+}
+
+func (u *useCase) Get(ctx context.Context, userID string, categoryID string) (*CategoryResponse, error) {
+	category, err := u.findOwned(ctx, userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(category), nil
+}
+
+func (u *useCase) Update(ctx context.Context, userID string, categoryID string, req *UpdateCategoryRequest) (*CategoryResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan kategori milik user
+	category, err := u.findOwned(ctx, userID, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Terapkan perubahan parsial (kategori default juga boleh diubah nama / warna / icon-nya)
+	if req.Name != nil {
+		category.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Color != nil {
+		category.Color = normalizeColor(req.Color)
+	}
+	if req.Icon != nil {
+		category.Icon = req.Icon
+	}
+
+	// 4. Simpan perubahan
+	if err := u.repo.Update(ctx, category); err != nil {
+		if errors.Is(err, ErrCategoryNotFound) || errors.Is(err, ErrCategoryNameTaken) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to update category")
+		return nil, ErrInternalServer
+	}
+
+	return toResponse(category), nil
+}
+
+func (u *useCase) Delete(ctx context.Context, userID string, categoryID string) error {
+	category, err := u.findOwned(ctx, userID, categoryID)
+	if err != nil {
+		return err
+	}
+	if category.IsDefault {
+		return ErrDefaultCategory
+	}
+
+	// History dengan kategori ini menjadi tanpa kategori (ON DELETE SET NULL)
+	if err := u.repo.Delete(ctx, category.ID, userID); err != nil {
+		if errors.Is(err, ErrCategoryNotFound) {
+			return err
+		}
+		u.log.WithError(err).Error("Failed to delete category")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+func (u *useCase) SeedDefaults(ctx context.Context, userID string) error {
+	now := time.Now()
+	categories := make([]Category, 0, len(DefaultCategories))
+	for _, d := range DefaultCategories {
+		color, icon := d.Color, d.Icon
+		categories = append(categories, Category{
+			ID:        uuid.New().String(),
+			UserID:    userID,
+			Name:      d.Name,
+			Color:     &color,
+			Icon:      &icon,
+			IsDefault: true,
+			CreatedAt: now,
+		})
+	}
+
+	if err := u.repo.SaveAll(ctx, categories); err != nil {
+		u.log.WithError(err).Error("Failed to seed default categories")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// findOwned mengambil kategori berdasarkan ID dan memastikan pemiliknya adalah userID
+func (u *useCase) findOwned(ctx context.Context, userID string, categoryID string) (*Category, error) {
+	// ID bukan UUID pasti tidak ada (hindari error cast dari Postgres)
+	if _, err := uuid.Parse(categoryID); err != nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	category, err := u.repo.FindByID(ctx, categoryID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find category")
+		return nil, ErrInternalServer
+	}
+	if category == nil {
+		return nil, ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// normalizeColor: hex disimpan dalam huruf besar supaya konsisten dengan kategori default
+func normalizeColor(color *string) *string {
+	if color == nil {
+		return nil
+	}
+	upper := strings.ToUpper(*color)
+	return &upper
+}
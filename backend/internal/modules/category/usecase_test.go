@@ -0,0 +1,192 @@
+package category_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/category"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==========================================
+// 1. MOCK OBJECTS
+// ==========================================
+
+// MockRepository memalsukan behavior Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveAll(ctx context.Context, categories []category.Category) error {
+	args := m.Called(ctx, categories)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id string, userID string) (*category.Category, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockRepository) FindAllByUserID(ctx context.Context, userID string) ([]category.Category, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]category.Category), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// ==========================================
+// 2. HELPER SETUP
+// ==========================================
+
+const (
+	ownerID    = "11111111-1111-1111-1111-111111111111"
+	categoryID = "44444444-4444-4444-4444-444444444444"
+)
+
+func setupTest() (category.UseCase, *MockRepository) {
+	mockRepo := new(MockRepository)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	return category.NewUseCase(mockRepo, log, validator.New()), mockRepo
+}
+
+// ==========================================
+// 3. GROUP: CREATE TESTS
+// ==========================================
+
+func TestCreate_Success(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	color := "#a1b2c3"
+	req := &category.CreateCategoryRequest{Name: "  Pets ", Color: &color}
+
+	// Expectation: nama di-trim, warna diseragamkan huruf besar, bukan kategori default
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(c *category.Category) bool {
+		return c.UserID == ownerID && c.Name == "Pets" && *c.Color == "#A1B2C3" && !c.IsDefault
+	})).Return(nil)
+
+	resp, err := u.Create(context.Background(), ownerID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Pets", resp.Name)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreate_InvalidColor(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	color := "red"
+	resp, err := u.Create(context.Background(), ownerID, &category.CreateCategoryRequest{Name: "Pets", Color: &color})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_NameTaken(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(category.ErrCategoryNameTaken)
+
+	resp, err := u.Create(context.Background(), ownerID, &category.CreateCategoryRequest{Name: "Groceries"})
+
+	assert.Equal(t, category.ErrCategoryNameTaken, err)
+	assert.Nil(t, resp)
+}
+
+// ==========================================
+// 4. GROUP: LIST & SEED TESTS
+// ==========================================
+
+func TestList_SeedsDefaultsWhenEmpty(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindAllByUserID", mock.Anything, ownerID).Return([]category.Category{}, nil).Once()
+	mockRepo.On("SaveAll", mock.Anything, mock.MatchedBy(func(categories []category.Category) bool {
+		return len(categories) == len(category.DefaultCategories) && categories[0].IsDefault
+	})).Return(nil)
+	mockRepo.On("FindAllByUserID", mock.Anything, ownerID).Return([]category.Category{{ID: categoryID, UserID: ownerID, Name: "Others", IsDefault: true}}, nil).Once()
+
+	resp, err := u.List(context.Background(), ownerID)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSeedDefaults_RepositoryError(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("SaveAll", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	err := u.SeedDefaults(context.Background(), ownerID)
+
+	assert.Equal(t, category.ErrInternalServer, err)
+}
+
+// ==========================================
+// 5. GROUP: UPDATE & DELETE TESTS
+// ==========================================
+
+func TestUpdate_OtherUsersCategory(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	// Kategori milik user lain tidak akan ditemukan karena query di-scope ke user_id
+	mockRepo.On("FindByID", mock.Anything, categoryID, "intruder").Return(nil, nil)
+
+	name := "Hijacked"
+	resp, err := u.Update(context.Background(), "intruder", categoryID, &category.UpdateCategoryRequest{Name: &name})
+
+	assert.Equal(t, category.ErrCategoryNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestDelete_DefaultCategoryIsProtected(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, categoryID, ownerID).Return(&category.Category{ID: categoryID, UserID: ownerID, IsDefault: true}, nil)
+
+	err := u.Delete(context.Background(), ownerID, categoryID)
+
+	assert.Equal(t, category.ErrDefaultCategory, err)
+	mockRepo.AssertNotCalled(t, "Delete")
+}
+
+func TestDelete_Success(t *testing.T) {
+	u, mockRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, categoryID, ownerID).Return(&category.Category{ID: categoryID, UserID: ownerID}, nil)
+	mockRepo.On("Delete", mock.Anything, categoryID, ownerID).Return(nil)
+
+	err := u.Delete(context.Background(), ownerID, categoryID)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,68 @@
+package history
+
+import "time"
+
+type History struct {
+	ID         string
+	BudgetID   string
+	CategoryID *string
+	AccountID  string
+	// RecurringID: terisi jika history dibuat otomatis dari recurring template
+	RecurringID *string
+	Date        time.Time
+	Amount      float64
+	CreatedAt   time.Time
+}
+
+// HistoryResponse: Format standar data pengeluaran untuk output JSON
+type HistoryResponse struct {
+	ID          string    `json:"id"`
+	BudgetID    string    `json:"budget_id"`
+	CategoryID  *string   `json:"category_id"`
+	AccountID   string    `json:"account_id"`
+	RecurringID *string   `json:"recurring_id"`
+	Date        time.Time `json:"date"`
+	Amount      float64   `json:"amount"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// HistoryMutationResponse: Dikembalikan setiap operasi tulis beserta sisa budget
+type HistoryMutationResponse struct {
+	HistoryResponse
+	RemainingBudget float64 `json:"remaining_budget"`
+}
+
+// CreateHistoryRequest: Validasi input saat mencatat pengeluaran (kategori opsional, akun default jika account_id kosong)
+type CreateHistoryRequest struct {
+	Date       *time.Time `json:"date" validate:"required"`
+	Amount     float64    `json:"amount" validate:"required,gt=0"`
+	CategoryID *string    `json:"category_id" validate:"omitempty,uuid"`
+	AccountID  *string    `json:"account_id" validate:"omitempty,uuid"`
+}
+
+// UpdateHistoryRequest: Semua field opsional (PATCH)
+type UpdateHistoryRequest struct {
+	Date       *time.Time `json:"date"`
+	Amount     *float64   `json:"amount" validate:"omitempty,gt=0"`
+	CategoryID *string    `json:"category_id" validate:"omitempty,uuid"`
+	AccountID  *string    `json:"account_id" validate:"omitempty,uuid"`
+}
+
+// ListHistoryRequest: Filter rentang tanggal (opsional)
+type ListHistoryRequest struct {
+	DateFrom *time.Time
+	DateTo   *time.Time
+}
+
+func toResponse(h *History) *HistoryResponse {
+	return &HistoryResponse{
+		ID:          h.ID,
+		BudgetID:    h.BudgetID,
+		CategoryID:  h.CategoryID,
+		AccountID:   h.AccountID,
+		RecurringID: h.RecurringID,
+		Date:        h.Date,
+		Amount:      h.Amount,
+		CreatedAt:   h.CreatedAt,
+	}
+}
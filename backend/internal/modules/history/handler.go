@@ -0,0 +1,116 @@
+package history
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	useCase UseCase
+}
+
+func NewHandler(useCase UseCase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req CreateHistoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Create(c.Context(), userID, c.Params("budget_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req ListHistoryRequest
+	var err error
+	if req.DateFrom, err = validation.ParseDateQuery("date_from", c.Query("date_from")); err != nil {
+		return err
+	}
+	if req.DateTo, err = validation.ParseDateQuery("date_to", c.Query("date_to")); err != nil {
+		return err
+	}
+
+	resp, err := h.useCase.List(c.Context(), userID, c.Params("budget_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.Get(c.Context(), userID, c.Params("history_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req UpdateHistoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Update(c.Context(), userID, c.Params("history_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	remaining, err := h.useCase.Delete(c.Context(), userID, c.Params("history_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true, "remaining_budget": remaining})
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler) {
+	budgets := app.Group("/api/budgets")
+	budgets.Post("/:budget_id/history", authMiddleware, h.Create)
+	budgets.Get("/:budget_id/history", authMiddleware, h.List)
+
+	api := app.Group("/api/history")
+	api.Get("/:history_id", authMiddleware, h.Get)
+	api.Patch("/:history_id", authMiddleware, h.Update)
+	api.Delete("/:history_id", authMiddleware, h.Delete)
+}
@@ -0,0 +1,190 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/database"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrHistoryNotFound = apperror.NotFound("history_not_found", "history not found")
+)
+
+// Repository: Kepemilikan budget dicek di UseCase sebelum memanggil method di sini
+type Repository interface {
+	Save(ctx context.Context, history *History) error
+	FindByID(ctx context.Context, id string) (*History, error)
+	FindAllByBudgetID(ctx context.Context, budgetID string, dateFrom, dateTo *time.Time) ([]History, error)
+	SumByBudgetID(ctx context.Context, budgetID string) (float64, error)
+	Update(ctx context.Context, history *History) error
+	Delete(ctx context.Context, id string) error
+}
+
+// historyColumns: Urutan kolom harus sama dengan urutan Scan
+const historyColumns = `id, budget_id, category_id, account_id, recurring_id, date, amount, created_at`
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &repository{db: db}
+}
+
+// Save: ErrBudgetClosed jika budget induk sudah ditutup
+func (r *repository) Save(ctx context.Context, history *History) error {
+	inserted, err := Insert(ctx, r.db, history)
+	if err != nil {
+		return err
+	}
+	if !inserted {
+		return budget.ErrBudgetClosed
+	}
+	return nil
+}
+
+// Insert adalah satu-satunya jalur INSERT ke histories, dipakai Save dan scheduler recurring
+// (di dalam transaksinya sendiri). Baris hanya dibuat jika budget induk belum ditutup (FOR SHARE
+// menunggu Close yang sedang berjalan); false berarti budget sudah ditutup atau kejadian recurring
+// yang sama sudah pernah dibuat.
+func Insert(ctx context.Context, db database.Execer, history *History) (bool, error) {
+	query := `
+		INSERT INTO histories (id, budget_id, category_id, account_id, recurring_id, date, amount, created_at)
+		SELECT $1, b.id, $3, $4, $5, $6, $7, $8 FROM monthly_budgets b
+		WHERE b.id = $2 AND b.closed_at IS NULL
+		FOR SHARE
+		ON CONFLICT (recurring_id, date) WHERE recurring_id IS NOT NULL DO NOTHING
+	`
+	tag, err := db.Exec(ctx, query,
+		history.ID, history.BudgetID, history.CategoryID, history.AccountID, history.RecurringID, history.Date, history.Amount, history.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*History, error) {
+	query := `SELECT ` + historyColumns + ` FROM histories WHERE id = $1`
+
+	var history History
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&history.ID, &history.BudgetID, &history.CategoryID, &history.AccountID, &history.RecurringID, &history.Date, &history.Amount, &history.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &history, nil
+}
+
+func (r *repository) FindAllByBudgetID(ctx context.Context, budgetID string, dateFrom, dateTo *time.Time) ([]History, error) {
+	query := `
+		SELECT ` + historyColumns + ` FROM histories
+		WHERE budget_id = $1
+		  AND ($2::timestamptz IS NULL OR date >= $2)
+		  AND ($3::timestamptz IS NULL OR date <= $3)
+		ORDER BY date DESC
+	`
+	rows, err := r.db.Query(ctx, query, budgetID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	histories := make([]History, 0)
+	for rows.Next() {
+		var history History
+		if err := rows.Scan(&history.ID, &history.BudgetID, &history.CategoryID, &history.AccountID, &history.RecurringID, &history.Date, &history.Amount, &history.CreatedAt); err != nil {
+			return nil, err
+		}
+		histories = append(histories, history)
+	}
+	return histories, rows.Err()
+}
+
+func (r *repository) SumByBudgetID(ctx context.Context, budgetID string) (float64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM histories WHERE budget_id = $1`
+
+	var total float64
+	if err := r.db.QueryRow(ctx, query, budgetID).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// Update & Delete: ErrBudgetClosed jika budget induk sudah ditutup. Budget induk dikunci FOR SHARE
+// sampai transaksi selesai, sehingga Close yang berjalan paralel menunggu (atau ditunggu).
+func (r *repository) Update(ctx context.Context, history *History) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockOpenParent(ctx, tx, history.ID); err != nil {
+		return err
+	}
+
+	query := `UPDATE histories SET date = $1, amount = $2, category_id = $3, account_id = $4 WHERE id = $5`
+	tag, err := tx.Exec(ctx, query, history.Date, history.Amount, history.CategoryID, history.AccountID, history.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrHistoryNotFound
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockOpenParent(ctx, tx, id); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM histories WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrHistoryNotFound
+	}
+	return tx.Commit(ctx)
+}
+
+// lockOpenParent mengunci budget induk history (FOR SHARE) dan memastikan belum ditutup
+func lockOpenParent(ctx context.Context, tx pgx.Tx, historyID string) error {
+	query := `
+		SELECT b.closed_at IS NOT NULL
+		FROM histories h
+		JOIN monthly_budgets b ON b.id = h.budget_id
+		WHERE h.id = $1
+		FOR SHARE OF b
+	`
+	var closed bool
+	err := tx.QueryRow(ctx, query, historyID).Scan(&closed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrHistoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if closed {
+		return budget.ErrBudgetClosed
+	}
+	return nil
+}
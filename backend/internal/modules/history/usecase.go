@@ -0,0 +1,280 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/account"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/category"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInternalServer = apperror.ErrInternal
+	ErrInvalidDate    = apperror.BadRequest("invalid_date_range", "date_from must be before date_to")
+)
+
+type UseCase interface {
+	Create(ctx context.Context, userID string, budgetID string, req *CreateHistoryRequest) (*HistoryMutationResponse, error)
+	List(ctx context.Context, userID string, budgetID string, req *ListHistoryRequest) ([]HistoryResponse, error)
+	Get(ctx context.Context, userID string, historyID string) (*HistoryResponse, error)
+	Update(ctx context.Context, userID string, historyID string, req *UpdateHistoryRequest) (*HistoryMutationResponse, error)
+	Delete(ctx context.Context, userID string, historyID string) (float64, error)
+}
+
+type useCase struct {
+	repo         Repository
+	budgetRepo   budget.Repository
+	categoryRepo category.Repository
+	accountRepo  account.Repository
+	log          *logrus.Logger
+	validate     *validator.Validate
+}
+
+func NewUseCase(repo Repository, budgetRepo budget.Repository, categoryRepo category.Repository, accountRepo account.Repository, log *logrus.Logger, validate *validator.Validate) UseCase {
+	return &useCase{
+		repo:         repo,
+		budgetRepo:   budgetRepo,
+		categoryRepo: categoryRepo,
+		accountRepo:  accountRepo,
+		log:          log,
+		validate:     validate,
+	}
+}
+
+func (u *useCase) Create(ctx context.Context, userID string, budgetID string, req *CreateHistoryRequest) (*HistoryMutationResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan budget induk, kategori dan akun (jika diisi) milik user
+	parent, err := u.findOwnedBudget(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.ClosedAt != nil {
+		return nil, budget.ErrBudgetClosed
+	}
+	if err := u.checkCategory(ctx, userID, req.CategoryID); err != nil {
+		return nil, err
+	}
+	accountID, err := account.Resolve(ctx, u.accountRepo, userID, req.AccountID)
+	if err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to resolve account")
+		return nil, ErrInternalServer
+	}
+
+	// 3. Construct Entity
+	newHistory := &History{
+		ID:         uuid.New().String(),
+		BudgetID:   parent.ID,
+		CategoryID: req.CategoryID,
+		AccountID:  accountID,
+		Date:       *req.Date,
+		Amount:     req.Amount,
+		CreatedAt:  time.Now(),
+	}
+
+	// 4. Simpan ke DB
+	if err := u.repo.Save(ctx, newHistory); err != nil {
+		if errors.Is(err, budget.ErrBudgetClosed) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to save history")
+		return nil, ErrInternalServer
+	}
+
+	return u.mutationResponse(ctx, parent, newHistory)
+}
+
+func (u *useCase) List(ctx context.Context, userID string, budgetID string, req *ListHistoryRequest) ([]HistoryResponse, error) {
+	if req.DateFrom != nil && req.DateTo != nil && req.DateFrom.After(*req.DateTo) {
+		return nil, ErrInvalidDate
+	}
+
+	parent, err := u.findOwnedBudget(ctx, userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	histories, err := u.repo.FindAllByBudgetID(ctx, parent.ID, req.DateFrom, req.DateTo)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list histories")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]HistoryResponse, 0, len(histories))
+	for i := range histories {
+		resp = append(resp, *toResponse(&histories[i]))
+	}
+	return resp, nil
+}
+
+func (u *useCase) Get(ctx context.Context, userID string, historyID string) (*HistoryResponse, error) {
+	history, _, err := u.findOwnedHistory(ctx, userID, historyID)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(history), nil
+}
+
+func (u *useCase) Update(ctx context.Context, userID string, historyID string, req *UpdateHistoryRequest) (*HistoryMutationResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan history (lewat budget induknya), kategori dan akun baru milik user
+	history, parent, err := u.findOwnedHistory(ctx, userID, historyID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.ClosedAt != nil {
+		return nil, budget.ErrBudgetClosed
+	}
+	if err := u.checkCategory(ctx, userID, req.CategoryID); err != nil {
+		return nil, err
+	}
+	if req.AccountID != nil {
+		if history.AccountID, err = account.Resolve(ctx, u.accountRepo, userID, req.AccountID); err != nil {
+			if errors.Is(err, account.ErrAccountNotFound) {
+				return nil, err
+			}
+			u.log.WithError(err).Error("Failed to resolve account")
+			return nil, ErrInternalServer
+		}
+	}
+
+	// 3. Terapkan perubahan parsial
+	if req.Date != nil {
+		history.Date = *req.Date
+	}
+	if req.Amount != nil {
+		history.Amount = *req.Amount
+	}
+	if req.CategoryID != nil {
+		history.CategoryID = req.CategoryID
+	}
+
+	// 4. Simpan perubahan
+	if err := u.repo.Update(ctx, history); err != nil {
+		if errors.Is(err, ErrHistoryNotFound) || errors.Is(err, budget.ErrBudgetClosed) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to update history")
+		return nil, ErrInternalServer
+	}
+
+	return u.mutationResponse(ctx, parent, history)
+}
+
+func (u *useCase) Delete(ctx context.Context, userID string, historyID string) (float64, error) {
+	_, parent, err := u.findOwnedHistory(ctx, userID, historyID)
+	if err != nil {
+		return 0, err
+	}
+	// Pengeluaran bulan yang sudah ditutup sudah dihitung ke carry-over bulan berikutnya
+	if parent.ClosedAt != nil {
+		return 0, budget.ErrBudgetClosed
+	}
+
+	if err := u.repo.Delete(ctx, historyID); err != nil {
+		if errors.Is(err, ErrHistoryNotFound) || errors.Is(err, budget.ErrBudgetClosed) {
+			return 0, err
+		}
+		u.log.WithError(err).Error("Failed to delete history")
+		return 0, ErrInternalServer
+	}
+
+	return u.remainingBudget(ctx, parent)
+}
+
+// findOwnedBudget memastikan budget induk ada dan dimiliki userID
+func (u *useCase) findOwnedBudget(ctx context.Context, userID string, budgetID string) (*budget.Budget, error) {
+	if _, err := uuid.Parse(budgetID); err != nil {
+		return nil, budget.ErrBudgetNotFound
+	}
+
+	parent, err := u.budgetRepo.FindByID(ctx, budgetID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find budget")
+		return nil, ErrInternalServer
+	}
+	if parent == nil {
+		return nil, budget.ErrBudgetNotFound
+	}
+	return parent, nil
+}
+
+// checkCategory: kategori opsional, tapi jika diisi harus milik user yang sama
+func (u *useCase) checkCategory(ctx context.Context, userID string, categoryID *string) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	owned, err := u.categoryRepo.FindByID(ctx, *categoryID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find category")
+		return ErrInternalServer
+	}
+	if owned == nil {
+		return category.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// findOwnedHistory mengambil history beserta budget induknya.
+// History milik user lain dilaporkan sebagai not found agar keberadaannya tidak bocor.
+func (u *useCase) findOwnedHistory(ctx context.Context, userID string, historyID string) (*History, *budget.Budget, error) {
+	if _, err := uuid.Parse(historyID); err != nil {
+		return nil, nil, ErrHistoryNotFound
+	}
+
+	history, err := u.repo.FindByID(ctx, historyID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find history")
+		return nil, nil, ErrInternalServer
+	}
+	if history == nil {
+		return nil, nil, ErrHistoryNotFound
+	}
+
+	parent, err := u.findOwnedBudget(ctx, userID, history.BudgetID)
+	if err != nil {
+		if errors.Is(err, budget.ErrBudgetNotFound) {
+			return nil, nil, ErrHistoryNotFound
+		}
+		return nil, nil, err
+	}
+	return history, parent, nil
+}
+
+// remainingBudget = budget - total pengeluaran pada budget tersebut
+func (u *useCase) remainingBudget(ctx context.Context, parent *budget.Budget) (float64, error) {
+	spent, err := u.repo.SumByBudgetID(ctx, parent.ID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to sum histories")
+		return 0, ErrInternalServer
+	}
+	return parent.Available() - spent, nil
+}
+
+func (u *useCase) mutationResponse(ctx context.Context, parent *budget.Budget, history *History) (*HistoryMutationResponse, error) {
+	remaining, err := u.remainingBudget(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoryMutationResponse{
+		HistoryResponse: *toResponse(history),
+		RemainingBudget: remaining,
+	}, nil
+}
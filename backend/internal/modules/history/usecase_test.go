@@ -0,0 +1,602 @@
+package history_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/account"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/category"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/history"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==========================================
+// 1. MOCK OBJECTS
+// ==========================================
+
+// MockRepository memalsukan behavior history.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, h *history.History) error {
+	args := m.Called(ctx, h)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id string) (*history.History, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*history.History), args.Error(1)
+}
+
+func (m *MockRepository) FindAllByBudgetID(ctx context.Context, budgetID string, dateFrom, dateTo *time.Time) ([]history.History, error) {
+	args := m.Called(ctx, budgetID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]history.History), args.Error(1)
+}
+
+func (m *MockRepository) SumByBudgetID(ctx context.Context, budgetID string) (float64, error) {
+	args := m.Called(ctx, budgetID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, h *history.History) error {
+	args := m.Called(ctx, h)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockBudgetRepository memalsukan behavior budget.Repository
+type MockBudgetRepository struct {
+	mock.Mock
+}
+
+func (m *MockBudgetRepository) Save(ctx context.Context, b *budget.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) FindByID(ctx context.Context, id string, userID string) (*budget.Budget, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]budget.Budget, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Update(ctx context.Context, b *budget.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) SetIncomeBudget(ctx context.Context, id string, income float64) (bool, error) {
+	args := m.Called(ctx, id, income)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) SumByCategory(ctx context.Context, budgetID string) ([]budget.CategoryTotal, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.CategoryTotal), args.Error(1)
+}
+
+func (m *MockBudgetRepository) FindAllocations(ctx context.Context, budgetID string) ([]budget.Allocation, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.Allocation), args.Error(1)
+}
+
+func (m *MockBudgetRepository) SetAllocation(ctx context.Context, a *budget.Allocation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) DeleteAllocation(ctx context.Context, budgetID string, categoryID string) error {
+	args := m.Called(ctx, budgetID, categoryID)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) MoveAllocation(ctx context.Context, budgetID string, fromCategoryID string, toCategoryID string, amount float64, at time.Time) error {
+	args := m.Called(ctx, budgetID, fromCategoryID, toCategoryID, amount, at)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) FindByMonth(ctx context.Context, userID string, monthStart time.Time) (*budget.Budget, error) {
+	args := m.Called(ctx, userID, monthStart)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Close(ctx context.Context, b *budget.Budget, next *budget.Budget, createNext bool) error {
+	args := m.Called(ctx, b, next, createNext)
+	return args.Error(0)
+}
+
+// MockCategoryRepository memalsukan behavior category.Repository
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Save(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) SaveAll(ctx context.Context, categories []category.Category) error {
+	args := m.Called(ctx, categories)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id string, userID string) (*category.Category, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindAllByUserID(ctx context.Context, userID string) ([]category.Category, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// MockAccountRepository memalsukan behavior account.Repository
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Save(ctx context.Context, a *account.Account) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindByID(ctx context.Context, id string, userID string) (*account.Account, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) FindAllByUserID(ctx context.Context, userID string) ([]account.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) Update(ctx context.Context, a *account.Account) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) EnsureDefault(ctx context.Context, userID string) (*account.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) SaveTransfer(ctx context.Context, t *account.Transfer) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindTransfers(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]account.Transfer, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.Transfer), args.Error(1)
+}
+
+func (m *MockAccountRepository) DeleteTransfer(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) Ledger(ctx context.Context, accountID string, dateFrom, dateTo *time.Time) ([]account.LedgerEntry, error) {
+	args := m.Called(ctx, accountID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.LedgerEntry), args.Error(1)
+}
+
+// ==========================================
+// 2. HELPER SETUP
+// ==========================================
+
+const (
+	ownerID   = "11111111-1111-1111-1111-111111111111"
+	budgetID  = "22222222-2222-2222-2222-222222222222"
+	historyID = "33333333-3333-3333-3333-333333333333"
+)
+
+const categoryID = "44444444-4444-4444-4444-444444444444"
+
+const (
+	defaultAccountID = "55555555-5555-5555-5555-555555555555"
+	savingsAccountID = "66666666-6666-6666-6666-666666666666"
+)
+
+func setupTest() (history.UseCase, *MockRepository, *MockBudgetRepository) {
+	u, mockRepo, mockBudgetRepo, _ := setupTestWithCategories()
+	return u, mockRepo, mockBudgetRepo
+}
+
+func setupTestWithCategories() (history.UseCase, *MockRepository, *MockBudgetRepository, *MockCategoryRepository) {
+	u, mockRepo, mockBudgetRepo, mockCategoryRepo, _ := setupTestWithAccounts()
+	return u, mockRepo, mockBudgetRepo, mockCategoryRepo
+}
+
+func setupTestWithAccounts() (history.UseCase, *MockRepository, *MockBudgetRepository, *MockCategoryRepository, *MockAccountRepository) {
+	mockRepo := new(MockRepository)
+	mockBudgetRepo := new(MockBudgetRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockAccountRepo := new(MockAccountRepository)
+
+	// Transaksi tanpa account_id masuk ke akun default
+	mockAccountRepo.On("EnsureDefault", mock.Anything, ownerID).
+		Return(&account.Account{ID: defaultAccountID, UserID: ownerID, Name: account.DefaultAccountName, IsDefault: true}, nil).Maybe()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	u := history.NewUseCase(mockRepo, mockBudgetRepo, mockCategoryRepo, mockAccountRepo, log, validator.New())
+	return u, mockRepo, mockBudgetRepo, mockCategoryRepo, mockAccountRepo
+}
+
+func ownedBudget() *budget.Budget {
+	return &budget.Budget{ID: budgetID, UserID: ownerID, Budget: 1000}
+}
+
+func closedBudget() *budget.Budget {
+	b := ownedBudget()
+	closedAt := time.Now()
+	b.ClosedAt = &closedAt
+	return b
+}
+
+// ==========================================
+// 3. GROUP: CREATE TESTS
+// ==========================================
+
+func TestCreate_Success(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	date := time.Now()
+	req := &history.CreateHistoryRequest{Date: &date, Amount: 150}
+
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(h *history.History) bool {
+		return h.BudgetID == budgetID && h.Amount == 150 && h.ID != ""
+	})).Return(nil)
+	mockRepo.On("SumByBudgetID", mock.Anything, budgetID).Return(400.0, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, budgetID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 600.0, resp.RemainingBudget)
+	assert.Equal(t, budgetID, resp.BudgetID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreate_InvalidAmount(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	date := time.Now()
+	resp, err := u.Create(context.Background(), ownerID, budgetID, &history.CreateHistoryRequest{Date: &date, Amount: -5})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockBudgetRepo.AssertNotCalled(t, "FindByID")
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_BudgetNotOwned(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	date := time.Now()
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, "intruder").Return(nil, nil)
+
+	resp, err := u.Create(context.Background(), "intruder", budgetID, &history.CreateHistoryRequest{Date: &date, Amount: 10})
+
+	assert.Equal(t, budget.ErrBudgetNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_ClosedBudgetRejected(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	date := time.Now()
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(closedBudget(), nil)
+
+	resp, err := u.Create(context.Background(), ownerID, budgetID, &history.CreateHistoryRequest{Date: &date, Amount: 150})
+
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestCreate_BudgetClosedWhileSaving(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	date := time.Now()
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(budget.ErrBudgetClosed)
+
+	resp, err := u.Create(context.Background(), ownerID, budgetID, &history.CreateHistoryRequest{Date: &date, Amount: 150})
+
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+	assert.Nil(t, resp)
+}
+
+func TestCreate_WithCategory(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, mockCategoryRepo := setupTestWithCategories()
+
+	date := time.Now()
+	id := categoryID
+	req := &history.CreateHistoryRequest{Date: &date, Amount: 150, CategoryID: &id}
+
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockCategoryRepo.On("FindByID", mock.Anything, categoryID, ownerID).Return(&category.Category{ID: categoryID, UserID: ownerID}, nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(h *history.History) bool {
+		return h.CategoryID != nil && *h.CategoryID == categoryID
+	})).Return(nil)
+	mockRepo.On("SumByBudgetID", mock.Anything, budgetID).Return(150.0, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, budgetID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, categoryID, *resp.CategoryID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreate_CategoryNotOwned(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, mockCategoryRepo := setupTestWithCategories()
+
+	date := time.Now()
+	id := categoryID
+	req := &history.CreateHistoryRequest{Date: &date, Amount: 150, CategoryID: &id}
+
+	// Kategori milik user lain tidak ditemukan karena query di-scope ke user_id
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockCategoryRepo.On("FindByID", mock.Anything, categoryID, ownerID).Return(nil, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, budgetID, req)
+
+	assert.Equal(t, category.ErrCategoryNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_DefaultsToDefaultAccount(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	date := time.Now()
+	req := &history.CreateHistoryRequest{Date: &date, Amount: 150}
+
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(h *history.History) bool {
+		return h.AccountID == defaultAccountID
+	})).Return(nil)
+	mockRepo.On("SumByBudgetID", mock.Anything, budgetID).Return(150.0, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, budgetID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaultAccountID, resp.AccountID)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreate_WithAccount(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _, mockAccountRepo := setupTestWithAccounts()
+
+	date := time.Now()
+	id := savingsAccountID
+	req := &history.CreateHistoryRequest{Date: &date, Amount: 150, AccountID: &id}
+
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockAccountRepo.On("FindByID", mock.Anything, savingsAccountID, ownerID).Return(&account.Account{ID: savingsAccountID, UserID: ownerID}, nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(h *history.History) bool {
+		return h.AccountID == savingsAccountID
+	})).Return(nil)
+	mockRepo.On("SumByBudgetID", mock.Anything, budgetID).Return(150.0, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, budgetID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, savingsAccountID, resp.AccountID)
+	mockAccountRepo.AssertNotCalled(t, "EnsureDefault", mock.Anything, mock.Anything)
+}
+
+func TestCreate_AccountNotOwned(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _, mockAccountRepo := setupTestWithAccounts()
+
+	date := time.Now()
+	id := savingsAccountID
+	req := &history.CreateHistoryRequest{Date: &date, Amount: 150, AccountID: &id}
+
+	// Akun milik user lain tidak ditemukan karena query di-scope ke user_id
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockAccountRepo.On("FindByID", mock.Anything, savingsAccountID, ownerID).Return(nil, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, budgetID, req)
+
+	assert.Equal(t, account.ErrAccountNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+// ==========================================
+// 4. GROUP: GET / UPDATE / DELETE TESTS
+// ==========================================
+
+func TestGet_HistoryOfOtherUser(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, historyID).Return(&history.History{ID: historyID, BudgetID: budgetID}, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, "intruder").Return(nil, nil)
+
+	resp, err := u.Get(context.Background(), "intruder", historyID)
+
+	// Tidak boleh membocorkan bahwa history tersebut ada
+	assert.Equal(t, history.ErrHistoryNotFound, err)
+	assert.Nil(t, resp)
+}
+
+func TestUpdate_Success(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	newAmount := 300.0
+	existing := &history.History{ID: historyID, BudgetID: budgetID, Amount: 100}
+
+	mockRepo.On("FindByID", mock.Anything, historyID).Return(existing, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(h *history.History) bool {
+		return h.Amount == newAmount
+	})).Return(nil)
+	mockRepo.On("SumByBudgetID", mock.Anything, budgetID).Return(300.0, nil)
+
+	resp, err := u.Update(context.Background(), ownerID, historyID, &history.UpdateHistoryRequest{Amount: &newAmount})
+
+	assert.NoError(t, err)
+	assert.Equal(t, newAmount, resp.Amount)
+	assert.Equal(t, 700.0, resp.RemainingBudget)
+}
+
+func TestUpdate_ClosedBudgetRejected(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	newAmount := 300.0
+	mockRepo.On("FindByID", mock.Anything, historyID).Return(&history.History{ID: historyID, BudgetID: budgetID, Amount: 100}, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(closedBudget(), nil)
+
+	resp, err := u.Update(context.Background(), ownerID, historyID, &history.UpdateHistoryRequest{Amount: &newAmount})
+
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestUpdate_BudgetClosedWhileSaving(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	newAmount := 300.0
+	mockRepo.On("FindByID", mock.Anything, historyID).Return(&history.History{ID: historyID, BudgetID: budgetID, Amount: 100}, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	// Close paralel menang: repository mengunci budget induk dan menolak perubahan
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(budget.ErrBudgetClosed)
+
+	resp, err := u.Update(context.Background(), ownerID, historyID, &history.UpdateHistoryRequest{Amount: &newAmount})
+
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+	assert.Nil(t, resp)
+}
+
+func TestDelete_ReturnsRemaining(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, historyID).Return(&history.History{ID: historyID, BudgetID: budgetID}, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("Delete", mock.Anything, historyID).Return(nil)
+	mockRepo.On("SumByBudgetID", mock.Anything, budgetID).Return(0.0, nil)
+
+	remaining, err := u.Delete(context.Background(), ownerID, historyID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1000.0, remaining)
+}
+
+func TestDelete_ClosedBudgetRejected(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, historyID).Return(&history.History{ID: historyID, BudgetID: budgetID}, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(closedBudget(), nil)
+
+	_, err := u.Delete(context.Background(), ownerID, historyID)
+
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+	mockRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestDelete_BudgetClosedWhileDeleting(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, historyID).Return(&history.History{ID: historyID, BudgetID: budgetID}, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("Delete", mock.Anything, historyID).Return(budget.ErrBudgetClosed)
+
+	_, err := u.Delete(context.Background(), ownerID, historyID)
+
+	assert.Equal(t, budget.ErrBudgetClosed, err)
+}
+
+func TestDelete_RepositoryError(t *testing.T) {
+	u, mockRepo, mockBudgetRepo := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, historyID).Return(&history.History{ID: historyID, BudgetID: budgetID}, nil)
+	mockBudgetRepo.On("FindByID", mock.Anything, budgetID, ownerID).Return(ownedBudget(), nil)
+	mockRepo.On("Delete", mock.Anything, historyID).Return(errors.New("db down"))
+
+	_, err := u.Delete(context.Background(), ownerID, historyID)
+
+	assert.Equal(t, history.ErrInternalServer, err)
+}
@@ -0,0 +1,95 @@
+package income
+
+import "time"
+
+// Sumber pemasukan yang didukung (harus sama dengan validasi oneof di request)
+const (
+	SourceSalary     = "salary"
+	SourceBonus      = "bonus"
+	SourceFreelance  = "freelance"
+	SourceInvestment = "investment"
+	SourceGift       = "gift"
+	SourceOther      = "other"
+)
+
+// Income: Pemasukan (gaji, bonus, freelance, ...); terpisah dari histories yang mencatat pengeluaran
+type Income struct {
+	ID          string
+	UserID      string
+	AccountID   string
+	Source      string
+	Description *string
+	Amount      float64
+	Date        time.Time
+	CreatedAt   time.Time
+}
+
+// IncomeResponse: Format standar data pemasukan untuk output JSON
+type IncomeResponse struct {
+	ID          string    `json:"id"`
+	AccountID   string    `json:"account_id"`
+	Source      string    `json:"source"`
+	Description *string   `json:"description"`
+	Amount      float64   `json:"amount"`
+	Date        time.Time `json:"date"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// SourceTotal: Total pemasukan satu sumber dalam rentang tanggal
+type SourceTotal struct {
+	Source string  `json:"source"`
+	Total  float64 `json:"total"`
+}
+
+// MonthSummaryResponse: GET /api/summary/{month}, pemasukan vs pengeluaran satu bulan.
+// SavingsRate (persen) null jika belum ada pemasukan; BudgetID null jika bulan itu belum punya budget.
+type MonthSummaryResponse struct {
+	Month          string        `json:"month"`
+	Income         float64       `json:"income"`
+	IncomeBySource []SourceTotal `json:"income_by_source"`
+	Expenses       float64       `json:"expenses"`
+	NetSavings     float64       `json:"net_savings"`
+	SavingsRate    *float64      `json:"savings_rate"`
+	BudgetID       *string       `json:"budget_id"`
+}
+
+// CreateIncomeRequest: Validasi input saat mencatat pemasukan (akun default jika account_id kosong)
+type CreateIncomeRequest struct {
+	Source      string     `json:"source" validate:"required,oneof=salary bonus freelance investment gift other"`
+	Description *string    `json:"description" validate:"omitempty,max=255"`
+	Amount      float64    `json:"amount" validate:"required,gt=0"`
+	Date        *time.Time `json:"date" validate:"required"`
+	AccountID   *string    `json:"account_id" validate:"omitempty,uuid"`
+}
+
+// UpdateIncomeRequest: Semua field opsional (PATCH)
+type UpdateIncomeRequest struct {
+	Source      *string    `json:"source" validate:"omitempty,oneof=salary bonus freelance investment gift other"`
+	Description *string    `json:"description" validate:"omitempty,max=255"`
+	Amount      *float64   `json:"amount" validate:"omitempty,gt=0"`
+	Date        *time.Time `json:"date"`
+	AccountID   *string    `json:"account_id" validate:"omitempty,uuid"`
+}
+
+// ListIncomeRequest: Filter rentang tanggal (opsional)
+type ListIncomeRequest struct {
+	DateFrom *time.Time
+	DateTo   *time.Time
+}
+
+// MonthSummaryRequest: Bulan dalam format YYYY-MM
+type MonthSummaryRequest struct {
+	Month string `validate:"required,datetime=2006-01"`
+}
+
+func toResponse(i *Income) *IncomeResponse {
+	return &IncomeResponse{
+		ID:          i.ID,
+		AccountID:   i.AccountID,
+		Source:      i.Source,
+		Description: i.Description,
+		Amount:      i.Amount,
+		Date:        i.Date,
+		CreatedAt:   i.CreatedAt,
+	}
+}
@@ -0,0 +1,132 @@
+package income
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/validation"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Handler struct {
+	useCase UseCase
+}
+
+func NewHandler(useCase UseCase) *Handler {
+	return &Handler{useCase: useCase}
+}
+
+func (h *Handler) Create(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req CreateIncomeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Create(c.Context(), userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) List(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	// Query param date_from & date_to (format ISO8601 / RFC3339)
+	var req ListIncomeRequest
+	var err error
+	if req.DateFrom, err = validation.ParseDateQuery("date_from", c.Query("date_from")); err != nil {
+		return err
+	}
+	if req.DateTo, err = validation.ParseDateQuery("date_to", c.Query("date_to")); err != nil {
+		return err
+	}
+
+	resp, err := h.useCase.List(c.Context(), userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Get(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.Get(c.Context(), userID, c.Params("income_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Update(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req UpdateIncomeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Update(c.Context(), userID, c.Params("income_id"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Delete(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.Delete(c.Context(), userID, c.Params("income_id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) MonthSummary(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.MonthSummary(c.Context(), userID, &MonthSummaryRequest{Month: c.Params("month")})
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler) {
+	api := app.Group("/api/incomes")
+
+	api.Post("/", authMiddleware, h.Create)
+	api.Get("/", authMiddleware, h.List)
+	api.Get("/:income_id", authMiddleware, h.Get)
+	api.Patch("/:income_id", authMiddleware, h.Update)
+	api.Delete("/:income_id", authMiddleware, h.Delete)
+
+	// Ringkasan bulanan: pemasukan vs pengeluaran
+	app.Get("/api/summary/:month", authMiddleware, h.MonthSummary)
+}
@@ -0,0 +1,157 @@
+package income
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrIncomeNotFound = apperror.NotFound("income_not_found", "income not found")
+)
+
+// Repository: Semua query WAJIB di-scope ke user_id
+type Repository interface {
+	Save(ctx context.Context, income *Income) error
+	FindByID(ctx context.Context, id string, userID string) (*Income, error)
+	FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]Income, error)
+	Update(ctx context.Context, income *Income) error
+	Delete(ctx context.Context, id string, userID string) error
+
+	// SumByMonth: total pemasukan di bulan monthStart (dipakai budget persentase)
+	SumByMonth(ctx context.Context, userID string, monthStart time.Time) (float64, error)
+	// SumBySource: total pemasukan per sumber di [from, to), terbesar dulu
+	SumBySource(ctx context.Context, userID string, from time.Time, to time.Time) ([]SourceTotal, error)
+}
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &repository{db: db}
+}
+
+// incomeColumns: Urutan kolom harus sama dengan urutan scanIncome
+const incomeColumns = `id, user_id, account_id, source, description, amount, date, created_at`
+
+func scanIncome(row pgx.Row, i *Income) error {
+	return row.Scan(&i.ID, &i.UserID, &i.AccountID, &i.Source, &i.Description, &i.Amount, &i.Date, &i.CreatedAt)
+}
+
+func (r *repository) Save(ctx context.Context, income *Income) error {
+	query := `
+		INSERT INTO incomes (` + incomeColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	_, err := r.db.Exec(ctx, query,
+		income.ID, income.UserID, income.AccountID, income.Source, income.Description, income.Amount, income.Date, income.CreatedAt,
+	)
+	return err
+}
+
+func (r *repository) FindByID(ctx context.Context, id string, userID string) (*Income, error) {
+	query := `SELECT ` + incomeColumns + ` FROM incomes WHERE id = $1 AND user_id = $2`
+
+	var income Income
+	if err := scanIncome(r.db.QueryRow(ctx, query, id, userID), &income); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &income, nil
+}
+
+func (r *repository) FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]Income, error) {
+	// Filter tanggal opsional: NULL berarti tidak dibatasi
+	query := `
+		SELECT ` + incomeColumns + ` FROM incomes
+		WHERE user_id = $1
+		  AND ($2::timestamptz IS NULL OR date >= $2)
+		  AND ($3::timestamptz IS NULL OR date <= $3)
+		ORDER BY date DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	incomes := make([]Income, 0)
+	for rows.Next() {
+		var income Income
+		if err := scanIncome(rows, &income); err != nil {
+			return nil, err
+		}
+		incomes = append(incomes, income)
+	}
+	return incomes, rows.Err()
+}
+
+func (r *repository) Update(ctx context.Context, income *Income) error {
+	query := `
+		UPDATE incomes
+		SET account_id = $1, source = $2, description = $3, amount = $4, date = $5
+		WHERE id = $6 AND user_id = $7
+	`
+	tag, err := r.db.Exec(ctx, query,
+		income.AccountID, income.Source, income.Description, income.Amount, income.Date,
+		income.ID, income.UserID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrIncomeNotFound
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string, userID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM incomes WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrIncomeNotFound
+	}
+	return nil
+}
+
+func (r *repository) SumByMonth(ctx context.Context, userID string, monthStart time.Time) (float64, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM incomes WHERE user_id = $1 AND date >= $2 AND date < $3`
+
+	var total float64
+	err := r.db.QueryRow(ctx, query, userID, monthStart, monthStart.AddDate(0, 1, 0)).Scan(&total)
+	return total, err
+}
+
+func (r *repository) SumBySource(ctx context.Context, userID string, from time.Time, to time.Time) ([]SourceTotal, error) {
+	query := `
+		SELECT source, SUM(amount) AS total
+		FROM incomes
+		WHERE user_id = $1 AND date >= $2 AND date < $3
+		GROUP BY source
+		ORDER BY total DESC
+	`
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make([]SourceTotal, 0)
+	for rows.Next() {
+		var total SourceTotal
+		if err := rows.Scan(&total.Source, &total.Total); err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+	return totals, rows.Err()
+}
@@ -0,0 +1,273 @@
+package income
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/account"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInternalServer = apperror.ErrInternal
+	ErrInvalidDate    = apperror.BadRequest("invalid_date_range", "date_from must be before date_to")
+)
+
+// BudgetSyncer: Menghitung ulang budget persentase setelah pemasukan sebulan berubah (module budget)
+type BudgetSyncer interface {
+	SyncIncome(ctx context.Context, userID string, monthStart time.Time) error
+}
+
+type UseCase interface {
+	Create(ctx context.Context, userID string, req *CreateIncomeRequest) (*IncomeResponse, error)
+	List(ctx context.Context, userID string, req *ListIncomeRequest) ([]IncomeResponse, error)
+	Get(ctx context.Context, userID string, incomeID string) (*IncomeResponse, error)
+	Update(ctx context.Context, userID string, incomeID string, req *UpdateIncomeRequest) (*IncomeResponse, error)
+	Delete(ctx context.Context, userID string, incomeID string) error
+
+	// MonthSummary: pemasukan, pengeluaran (histories budget bulan itu), tabungan bersih & savings rate
+	MonthSummary(ctx context.Context, userID string, req *MonthSummaryRequest) (*MonthSummaryResponse, error)
+}
+
+type useCase struct {
+	repo        Repository
+	budgetRepo  budget.Repository
+	accountRepo account.Repository
+	budgets     BudgetSyncer
+	log         *logrus.Logger
+	validate    *validator.Validate
+}
+
+func NewUseCase(repo Repository, budgetRepo budget.Repository, accountRepo account.Repository, budgets BudgetSyncer, log *logrus.Logger, validate *validator.Validate) UseCase {
+	return &useCase{
+		repo:        repo,
+		budgetRepo:  budgetRepo,
+		accountRepo: accountRepo,
+		budgets:     budgets,
+		log:         log,
+		validate:    validate,
+	}
+}
+
+func (u *useCase) Create(ctx context.Context, userID string, req *CreateIncomeRequest) (*IncomeResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan akun (jika diisi) milik user, kosong = akun default
+	accountID, err := account.Resolve(ctx, u.accountRepo, userID, req.AccountID)
+	if err != nil {
+		if errors.Is(err, account.ErrAccountNotFound) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to resolve account")
+		return nil, ErrInternalServer
+	}
+
+	// 3. Construct Entity
+	newIncome := &Income{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		AccountID:   accountID,
+		Source:      req.Source,
+		Description: req.Description,
+		Amount:      req.Amount,
+		Date:        *req.Date,
+		CreatedAt:   time.Now(),
+	}
+
+	// 4. Simpan ke DB
+	if err := u.repo.Save(ctx, newIncome); err != nil {
+		u.log.WithError(err).Error("Failed to save income")
+		return nil, ErrInternalServer
+	}
+
+	// 5. Budget persentase bulan itu ikut berubah
+	u.syncBudgets(ctx, userID, newIncome.Date)
+
+	return toResponse(newIncome), nil
+}
+
+func (u *useCase) List(ctx context.Context, userID string, req *ListIncomeRequest) ([]IncomeResponse, error) {
+	if req.DateFrom != nil && req.DateTo != nil && req.DateFrom.After(*req.DateTo) {
+		return nil, ErrInvalidDate
+	}
+
+	incomes, err := u.repo.FindAllByUserID(ctx, userID, req.DateFrom, req.DateTo)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list incomes")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]IncomeResponse, 0, len(incomes))
+	for i := range incomes {
+		resp = append(resp, *toResponse(&incomes[i]))
+	}
+	return resp, nil
+}
+
+func (u *useCase) Get(ctx context.Context, userID string, incomeID string) (*IncomeResponse, error) {
+	income, err := u.findOwned(ctx, userID, incomeID)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(income), nil
+}
+
+func (u *useCase) Update(ctx context.Context, userID string, incomeID string, req *UpdateIncomeRequest) (*IncomeResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan pemasukan (dan akun baru) milik user
+	income, err := u.findOwned(ctx, userID, incomeID)
+	if err != nil {
+		return nil, err
+	}
+	if req.AccountID != nil {
+		if income.AccountID, err = account.Resolve(ctx, u.accountRepo, userID, req.AccountID); err != nil {
+			if errors.Is(err, account.ErrAccountNotFound) {
+				return nil, err
+			}
+			u.log.WithError(err).Error("Failed to resolve account")
+			return nil, ErrInternalServer
+		}
+	}
+	previousDate := income.Date
+
+	// 3. Terapkan perubahan parsial
+	if req.Source != nil {
+		income.Source = *req.Source
+	}
+	if req.Description != nil {
+		income.Description = req.Description
+	}
+	if req.Amount != nil {
+		income.Amount = *req.Amount
+	}
+	if req.Date != nil {
+		income.Date = *req.Date
+	}
+
+	// 4. Simpan perubahan
+	if err := u.repo.Update(ctx, income); err != nil {
+		if errors.Is(err, ErrIncomeNotFound) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to update income")
+		return nil, ErrInternalServer
+	}
+
+	// 5. Jika tanggal pindah bulan, kedua bulan dihitung ulang
+	u.syncBudgets(ctx, userID, previousDate, income.Date)
+
+	return toResponse(income), nil
+}
+
+func (u *useCase) Delete(ctx context.Context, userID string, incomeID string) error {
+	// Tanggal pemasukan dibutuhkan untuk menghitung ulang budget bulannya
+	income, err := u.findOwned(ctx, userID, incomeID)
+	if err != nil {
+		return err
+	}
+
+	if err := u.repo.Delete(ctx, income.ID, userID); err != nil {
+		if errors.Is(err, ErrIncomeNotFound) {
+			return err
+		}
+		u.log.WithError(err).Error("Failed to delete income")
+		return ErrInternalServer
+	}
+
+	u.syncBudgets(ctx, userID, income.Date)
+	return nil
+}
+
+func (u *useCase) MonthSummary(ctx context.Context, userID string, req *MonthSummaryRequest) (*MonthSummaryResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	monthStart, _ := time.Parse("2006-01", req.Month)
+
+	// 2. Pemasukan per sumber
+	bySource, err := u.repo.SumBySource(ctx, userID, monthStart, monthStart.AddDate(0, 1, 0))
+	if err != nil {
+		u.log.WithError(err).Error("Failed to sum income by source")
+		return nil, ErrInternalServer
+	}
+	resp := &MonthSummaryResponse{Month: req.Month, IncomeBySource: bySource}
+	for _, s := range bySource {
+		resp.Income += s.Total
+	}
+
+	// 3. Pengeluaran = histories budget bulan itu (0 jika belum ada budget)
+	monthly, err := u.budgetRepo.FindByMonth(ctx, userID, monthStart)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find budget by month")
+		return nil, ErrInternalServer
+	}
+	if monthly != nil {
+		totals, err := u.budgetRepo.SumByCategory(ctx, monthly.ID)
+		if err != nil {
+			u.log.WithError(err).Error("Failed to sum histories by category")
+			return nil, ErrInternalServer
+		}
+		for _, t := range totals {
+			resp.Expenses += t.Total
+		}
+		resp.BudgetID = &monthly.ID
+	}
+
+	// 4. Tabungan bersih & savings rate (persen dari pemasukan, 2 desimal)
+	resp.NetSavings = resp.Income - resp.Expenses
+	if resp.Income > 0 {
+		rate := math.Round(resp.NetSavings/resp.Income*10000) / 100
+		resp.SavingsRate = &rate
+	}
+	return resp, nil
+}
+
+// syncBudgets menghitung ulang budget persentase di bulan (UTC, sama dengan MonthSummary) setiap tanggal.
+// Gagal sinkron tidak menggagalkan request; budget tersinkron lagi saat pemasukan berikutnya berubah.
+func (u *useCase) syncBudgets(ctx context.Context, userID string, dates ...time.Time) {
+	synced := make(map[time.Time]bool, len(dates))
+	for _, date := range dates {
+		date = date.UTC()
+		monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if synced[monthStart] {
+			continue
+		}
+		synced[monthStart] = true
+
+		if err := u.budgets.SyncIncome(ctx, userID, monthStart); err != nil {
+			u.log.WithError(err).WithField("month", monthStart.Format("2006-01")).Warn("Failed to sync income-based budget")
+		}
+	}
+}
+
+// findOwned mengambil pemasukan berdasarkan ID dan memastikan pemiliknya adalah userID
+func (u *useCase) findOwned(ctx context.Context, userID string, incomeID string) (*Income, error) {
+	// ID bukan UUID pasti tidak ada (hindari error cast dari Postgres)
+	if _, err := uuid.Parse(incomeID); err != nil {
+		return nil, ErrIncomeNotFound
+	}
+
+	income, err := u.repo.FindByID(ctx, incomeID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find income")
+		return nil, ErrInternalServer
+	}
+	if income == nil {
+		return nil, ErrIncomeNotFound
+	}
+	return income, nil
+}
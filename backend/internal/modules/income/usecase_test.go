@@ -0,0 +1,483 @@
+package income_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/account"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/income"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==========================================
+// 1. MOCK OBJECTS
+// ==========================================
+
+// MockRepository memalsukan behavior income.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, i *income.Income) error {
+	args := m.Called(ctx, i)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id string, userID string) (*income.Income, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*income.Income), args.Error(1)
+}
+
+func (m *MockRepository) FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]income.Income, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]income.Income), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, i *income.Income) error {
+	args := m.Called(ctx, i)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SumByMonth(ctx context.Context, userID string, monthStart time.Time) (float64, error) {
+	args := m.Called(ctx, userID, monthStart)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockRepository) SumBySource(ctx context.Context, userID string, from time.Time, to time.Time) ([]income.SourceTotal, error) {
+	args := m.Called(ctx, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]income.SourceTotal), args.Error(1)
+}
+
+// MockBudgetRepository memalsukan behavior budget.Repository
+type MockBudgetRepository struct {
+	mock.Mock
+}
+
+func (m *MockBudgetRepository) Save(ctx context.Context, b *budget.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) FindByID(ctx context.Context, id string, userID string) (*budget.Budget, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]budget.Budget, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Update(ctx context.Context, b *budget.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) SetIncomeBudget(ctx context.Context, id string, income float64) (bool, error) {
+	args := m.Called(ctx, id, income)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) SumByCategory(ctx context.Context, budgetID string) ([]budget.CategoryTotal, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.CategoryTotal), args.Error(1)
+}
+
+func (m *MockBudgetRepository) FindAllocations(ctx context.Context, budgetID string) ([]budget.Allocation, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.Allocation), args.Error(1)
+}
+
+func (m *MockBudgetRepository) SetAllocation(ctx context.Context, a *budget.Allocation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) DeleteAllocation(ctx context.Context, budgetID string, categoryID string) error {
+	args := m.Called(ctx, budgetID, categoryID)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) MoveAllocation(ctx context.Context, budgetID string, fromCategoryID string, toCategoryID string, amount float64, at time.Time) error {
+	args := m.Called(ctx, budgetID, fromCategoryID, toCategoryID, amount, at)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) FindByMonth(ctx context.Context, userID string, monthStart time.Time) (*budget.Budget, error) {
+	args := m.Called(ctx, userID, monthStart)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Close(ctx context.Context, b *budget.Budget, next *budget.Budget, createNext bool) error {
+	args := m.Called(ctx, b, next, createNext)
+	return args.Error(0)
+}
+
+// MockBudgetSyncer memalsukan behavior income.BudgetSyncer
+type MockBudgetSyncer struct {
+	mock.Mock
+}
+
+func (m *MockBudgetSyncer) SyncIncome(ctx context.Context, userID string, monthStart time.Time) error {
+	args := m.Called(ctx, userID, monthStart)
+	return args.Error(0)
+}
+
+// MockAccountRepository memalsukan behavior account.Repository
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Save(ctx context.Context, a *account.Account) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindByID(ctx context.Context, id string, userID string) (*account.Account, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) FindAllByUserID(ctx context.Context, userID string) ([]account.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) Update(ctx context.Context, a *account.Account) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) EnsureDefault(ctx context.Context, userID string) (*account.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) SaveTransfer(ctx context.Context, t *account.Transfer) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindTransfers(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]account.Transfer, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.Transfer), args.Error(1)
+}
+
+func (m *MockAccountRepository) DeleteTransfer(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) Ledger(ctx context.Context, accountID string, dateFrom, dateTo *time.Time) ([]account.LedgerEntry, error) {
+	args := m.Called(ctx, accountID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.LedgerEntry), args.Error(1)
+}
+
+// ==========================================
+// 2. HELPER SETUP
+// ==========================================
+
+const (
+	ownerID  = "11111111-1111-1111-1111-111111111111"
+	budgetID = "22222222-2222-2222-2222-222222222222"
+	incomeID = "33333333-3333-3333-3333-333333333333"
+
+	defaultAccountID = "55555555-5555-5555-5555-555555555555"
+	bankAccountID    = "66666666-6666-6666-6666-666666666666"
+)
+
+func setupTest() (income.UseCase, *MockRepository, *MockBudgetRepository, *MockBudgetSyncer) {
+	u, mockRepo, mockBudgetRepo, mockSyncer, _ := setupTestWithAccounts()
+	return u, mockRepo, mockBudgetRepo, mockSyncer
+}
+
+func setupTestWithAccounts() (income.UseCase, *MockRepository, *MockBudgetRepository, *MockBudgetSyncer, *MockAccountRepository) {
+	mockRepo := new(MockRepository)
+	mockBudgetRepo := new(MockBudgetRepository)
+	mockSyncer := new(MockBudgetSyncer)
+	mockAccountRepo := new(MockAccountRepository)
+
+	// Pemasukan tanpa account_id masuk ke akun default
+	mockAccountRepo.On("EnsureDefault", mock.Anything, ownerID).
+		Return(&account.Account{ID: defaultAccountID, UserID: ownerID, Name: account.DefaultAccountName, IsDefault: true}, nil).Maybe()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	u := income.NewUseCase(mockRepo, mockBudgetRepo, mockAccountRepo, mockSyncer, log, validator.New())
+	return u, mockRepo, mockBudgetRepo, mockSyncer, mockAccountRepo
+}
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func januarySalary() *income.Income {
+	return &income.Income{
+		ID:     incomeID,
+		UserID: ownerID,
+		Source: income.SourceSalary,
+		Amount: 8000000,
+		Date:   time.Date(2026, 1, 25, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// ==========================================
+// 3. GROUP: CREATE TESTS
+// ==========================================
+
+func TestCreate_SuccessSyncsBudget(t *testing.T) {
+	u, mockRepo, _, mockSyncer := setupTest()
+
+	date := time.Date(2026, 1, 25, 0, 0, 0, 0, time.UTC)
+	req := &income.CreateIncomeRequest{Source: income.SourceSalary, Amount: 8000000, Date: &date}
+
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(i *income.Income) bool {
+		return i.UserID == ownerID && i.Source == income.SourceSalary && i.AccountID == defaultAccountID && i.ID != ""
+	})).Return(nil)
+	mockSyncer.On("SyncIncome", mock.Anything, ownerID, month(2026, 1)).Return(nil)
+
+	resp, err := u.Create(context.Background(), ownerID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 8000000.0, resp.Amount)
+	mockRepo.AssertExpectations(t)
+	mockSyncer.AssertExpectations(t)
+}
+
+func TestCreate_SyncsUTCMonth(t *testing.T) {
+	u, mockRepo, _, mockSyncer := setupTest()
+
+	// 1 Februari 03:00 WIB masih 31 Januari di UTC
+	date := time.Date(2026, 2, 1, 3, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+	req := &income.CreateIncomeRequest{Source: income.SourceSalary, Amount: 8000000, Date: &date}
+
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	mockSyncer.On("SyncIncome", mock.Anything, ownerID, month(2026, 1)).Return(nil)
+
+	_, err := u.Create(context.Background(), ownerID, req)
+
+	assert.NoError(t, err)
+	mockSyncer.AssertExpectations(t)
+}
+
+func TestCreate_UnknownSource(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	date := time.Now()
+	resp, err := u.Create(context.Background(), ownerID, &income.CreateIncomeRequest{Source: "lottery", Amount: 10, Date: &date})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_SyncFailureDoesNotFail(t *testing.T) {
+	u, mockRepo, _, mockSyncer := setupTest()
+
+	date := time.Now()
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	mockSyncer.On("SyncIncome", mock.Anything, ownerID, mock.Anything).Return(errors.New("db down"))
+
+	resp, err := u.Create(context.Background(), ownerID, &income.CreateIncomeRequest{Source: income.SourceBonus, Amount: 10, Date: &date})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+func TestCreate_AccountNotOwned(t *testing.T) {
+	u, mockRepo, _, mockSyncer, mockAccountRepo := setupTestWithAccounts()
+
+	date := time.Now()
+	id := bankAccountID
+	mockAccountRepo.On("FindByID", mock.Anything, bankAccountID, ownerID).Return(nil, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, &income.CreateIncomeRequest{Source: income.SourceSalary, Amount: 10, Date: &date, AccountID: &id})
+
+	assert.Equal(t, account.ErrAccountNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+	mockSyncer.AssertNotCalled(t, "SyncIncome")
+}
+
+// ==========================================
+// 4. GROUP: UPDATE & DELETE TESTS
+// ==========================================
+
+func TestUpdate_MovingMonthSyncsBothMonths(t *testing.T) {
+	u, mockRepo, _, mockSyncer := setupTest()
+
+	february := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	mockRepo.On("FindByID", mock.Anything, incomeID, ownerID).Return(januarySalary(), nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(i *income.Income) bool {
+		return i.Date.Equal(february)
+	})).Return(nil)
+	mockSyncer.On("SyncIncome", mock.Anything, ownerID, month(2026, 1)).Return(nil).Once()
+	mockSyncer.On("SyncIncome", mock.Anything, ownerID, month(2026, 2)).Return(nil).Once()
+
+	_, err := u.Update(context.Background(), ownerID, incomeID, &income.UpdateIncomeRequest{Date: &february})
+
+	assert.NoError(t, err)
+	mockSyncer.AssertExpectations(t)
+}
+
+func TestUpdate_SameMonthSyncsOnce(t *testing.T) {
+	u, mockRepo, _, mockSyncer := setupTest()
+
+	amount := 9000000.0
+	mockRepo.On("FindByID", mock.Anything, incomeID, ownerID).Return(januarySalary(), nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	mockSyncer.On("SyncIncome", mock.Anything, ownerID, month(2026, 1)).Return(nil)
+
+	resp, err := u.Update(context.Background(), ownerID, incomeID, &income.UpdateIncomeRequest{Amount: &amount})
+
+	assert.NoError(t, err)
+	assert.Equal(t, amount, resp.Amount)
+	mockSyncer.AssertNumberOfCalls(t, "SyncIncome", 1)
+}
+
+func TestGet_InvalidID(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	resp, err := u.Get(context.Background(), ownerID, "not-a-uuid")
+
+	assert.Equal(t, income.ErrIncomeNotFound, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "FindByID")
+}
+
+func TestDelete_NotOwned(t *testing.T) {
+	u, mockRepo, _, mockSyncer := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, incomeID, "intruder").Return(nil, nil)
+
+	err := u.Delete(context.Background(), "intruder", incomeID)
+
+	assert.Equal(t, income.ErrIncomeNotFound, err)
+	mockRepo.AssertNotCalled(t, "Delete")
+	mockSyncer.AssertNotCalled(t, "SyncIncome")
+}
+
+func TestDelete_SyncsBudget(t *testing.T) {
+	u, mockRepo, _, mockSyncer := setupTest()
+
+	mockRepo.On("FindByID", mock.Anything, incomeID, ownerID).Return(januarySalary(), nil)
+	mockRepo.On("Delete", mock.Anything, incomeID, ownerID).Return(nil)
+	mockSyncer.On("SyncIncome", mock.Anything, ownerID, month(2026, 1)).Return(nil)
+
+	err := u.Delete(context.Background(), ownerID, incomeID)
+
+	assert.NoError(t, err)
+	mockSyncer.AssertExpectations(t)
+}
+
+// ==========================================
+// 5. GROUP: MONTH SUMMARY TESTS
+// ==========================================
+
+func TestMonthSummary_IncomeExpensesAndSavingsRate(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _ := setupTest()
+
+	mockRepo.On("SumBySource", mock.Anything, ownerID, month(2026, 1), month(2026, 2)).Return([]income.SourceTotal{
+		{Source: income.SourceSalary, Total: 8000000},
+		{Source: income.SourceFreelance, Total: 1000000},
+	}, nil)
+	mockBudgetRepo.On("FindByMonth", mock.Anything, ownerID, month(2026, 1)).Return(&budget.Budget{ID: budgetID, UserID: ownerID}, nil)
+	mockBudgetRepo.On("SumByCategory", mock.Anything, budgetID).Return([]budget.CategoryTotal{{Total: 5000000}, {Total: 1000000}}, nil)
+
+	resp, err := u.MonthSummary(context.Background(), ownerID, &income.MonthSummaryRequest{Month: "2026-01"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 9000000.0, resp.Income)
+	assert.Equal(t, 6000000.0, resp.Expenses)
+	assert.Equal(t, 3000000.0, resp.NetSavings)
+	assert.Equal(t, 33.33, *resp.SavingsRate)
+	assert.Equal(t, budgetID, *resp.BudgetID)
+	assert.Len(t, resp.IncomeBySource, 2)
+}
+
+func TestMonthSummary_NoIncomeNoBudget(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _ := setupTest()
+
+	mockRepo.On("SumBySource", mock.Anything, ownerID, mock.Anything, mock.Anything).Return([]income.SourceTotal{}, nil)
+	mockBudgetRepo.On("FindByMonth", mock.Anything, ownerID, month(2026, 3)).Return(nil, nil)
+
+	resp, err := u.MonthSummary(context.Background(), ownerID, &income.MonthSummaryRequest{Month: "2026-03"})
+
+	assert.NoError(t, err)
+	assert.Zero(t, resp.Expenses)
+	assert.Nil(t, resp.SavingsRate)
+	assert.Nil(t, resp.BudgetID)
+	mockBudgetRepo.AssertNotCalled(t, "SumByCategory")
+}
+
+func TestMonthSummary_InvalidMonth(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	resp, err := u.MonthSummary(context.Background(), ownerID, &income.MonthSummaryRequest{Month: "2026-13"})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "SumBySource")
+}
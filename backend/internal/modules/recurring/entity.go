@@ -0,0 +1,89 @@
+package recurring
+
+import "time"
+
+// Recurring: Template transaksi berulang (sewa, langganan, cicilan)
+type Recurring struct {
+	ID         string
+	UserID     string
+	CategoryID *string
+	// AccountID: akun sumber dana; nil = akun default saat history dibuat
+	AccountID *string
+	Name      string
+	Amount    float64
+	// Rule: RRULE dalam bentuk kanonik (Rule.String)
+	Rule      string
+	StartDate time.Time
+	EndDate   *time.Time
+	// NextRunAt: kejadian berikutnya yang belum dibuat menjadi history (nil = jadwal sudah selesai)
+	NextRunAt *time.Time
+	Active    bool
+	CreatedAt time.Time
+}
+
+// RecurringResponse: Format standar data recurring template untuk output JSON
+type RecurringResponse struct {
+	ID         string     `json:"id"`
+	CategoryID *string    `json:"category_id"`
+	AccountID  *string    `json:"account_id"`
+	Name       string     `json:"name"`
+	Amount     float64    `json:"amount"`
+	RRule      string     `json:"rrule"`
+	StartDate  time.Time  `json:"start_date"`
+	EndDate    *time.Time `json:"end_date"`
+	NextRunAt  *time.Time `json:"next_run_at"`
+	Active     bool       `json:"active"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// OccurrenceResponse: Satu jadwal transaksi yang akan datang
+type OccurrenceResponse struct {
+	RecurringID string    `json:"recurring_id"`
+	Name        string    `json:"name"`
+	Amount      float64   `json:"amount"`
+	CategoryID  *string   `json:"category_id"`
+	Date        time.Time `json:"date"`
+}
+
+// CreateRecurringRequest: RRule memakai subset RRULE (lihat Rule), end_date inklusif & opsional
+type CreateRecurringRequest struct {
+	Name       string     `json:"name" validate:"required,max=100"`
+	Amount     float64    `json:"amount" validate:"required,gt=0"`
+	CategoryID *string    `json:"category_id" validate:"omitempty,uuid"`
+	AccountID  *string    `json:"account_id" validate:"omitempty,uuid"`
+	RRule      string     `json:"rrule" validate:"required,max=200"`
+	StartDate  *time.Time `json:"start_date" validate:"required"`
+	EndDate    *time.Time `json:"end_date"`
+}
+
+// UpdateRecurringRequest: Semua field opsional (PATCH). Mengubah rrule / end_date / active menjadwalkan ulang mulai hari ini.
+type UpdateRecurringRequest struct {
+	Name       *string    `json:"name" validate:"omitempty,min=1,max=100"`
+	Amount     *float64   `json:"amount" validate:"omitempty,gt=0"`
+	CategoryID *string    `json:"category_id" validate:"omitempty,uuid"`
+	AccountID  *string    `json:"account_id" validate:"omitempty,uuid"`
+	RRule      *string    `json:"rrule" validate:"omitempty,max=200"`
+	EndDate    *time.Time `json:"end_date"`
+	Active     *bool      `json:"active"`
+}
+
+// UpcomingRequest: Jumlah hari ke depan (default 30)
+type UpcomingRequest struct {
+	Days int `validate:"min=1,max=366"`
+}
+
+func toResponse(r *Recurring) *RecurringResponse {
+	return &RecurringResponse{
+		ID:         r.ID,
+		CategoryID: r.CategoryID,
+		AccountID:  r.AccountID,
+		Name:       r.Name,
+		Amount:     r.Amount,
+		RRule:      r.Rule,
+		StartDate:  r.StartDate,
+		EndDate:    r.EndDate,
+		NextRunAt:  r.NextRunAt,
+		Active:     r.Active,
+		CreatedAt:  r.CreatedAt,
+	}
+}
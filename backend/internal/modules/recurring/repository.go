@@ -0,0 +1,170 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/history"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRecurringNotFound = apperror.NotFound("recurring_not_found", "recurring transaction not found")
+)
+
+// Repository: Query milik user WAJIB di-scope ke user_id; FindDue & Materialise dipakai scheduler
+type Repository interface {
+	Save(ctx context.Context, recurring *Recurring) error
+	FindByID(ctx context.Context, id string, userID string) (*Recurring, error)
+	FindAllByUserID(ctx context.Context, userID string) ([]Recurring, error)
+	Update(ctx context.Context, recurring *Recurring) error
+	Delete(ctx context.Context, id string, userID string) error
+
+	// FindDue: template aktif yang next_run_at-nya sudah lewat dan budget bulan kejadiannya sudah ada, paling lama dulu
+	FindDue(ctx context.Context, now time.Time, limit int) ([]Recurring, error)
+	// Materialise menyimpan entry (history untuk kejadian recurring.NextRunAt) lalu memajukan next_run_at ke next.
+	// entry nil berarti kejadian dilewati. Satu kejadian tidak pernah dibuat dua kali (unique index histories(recurring_id, date))
+	// dan tidak pernah masuk ke budget yang sudah ditutup (history.Insert).
+	Materialise(ctx context.Context, recurring *Recurring, entry *history.History, next *time.Time) error
+}
+
+type repository struct {
+	db *pgxpool.Pool
+}
+
+func NewRepository(db *pgxpool.Pool) Repository {
+	return &repository{db: db}
+}
+
+// recurringColumns: Urutan kolom harus sama dengan urutan scanRecurring
+const recurringColumns = `id, user_id, category_id, account_id, name, amount, rrule, start_date, end_date, next_run_at, active, created_at`
+
+func scanRecurring(row pgx.Row, r *Recurring) error {
+	return row.Scan(&r.ID, &r.UserID, &r.CategoryID, &r.AccountID, &r.Name, &r.Amount, &r.Rule, &r.StartDate, &r.EndDate, &r.NextRunAt, &r.Active, &r.CreatedAt)
+}
+
+func (r *repository) Save(ctx context.Context, recurring *Recurring) error {
+	query := `
+		INSERT INTO recurring_transactions (` + recurringColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err := r.db.Exec(ctx, query,
+		recurring.ID, recurring.UserID, recurring.CategoryID, recurring.AccountID, recurring.Name, recurring.Amount, recurring.Rule,
+		recurring.StartDate, recurring.EndDate, recurring.NextRunAt, recurring.Active, recurring.CreatedAt,
+	)
+	return err
+}
+
+func (r *repository) FindByID(ctx context.Context, id string, userID string) (*Recurring, error) {
+	query := `SELECT ` + recurringColumns + ` FROM recurring_transactions WHERE id = $1 AND user_id = $2`
+
+	var recurring Recurring
+	if err := scanRecurring(r.db.QueryRow(ctx, query, id, userID), &recurring); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &recurring, nil
+}
+
+func (r *repository) FindAllByUserID(ctx context.Context, userID string) ([]Recurring, error) {
+	query := `SELECT ` + recurringColumns + ` FROM recurring_transactions WHERE user_id = $1 ORDER BY next_run_at NULLS LAST, lower(name)`
+	return r.query(ctx, query, userID)
+}
+
+func (r *repository) Update(ctx context.Context, recurring *Recurring) error {
+	query := `
+		UPDATE recurring_transactions
+		SET category_id = $1, account_id = $2, name = $3, amount = $4, rrule = $5, end_date = $6, next_run_at = $7, active = $8
+		WHERE id = $9 AND user_id = $10
+	`
+	tag, err := r.db.Exec(ctx, query,
+		recurring.CategoryID, recurring.AccountID, recurring.Name, recurring.Amount, recurring.Rule, recurring.EndDate, recurring.NextRunAt, recurring.Active,
+		recurring.ID, recurring.UserID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRecurringNotFound
+	}
+	return nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string, userID string) error {
+	// History yang sudah dibuat tetap ada (recurring_id ON DELETE SET NULL)
+	tag, err := r.db.Exec(ctx, `DELETE FROM recurring_transactions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRecurringNotFound
+	}
+	return nil
+}
+
+func (r *repository) FindDue(ctx context.Context, now time.Time, limit int) ([]Recurring, error) {
+	// Template yang ditunda karena budget bulannya belum dibuat tidak ikut diambil,
+	// kalau tidak mereka memenuhi batch dan template user lain tidak pernah diproses
+	query := `
+		SELECT ` + recurringColumns + ` FROM recurring_transactions r
+		WHERE r.active AND r.next_run_at <= $1
+		  AND EXISTS (
+			SELECT 1 FROM monthly_budgets b
+			WHERE b.user_id = r.user_id
+			  AND b.date >= date_trunc('month', r.next_run_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC'
+			  AND b.date < (date_trunc('month', r.next_run_at AT TIME ZONE 'UTC') + INTERVAL '1 month') AT TIME ZONE 'UTC'
+		  )
+		ORDER BY r.next_run_at
+		LIMIT $2
+	`
+	return r.query(ctx, query, now, limit)
+}
+
+func (r *repository) Materialise(ctx context.Context, recurring *Recurring, entry *history.History, next *time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if entry != nil {
+		if _, err := history.Insert(ctx, tx, entry); err != nil {
+			return err
+		}
+	}
+
+	// next_run_at lama sebagai guard: template yang diubah / dihapus di tengah jalan tidak ikut dimajukan
+	advance := `UPDATE recurring_transactions SET next_run_at = $1 WHERE id = $2 AND next_run_at = $3`
+	tag, err := tx.Exec(ctx, advance, next, recurring.ID, recurring.NextRunAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRecurringNotFound
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *repository) query(ctx context.Context, query string, args ...any) ([]Recurring, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recurrings := make([]Recurring, 0)
+	for rows.Next() {
+		var recurring Recurring
+		if err := scanRecurring(rows, &recurring); err != nil {
+			return nil, err
+		}
+		recurrings = append(recurrings, recurring)
+	}
+	return recurrings, rows.Err()
+}
@@ -0,0 +1,254 @@
+package recurring
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+)
+
+type Frequency string
+
+const (
+	FreqWeekly  Frequency = "WEEKLY"
+	FreqMonthly Frequency = "MONTHLY"
+	FreqYearly  Frequency = "YEARLY"
+)
+
+// LastDay: BYMONTHDAY=-1, hari terakhir bulan
+const LastDay = -1
+
+// maxOccurrences membatasi Between supaya rentang besar tidak menghasilkan list tanpa batas
+const maxOccurrences = 500
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// Rule: Subset RRULE (RFC 5545) yang didukung.
+//
+//	FREQ=WEEKLY;BYDAY=MO                           mingguan pada hari tertentu
+//	FREQ=MONTHLY;BYMONTHDAY=25                     bulanan pada tanggal N (-1 = hari terakhir)
+//	FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1  hari kerja terakhir setiap bulan
+//	FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=15            tahunan
+//
+// INTERVAL=N (default 1) berlaku untuk semua FREQ. Tanggal yang tidak ada di bulan pendek
+// (mis. 31 di bulan Februari) jatuh ke hari terakhir bulan tersebut.
+type Rule struct {
+	Freq            Frequency
+	Interval        int
+	Weekday         time.Weekday
+	MonthDay        int
+	Month           time.Month
+	LastBusinessDay bool
+}
+
+func invalidRule(msg string) error {
+	return apperror.BadRequest("invalid_rrule", msg)
+}
+
+// ParseRule mem-parsing string RRULE (prefix "RRULE:" opsional); kombinasi di luar subset ditolak
+func ParseRule(value string) (*Rule, error) {
+	parts := make(map[string]string)
+	for _, part := range strings.Split(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(value)), "RRULE:"), ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, invalidRule("malformed rrule part " + strconv.Quote(part))
+		}
+		if _, dup := parts[key]; dup {
+			return nil, invalidRule("duplicate rrule part " + key)
+		}
+		parts[key] = val
+	}
+
+	rule := &Rule{Freq: Frequency(parts["FREQ"]), Interval: 1}
+	delete(parts, "FREQ")
+
+	if v, ok := parts["INTERVAL"]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 99 {
+			return nil, invalidRule("INTERVAL must be between 1 and 99")
+		}
+		rule.Interval = n
+		delete(parts, "INTERVAL")
+	}
+
+	var err error
+	switch rule.Freq {
+	case FreqWeekly:
+		err = rule.parseWeekly(parts)
+	case FreqMonthly:
+		err = rule.parseMonthly(parts)
+	case FreqYearly:
+		err = rule.parseYearly(parts)
+	default:
+		return nil, invalidRule("FREQ must be WEEKLY, MONTHLY or YEARLY")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range parts {
+		return nil, invalidRule(key + " is not supported for FREQ=" + string(rule.Freq))
+	}
+	return rule, nil
+}
+
+func (r *Rule) parseWeekly(parts map[string]string) error {
+	day, ok := weekdayCodes[parts["BYDAY"]]
+	if !ok {
+		return invalidRule("FREQ=WEEKLY requires a single BYDAY (MO..SU)")
+	}
+	r.Weekday = day
+	delete(parts, "BYDAY")
+	return nil
+}
+
+func (r *Rule) parseMonthly(parts map[string]string) error {
+	// Hari kerja terakhir: BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1
+	if byDay, ok := parts["BYDAY"]; ok {
+		days := strings.Split(byDay, ",")
+		sort.Strings(days)
+		if strings.Join(days, ",") != "FR,MO,TH,TU,WE" || parts["BYSETPOS"] != "-1" {
+			return invalidRule("FREQ=MONTHLY with BYDAY only supports the last business day (BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1)")
+		}
+		r.LastBusinessDay = true
+		delete(parts, "BYDAY")
+		delete(parts, "BYSETPOS")
+		return nil
+	}
+
+	day, err := parseMonthDay(parts)
+	if err != nil {
+		return err
+	}
+	r.MonthDay = day
+	return nil
+}
+
+func (r *Rule) parseYearly(parts map[string]string) error {
+	month, err := strconv.Atoi(parts["BYMONTH"])
+	if err != nil || month < 1 || month > 12 {
+		return invalidRule("FREQ=YEARLY requires BYMONTH between 1 and 12")
+	}
+	r.Month = time.Month(month)
+	delete(parts, "BYMONTH")
+
+	day, err := parseMonthDay(parts)
+	if err != nil {
+		return err
+	}
+	r.MonthDay = day
+	return nil
+}
+
+func parseMonthDay(parts map[string]string) (int, error) {
+	day, err := strconv.Atoi(parts["BYMONTHDAY"])
+	if err != nil || day == 0 || day < LastDay || day > 31 {
+		return 0, invalidRule("BYMONTHDAY must be between 1 and 31, or -1 for the last day")
+	}
+	delete(parts, "BYMONTHDAY")
+	return day, nil
+}
+
+// String mengembalikan bentuk kanonik (yang disimpan di database)
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	switch {
+	case r.Freq == FreqWeekly:
+		for code, day := range weekdayCodes {
+			if day == r.Weekday {
+				parts = append(parts, "BYDAY="+code)
+			}
+		}
+	case r.LastBusinessDay:
+		parts = append(parts, "BYDAY=MO,TU,WE,TH,FR", "BYSETPOS=-1")
+	default:
+		if r.Freq == FreqYearly {
+			parts = append(parts, "BYMONTH="+strconv.Itoa(int(r.Month)))
+		}
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.MonthDay))
+	}
+	return strings.Join(parts, ";")
+}
+
+// NextOnOrAfter: kejadian pertama yang jatuh pada / setelah t (dan tidak sebelum start).
+// Semua tanggal dinormalisasi ke tengah malam UTC.
+func (r *Rule) NextOnOrAfter(start time.Time, t time.Time) time.Time {
+	start, t = truncateDay(start), truncateDay(t)
+	if t.Before(start) {
+		t = start
+	}
+
+	switch r.Freq {
+	case FreqWeekly:
+		first := start.AddDate(0, 0, (int(r.Weekday)-int(start.Weekday())+7)%7)
+		if !t.After(first) {
+			return first
+		}
+		step := 7 * r.Interval
+		days := int(t.Sub(first).Hours() / 24)
+		return first.AddDate(0, 0, (days+step-1)/step*step)
+
+	case FreqYearly:
+		// Lompat mendekati t dulu, lalu maju per periode
+		k := max((t.Year()-start.Year())/r.Interval-1, 0)
+		for ; ; k++ {
+			if occ := r.dayInMonth(start.Year()+k*r.Interval, r.Month); !occ.Before(t) {
+				return occ
+			}
+		}
+
+	default:
+		months := (t.Year()-start.Year())*12 + int(t.Month()-start.Month())
+		k := max(months/r.Interval-1, 0)
+		for ; ; k++ {
+			month := time.Date(start.Year(), start.Month()+time.Month(k*r.Interval), 1, 0, 0, 0, 0, time.UTC)
+			if occ := r.dayInMonth(month.Year(), month.Month()); !occ.Before(t) {
+				return occ
+			}
+		}
+	}
+}
+
+// Between: semua kejadian di [from, to] (inklusif), tidak sebelum start dan tidak setelah end (jika ada)
+func (r *Rule) Between(start time.Time, end *time.Time, from time.Time, to time.Time) []time.Time {
+	occurrences := make([]time.Time, 0)
+	for next := r.NextOnOrAfter(start, from); !next.After(to) && len(occurrences) < maxOccurrences; next = r.NextOnOrAfter(start, next.AddDate(0, 0, 1)) {
+		if end != nil && next.After(truncateDay(*end)) {
+			break
+		}
+		occurrences = append(occurrences, next)
+	}
+	return occurrences
+}
+
+func (r *Rule) dayInMonth(year int, month time.Month) time.Time {
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+
+	if r.LastBusinessDay {
+		for lastDay.Weekday() == time.Saturday || lastDay.Weekday() == time.Sunday {
+			lastDay = lastDay.AddDate(0, 0, -1)
+		}
+		return lastDay
+	}
+	if r.MonthDay == LastDay || r.MonthDay > lastDay.Day() {
+		return lastDay
+	}
+	return time.Date(year, month, r.MonthDay, 0, 0, 0, 0, time.UTC)
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
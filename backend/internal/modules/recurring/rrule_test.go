@@ -0,0 +1,125 @@
+package recurring_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/recurring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// ==========================================
+// 1. GROUP: PARSE TESTS
+// ==========================================
+
+func TestParseRule_SupportedRulesAreCanonicalised(t *testing.T) {
+	cases := map[string]string{
+		"FREQ=MONTHLY;BYMONTHDAY=25":                      "FREQ=MONTHLY;BYMONTHDAY=25",
+		"rrule:freq=weekly;byday=mo":                      "FREQ=WEEKLY;BYDAY=MO",
+		"FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=15":             "FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=15",
+		"FREQ=MONTHLY;BYSETPOS=-1;BYDAY=FR,TH,WE,TU,MO":   "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=-1":           "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=-1",
+		"FREQ=WEEKLY;INTERVAL=1;BYDAY=SU":                 "FREQ=WEEKLY;BYDAY=SU",
+		"  FREQ=MONTHLY;BYMONTHDAY=1;  ":                  "FREQ=MONTHLY;BYMONTHDAY=1",
+		"FREQ=YEARLY;INTERVAL=2;BYMONTH=12;BYMONTHDAY=-1": "FREQ=YEARLY;INTERVAL=2;BYMONTH=12;BYMONTHDAY=-1",
+		"FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;":  "FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+		"FREQ=WEEKLY;BYDAY=FR;INTERVAL=2":                 "FREQ=WEEKLY;INTERVAL=2;BYDAY=FR",
+		"FREQ=MONTHLY;BYMONTHDAY=31":                      "FREQ=MONTHLY;BYMONTHDAY=31",
+		"FREQ=YEARLY;BYMONTHDAY=29;BYMONTH=2":             "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29",
+	}
+
+	for input, want := range cases {
+		rule, err := recurring.ParseRule(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, rule.String(), input)
+	}
+}
+
+func TestParseRule_RejectsUnsupportedRules(t *testing.T) {
+	cases := []string{
+		"",
+		"FREQ=DAILY",
+		"FREQ=MONTHLY",                         // tanpa BYMONTHDAY
+		"FREQ=MONTHLY;BYMONTHDAY=32",           // di luar rentang
+		"FREQ=MONTHLY;BYMONTHDAY=0",            // tidak valid
+		"FREQ=WEEKLY;BYDAY=MO,WE",              // lebih dari satu hari
+		"FREQ=MONTHLY;BYDAY=MO;BYSETPOS=-1",    // hanya hari kerja terakhir yang didukung
+		"FREQ=YEARLY;BYMONTHDAY=1",             // tanpa BYMONTH
+		"FREQ=MONTHLY;BYMONTHDAY=1;COUNT=12",   // COUNT tidak didukung
+		"FREQ=MONTHLY;INTERVAL=0;BYMONTHDAY=1", // interval tidak valid
+		"FREQ=MONTHLY;BYMONTHDAY",              // bagian rusak
+	}
+
+	for _, input := range cases {
+		_, err := recurring.ParseRule(input)
+		appErr := apperror.From(err)
+		require.NotNil(t, appErr, input)
+		assert.Equal(t, "invalid_rrule", appErr.Code, input)
+	}
+}
+
+// ==========================================
+// 2. GROUP: OCCURRENCE TESTS
+// ==========================================
+
+func TestNextOnOrAfter_MonthlyClampsToShortMonths(t *testing.T) {
+	rule, _ := recurring.ParseRule("FREQ=MONTHLY;BYMONTHDAY=31")
+	start := date(2026, 1, 1)
+
+	assert.Equal(t, date(2026, 1, 31), rule.NextOnOrAfter(start, date(2026, 1, 10)))
+	assert.Equal(t, date(2026, 2, 28), rule.NextOnOrAfter(start, date(2026, 2, 1)))
+	assert.Equal(t, date(2026, 3, 31), rule.NextOnOrAfter(start, date(2026, 3, 1)))
+}
+
+func TestNextOnOrAfter_NeverBeforeStart(t *testing.T) {
+	rule, _ := recurring.ParseRule("FREQ=MONTHLY;BYMONTHDAY=5")
+
+	// Mulai tanggal 20: kejadian pertama 5 bulan berikutnya
+	assert.Equal(t, date(2026, 2, 5), rule.NextOnOrAfter(date(2026, 1, 20), date(2025, 12, 1)))
+}
+
+func TestNextOnOrAfter_IntervalCountsFromStart(t *testing.T) {
+	quarterly, _ := recurring.ParseRule("FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=1")
+	biweekly, _ := recurring.ParseRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=FR")
+
+	assert.Equal(t, date(2026, 4, 1), quarterly.NextOnOrAfter(date(2026, 1, 1), date(2026, 1, 2)))
+	assert.Equal(t, date(2026, 7, 1), quarterly.NextOnOrAfter(date(2026, 1, 1), date(2026, 5, 15)))
+
+	// 2 Jan 2026 = Jumat
+	assert.Equal(t, date(2026, 1, 2), biweekly.NextOnOrAfter(date(2026, 1, 1), date(2026, 1, 1)))
+	assert.Equal(t, date(2026, 1, 16), biweekly.NextOnOrAfter(date(2026, 1, 1), date(2026, 1, 3)))
+	assert.Equal(t, date(2026, 1, 16), biweekly.NextOnOrAfter(date(2026, 1, 1), date(2026, 1, 16)))
+}
+
+func TestNextOnOrAfter_LastBusinessDay(t *testing.T) {
+	rule, _ := recurring.ParseRule("FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1")
+	start := date(2026, 1, 1)
+
+	// 31 Jan 2026 = Sabtu, 28 Feb 2026 = Sabtu, 31 Mar 2026 = Selasa
+	assert.Equal(t, date(2026, 1, 30), rule.NextOnOrAfter(start, start))
+	assert.Equal(t, date(2026, 2, 27), rule.NextOnOrAfter(start, date(2026, 1, 31)))
+	assert.Equal(t, date(2026, 3, 31), rule.NextOnOrAfter(start, date(2026, 3, 1)))
+}
+
+func TestNextOnOrAfter_YearlyLeapDay(t *testing.T) {
+	rule, _ := recurring.ParseRule("FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=29")
+	start := date(2026, 1, 1)
+
+	assert.Equal(t, date(2026, 2, 28), rule.NextOnOrAfter(start, start))
+	assert.Equal(t, date(2028, 2, 29), rule.NextOnOrAfter(start, date(2027, 3, 1)))
+}
+
+func TestBetween_StopsAtEndDate(t *testing.T) {
+	rule, _ := recurring.ParseRule("FREQ=WEEKLY;BYDAY=MO")
+	end := date(2026, 1, 19)
+
+	got := rule.Between(date(2026, 1, 1), &end, date(2026, 1, 1), date(2026, 12, 31))
+
+	assert.Equal(t, []time.Time{date(2026, 1, 5), date(2026, 1, 12), date(2026, 1, 19)}, got)
+}
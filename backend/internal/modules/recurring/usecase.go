@@ -0,0 +1,394 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/account"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/category"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/history"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrInternalServer = apperror.ErrInternal
+	ErrInvalidDate    = apperror.BadRequest("invalid_date_range", "end_date must not be before start_date")
+)
+
+// dueBatchSize: jumlah template maksimal yang diproses per putaran scheduler
+const dueBatchSize = 100
+
+type UseCase interface {
+	Create(ctx context.Context, userID string, req *CreateRecurringRequest) (*RecurringResponse, error)
+	List(ctx context.Context, userID string) ([]RecurringResponse, error)
+	Get(ctx context.Context, userID string, recurringID string) (*RecurringResponse, error)
+	Update(ctx context.Context, userID string, recurringID string, req *UpdateRecurringRequest) (*RecurringResponse, error)
+	Delete(ctx context.Context, userID string, recurringID string) error
+	Upcoming(ctx context.Context, userID string, req *UpcomingRequest) ([]OccurrenceResponse, error)
+
+	// MaterialiseDue membuat history untuk semua kejadian yang sudah jatuh tempo.
+	// Dipanggil berkala oleh background worker.
+	MaterialiseDue(ctx context.Context) error
+}
+
+type useCase struct {
+	repo         Repository
+	budgetRepo   budget.Repository
+	categoryRepo category.Repository
+	accountRepo  account.Repository
+	log          *logrus.Logger
+	validate     *validator.Validate
+}
+
+func NewUseCase(repo Repository, budgetRepo budget.Repository, categoryRepo category.Repository, accountRepo account.Repository, log *logrus.Logger, validate *validator.Validate) UseCase {
+	return &useCase{
+		repo:         repo,
+		budgetRepo:   budgetRepo,
+		categoryRepo: categoryRepo,
+		accountRepo:  accountRepo,
+		log:          log,
+		validate:     validate,
+	}
+}
+
+func (u *useCase) Create(ctx context.Context, userID string, req *CreateRecurringRequest) (*RecurringResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	rule, err := ParseRule(req.RRule)
+	if err != nil {
+		return nil, err
+	}
+	if req.EndDate != nil && req.EndDate.Before(*req.StartDate) {
+		return nil, ErrInvalidDate
+	}
+
+	// 2. Pastikan kategori & akun (jika diisi) milik user
+	if err := u.checkCategory(ctx, userID, req.CategoryID); err != nil {
+		return nil, err
+	}
+	if req.AccountID != nil {
+		if _, err := account.Resolve(ctx, u.accountRepo, userID, req.AccountID); err != nil {
+			if errors.Is(err, account.ErrAccountNotFound) {
+				return nil, err
+			}
+			u.log.WithError(err).Error("Failed to resolve account")
+			return nil, ErrInternalServer
+		}
+	}
+
+	// 3. Construct Entity (kejadian sebelum hari ini tidak dibuat mundur)
+	newRecurring := &Recurring{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		CategoryID: req.CategoryID,
+		AccountID:  req.AccountID,
+		Name:       strings.TrimSpace(req.Name),
+		Amount:     req.Amount,
+		Rule:       rule.String(),
+		StartDate:  truncateDay(*req.StartDate),
+		EndDate:    req.EndDate,
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	newRecurring.NextRunAt = u.nextRun(rule, newRecurring, time.Now())
+
+	// 4. Simpan ke DB
+	if err := u.repo.Save(ctx, newRecurring); err != nil {
+		u.log.WithError(err).Error("Failed to save recurring transaction")
+		return nil, ErrInternalServer
+	}
+
+	return toResponse(newRecurring), nil
+}
+
+func (u *useCase) List(ctx context.Context, userID string) ([]RecurringResponse, error) {
+	recurrings, err := u.repo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list recurring transactions")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]RecurringResponse, 0, len(recurrings))
+	for i := range recurrings {
+		resp = append(resp, *toResponse(&recurrings[i]))
+	}
+	return resp, nil
+}
+
+func (u *useCase) Get(ctx context.Context, userID string, recurringID string) (*RecurringResponse, error) {
+	recurring, err := u.findOwned(ctx, userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+	return toResponse(recurring), nil
+}
+
+func (u *useCase) Update(ctx context.Context, userID string, recurringID string, req *UpdateRecurringRequest) (*RecurringResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Pastikan template (dan kategori / akun baru) milik user
+	recurring, err := u.findOwned(ctx, userID, recurringID)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.checkCategory(ctx, userID, req.CategoryID); err != nil {
+		return nil, err
+	}
+	if req.AccountID != nil {
+		if _, err := account.Resolve(ctx, u.accountRepo, userID, req.AccountID); err != nil {
+			if errors.Is(err, account.ErrAccountNotFound) {
+				return nil, err
+			}
+			u.log.WithError(err).Error("Failed to resolve account")
+			return nil, ErrInternalServer
+		}
+	}
+
+	// 3. Terapkan perubahan parsial
+	if req.Name != nil {
+		recurring.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Amount != nil {
+		recurring.Amount = *req.Amount
+	}
+	if req.CategoryID != nil {
+		recurring.CategoryID = req.CategoryID
+	}
+	if req.AccountID != nil {
+		recurring.AccountID = req.AccountID
+	}
+	if req.RRule != nil {
+		rule, err := ParseRule(*req.RRule)
+		if err != nil {
+			return nil, err
+		}
+		recurring.Rule = rule.String()
+	}
+	if req.EndDate != nil {
+		if req.EndDate.Before(recurring.StartDate) {
+			return nil, ErrInvalidDate
+		}
+		recurring.EndDate = req.EndDate
+	}
+	if req.Active != nil {
+		recurring.Active = *req.Active
+	}
+
+	// 4. Perubahan jadwal dihitung ulang mulai hari ini
+	if req.RRule != nil || req.EndDate != nil || req.Active != nil {
+		rule, err := ParseRule(recurring.Rule)
+		if err != nil {
+			u.log.WithError(err).Error("Stored rrule cannot be parsed")
+			return nil, ErrInternalServer
+		}
+		recurring.NextRunAt = u.nextRun(rule, recurring, time.Now())
+	}
+
+	// 5. Simpan perubahan
+	if err := u.repo.Update(ctx, recurring); err != nil {
+		if errors.Is(err, ErrRecurringNotFound) {
+			return nil, err
+		}
+		u.log.WithError(err).Error("Failed to update recurring transaction")
+		return nil, ErrInternalServer
+	}
+
+	return toResponse(recurring), nil
+}
+
+func (u *useCase) Delete(ctx context.Context, userID string, recurringID string) error {
+	if _, err := uuid.Parse(recurringID); err != nil {
+		return ErrRecurringNotFound
+	}
+
+	if err := u.repo.Delete(ctx, recurringID, userID); err != nil {
+		if errors.Is(err, ErrRecurringNotFound) {
+			return err
+		}
+		u.log.WithError(err).Error("Failed to delete recurring transaction")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+func (u *useCase) Upcoming(ctx context.Context, userID string, req *UpcomingRequest) ([]OccurrenceResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Ambil semua template user
+	recurrings, err := u.repo.FindAllByUserID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list recurring transactions")
+		return nil, ErrInternalServer
+	}
+
+	// 3. Kembangkan jadwal mulai next_run_at (termasuk yang tertunda) sampai N hari ke depan
+	until := truncateDay(time.Now()).AddDate(0, 0, req.Days)
+	occurrences := make([]OccurrenceResponse, 0)
+	for _, r := range recurrings {
+		if !r.Active || r.NextRunAt == nil {
+			continue
+		}
+		rule, err := ParseRule(r.Rule)
+		if err != nil {
+			u.log.WithError(err).WithField("recurring_id", r.ID).Error("Stored rrule cannot be parsed")
+			continue
+		}
+		for _, date := range rule.Between(r.StartDate, r.EndDate, *r.NextRunAt, until) {
+			occurrences = append(occurrences, OccurrenceResponse{
+				RecurringID: r.ID,
+				Name:        r.Name,
+				Amount:      r.Amount,
+				CategoryID:  r.CategoryID,
+				Date:        date,
+			})
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Date.Before(occurrences[j].Date)
+	})
+	return occurrences, nil
+}
+
+func (u *useCase) MaterialiseDue(ctx context.Context) error {
+	now := time.Now()
+	due, err := u.repo.FindDue(ctx, now, dueBatchSize)
+	if err != nil {
+		return err
+	}
+
+	// Satu template gagal tidak menghentikan template lain
+	for i := range due {
+		if err := u.materialise(ctx, &due[i], now); err != nil {
+			u.log.WithError(err).WithField("recurring_id", due[i].ID).Error("Failed to materialise recurring transaction")
+		}
+	}
+	return nil
+}
+
+// materialise membuat history untuk setiap kejadian template yang sudah jatuh tempo, berurutan.
+// Kejadian di bulan yang belum punya budget ditunda sampai budget bulan itu dibuat;
+// kejadian di bulan yang budget-nya sudah ditutup dilewati.
+func (u *useCase) materialise(ctx context.Context, recurring *Recurring, now time.Time) error {
+	rule, err := ParseRule(recurring.Rule)
+	if err != nil {
+		return err
+	}
+
+	// Template tanpa akun (atau akunnya sudah dihapus) masuk ke akun default
+	accountID, err := u.accountFor(ctx, recurring)
+	if err != nil {
+		return err
+	}
+
+	for recurring.NextRunAt != nil && !recurring.NextRunAt.After(now) {
+		occurrence := *recurring.NextRunAt
+
+		// Bulan dihitung di UTC, sama dengan FindDue (pgx mengembalikan next_run_at di time.Local)
+		utc := occurrence.UTC()
+		monthStart := time.Date(utc.Year(), utc.Month(), 1, 0, 0, 0, 0, time.UTC)
+		parent, err := u.budgetRepo.FindByMonth(ctx, recurring.UserID, monthStart)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			u.log.WithField("recurring_id", recurring.ID).Debugf("No budget for %s yet, occurrence postponed", monthStart.Format("2006-01"))
+			return nil
+		}
+
+		var entry *history.History
+		if parent.ClosedAt != nil {
+			u.log.WithField("recurring_id", recurring.ID).Warnf("Budget %s already closed, occurrence %s skipped", monthStart.Format("2006-01"), occurrence.Format("2006-01-02"))
+		} else {
+			entry = &history.History{
+				ID:          uuid.New().String(),
+				BudgetID:    parent.ID,
+				CategoryID:  recurring.CategoryID,
+				AccountID:   accountID,
+				RecurringID: &recurring.ID,
+				Date:        occurrence,
+				Amount:      recurring.Amount,
+				CreatedAt:   time.Now(),
+			}
+		}
+
+		next := u.nextRun(rule, recurring, occurrence.AddDate(0, 0, 1))
+		if err := u.repo.Materialise(ctx, recurring, entry, next); err != nil {
+			return err
+		}
+		recurring.NextRunAt = next
+	}
+	return nil
+}
+
+// nextRun: kejadian pertama pada / setelah from, nil jika melewati end_date
+func (u *useCase) nextRun(rule *Rule, recurring *Recurring, from time.Time) *time.Time {
+	next := rule.NextOnOrAfter(recurring.StartDate, from)
+	if recurring.EndDate != nil && next.After(truncateDay(*recurring.EndDate)) {
+		return nil
+	}
+	return &next
+}
+
+// checkCategory: kategori opsional, tapi jika diisi harus milik user yang sama
+func (u *useCase) checkCategory(ctx context.Context, userID string, categoryID *string) error {
+	if categoryID == nil {
+		return nil
+	}
+
+	owned, err := u.categoryRepo.FindByID(ctx, *categoryID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find category")
+		return ErrInternalServer
+	}
+	if owned == nil {
+		return category.ErrCategoryNotFound
+	}
+	return nil
+}
+
+// accountFor: akun tempat history template dicatat
+func (u *useCase) accountFor(ctx context.Context, recurring *Recurring) (string, error) {
+	if recurring.AccountID != nil {
+		return *recurring.AccountID, nil
+	}
+
+	def, err := u.accountRepo.EnsureDefault(ctx, recurring.UserID)
+	if err != nil {
+		return "", err
+	}
+	return def.ID, nil
+}
+
+// findOwned mengambil template berdasarkan ID dan memastikan pemiliknya adalah userID
+func (u *useCase) findOwned(ctx context.Context, userID string, recurringID string) (*Recurring, error) {
+	// ID bukan UUID pasti tidak ada (hindari error cast dari Postgres)
+	if _, err := uuid.Parse(recurringID); err != nil {
+		return nil, ErrRecurringNotFound
+	}
+
+	recurring, err := u.repo.FindByID(ctx, recurringID, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to find recurring transaction")
+		return nil, ErrInternalServer
+	}
+	if recurring == nil {
+		return nil, ErrRecurringNotFound
+	}
+	return recurring, nil
+}
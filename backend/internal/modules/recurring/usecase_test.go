@@ -0,0 +1,622 @@
+package recurring_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/account"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/budget"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/category"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/history"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/recurring"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==========================================
+// 1. MOCK OBJECTS
+// ==========================================
+
+// MockRepository memalsukan behavior recurring.Repository
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Save(ctx context.Context, r *recurring.Recurring) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByID(ctx context.Context, id string, userID string) (*recurring.Recurring, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*recurring.Recurring), args.Error(1)
+}
+
+func (m *MockRepository) FindAllByUserID(ctx context.Context, userID string) ([]recurring.Recurring, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]recurring.Recurring), args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, r *recurring.Recurring) error {
+	args := m.Called(ctx, r)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindDue(ctx context.Context, now time.Time, limit int) ([]recurring.Recurring, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]recurring.Recurring), args.Error(1)
+}
+
+func (m *MockRepository) Materialise(ctx context.Context, r *recurring.Recurring, entry *history.History, next *time.Time) error {
+	args := m.Called(ctx, r, entry, next)
+	return args.Error(0)
+}
+
+// MockBudgetRepository memalsukan behavior budget.Repository
+type MockBudgetRepository struct {
+	mock.Mock
+}
+
+func (m *MockBudgetRepository) Save(ctx context.Context, b *budget.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) FindByID(ctx context.Context, id string, userID string) (*budget.Budget, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) FindAllByUserID(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]budget.Budget, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Update(ctx context.Context, b *budget.Budget) error {
+	args := m.Called(ctx, b)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) SetIncomeBudget(ctx context.Context, id string, income float64) (bool, error) {
+	args := m.Called(ctx, id, income)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) SumByCategory(ctx context.Context, budgetID string) ([]budget.CategoryTotal, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.CategoryTotal), args.Error(1)
+}
+
+func (m *MockBudgetRepository) FindAllocations(ctx context.Context, budgetID string) ([]budget.Allocation, error) {
+	args := m.Called(ctx, budgetID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]budget.Allocation), args.Error(1)
+}
+
+func (m *MockBudgetRepository) SetAllocation(ctx context.Context, a *budget.Allocation) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) DeleteAllocation(ctx context.Context, budgetID string, categoryID string) error {
+	args := m.Called(ctx, budgetID, categoryID)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) MoveAllocation(ctx context.Context, budgetID string, fromCategoryID string, toCategoryID string, amount float64, at time.Time) error {
+	args := m.Called(ctx, budgetID, fromCategoryID, toCategoryID, amount, at)
+	return args.Error(0)
+}
+
+func (m *MockBudgetRepository) FindByMonth(ctx context.Context, userID string, monthStart time.Time) (*budget.Budget, error) {
+	args := m.Called(ctx, userID, monthStart)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*budget.Budget), args.Error(1)
+}
+
+func (m *MockBudgetRepository) Close(ctx context.Context, b *budget.Budget, next *budget.Budget, createNext bool) error {
+	args := m.Called(ctx, b, next, createNext)
+	return args.Error(0)
+}
+
+// MockCategoryRepository memalsukan behavior category.Repository
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Save(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) SaveAll(ctx context.Context, categories []category.Category) error {
+	args := m.Called(ctx, categories)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) FindByID(ctx context.Context, id string, userID string) (*category.Category, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) FindAllByUserID(ctx context.Context, userID string) ([]category.Category, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]category.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, c *category.Category) error {
+	args := m.Called(ctx, c)
+	return args.Error(0)
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+// MockAccountRepository memalsukan behavior account.Repository
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountRepository) Save(ctx context.Context, a *account.Account) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindByID(ctx context.Context, id string, userID string) (*account.Account, error) {
+	args := m.Called(ctx, id, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) FindAllByUserID(ctx context.Context, userID string) ([]account.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) Update(ctx context.Context, a *account.Account) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) Delete(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) EnsureDefault(ctx context.Context, userID string) (*account.Account, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*account.Account), args.Error(1)
+}
+
+func (m *MockAccountRepository) SaveTransfer(ctx context.Context, t *account.Transfer) error {
+	args := m.Called(ctx, t)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) FindTransfers(ctx context.Context, userID string, dateFrom, dateTo *time.Time) ([]account.Transfer, error) {
+	args := m.Called(ctx, userID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.Transfer), args.Error(1)
+}
+
+func (m *MockAccountRepository) DeleteTransfer(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockAccountRepository) Ledger(ctx context.Context, accountID string, dateFrom, dateTo *time.Time) ([]account.LedgerEntry, error) {
+	args := m.Called(ctx, accountID, dateFrom, dateTo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]account.LedgerEntry), args.Error(1)
+}
+
+// ==========================================
+// 2. HELPER SETUP
+// ==========================================
+
+const (
+	ownerID     = "11111111-1111-1111-1111-111111111111"
+	budgetID    = "22222222-2222-2222-2222-222222222222"
+	recurringID = "33333333-3333-3333-3333-333333333333"
+	categoryID  = "44444444-4444-4444-4444-444444444444"
+
+	defaultAccountID = "55555555-5555-5555-5555-555555555555"
+	cardAccountID    = "66666666-6666-6666-6666-666666666666"
+)
+
+func setupTest() (recurring.UseCase, *MockRepository, *MockBudgetRepository, *MockCategoryRepository) {
+	u, mockRepo, mockBudgetRepo, mockCategoryRepo, _ := setupTestWithAccounts()
+	return u, mockRepo, mockBudgetRepo, mockCategoryRepo
+}
+
+func setupTestWithAccounts() (recurring.UseCase, *MockRepository, *MockBudgetRepository, *MockCategoryRepository, *MockAccountRepository) {
+	mockRepo := new(MockRepository)
+	mockBudgetRepo := new(MockBudgetRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	mockAccountRepo := new(MockAccountRepository)
+
+	// Template tanpa account_id dicatat ke akun default
+	mockAccountRepo.On("EnsureDefault", mock.Anything, ownerID).
+		Return(&account.Account{ID: defaultAccountID, UserID: ownerID, Name: account.DefaultAccountName, IsDefault: true}, nil).Maybe()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	u := recurring.NewUseCase(mockRepo, mockBudgetRepo, mockCategoryRepo, mockAccountRepo, log, validator.New())
+	return u, mockRepo, mockBudgetRepo, mockCategoryRepo, mockAccountRepo
+}
+
+func today() time.Time {
+	now := time.Now().UTC()
+	return date(now.Year(), now.Month(), now.Day())
+}
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// weeklyFrom: template mingguan yang dimulai pada start, next_run_at = start
+func weeklyFrom(start time.Time) *recurring.Recurring {
+	return &recurring.Recurring{
+		ID:        recurringID,
+		UserID:    ownerID,
+		Name:      "Sewa",
+		Amount:    500,
+		Rule:      "FREQ=WEEKLY;BYDAY=" + weekdayCodes[start.Weekday()],
+		StartDate: start,
+		NextRunAt: &start,
+		Active:    true,
+	}
+}
+
+// ==========================================
+// 3. GROUP: CREATE TESTS
+// ==========================================
+
+func TestCreate_Success(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	start := today().AddDate(0, -2, 0)
+	req := &recurring.CreateRecurringRequest{Name: " Netflix ", Amount: 54000, RRule: "freq=monthly;bymonthday=31", StartDate: &start}
+
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(r *recurring.Recurring) bool {
+		// Kejadian sebelum hari ini tidak dibuat mundur
+		return r.Name == "Netflix" && r.Rule == "FREQ=MONTHLY;BYMONTHDAY=31" &&
+			r.NextRunAt != nil && !r.NextRunAt.Before(today()) && r.Active
+	})).Return(nil)
+
+	resp, err := u.Create(context.Background(), ownerID, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "FREQ=MONTHLY;BYMONTHDAY=31", resp.RRule)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCreate_InvalidRRule(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	start := today()
+	resp, err := u.Create(context.Background(), ownerID, &recurring.CreateRecurringRequest{Name: "Gym", Amount: 10, RRule: "FREQ=DAILY", StartDate: &start})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_EndBeforeStart(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	start := today()
+	end := start.AddDate(0, 0, -1)
+	resp, err := u.Create(context.Background(), ownerID, &recurring.CreateRecurringRequest{Name: "Gym", Amount: 10, RRule: "FREQ=MONTHLY;BYMONTHDAY=1", StartDate: &start, EndDate: &end})
+
+	assert.ErrorIs(t, err, recurring.ErrInvalidDate)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_CategoryNotOwned(t *testing.T) {
+	u, mockRepo, _, mockCategoryRepo := setupTest()
+
+	start := today()
+	catID := categoryID
+	mockCategoryRepo.On("FindByID", mock.Anything, categoryID, ownerID).Return(nil, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, &recurring.CreateRecurringRequest{Name: "Gym", Amount: 10, RRule: "FREQ=MONTHLY;BYMONTHDAY=1", StartDate: &start, CategoryID: &catID})
+
+	assert.ErrorIs(t, err, category.ErrCategoryNotFound)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+func TestCreate_AccountNotOwned(t *testing.T) {
+	u, mockRepo, _, _, mockAccountRepo := setupTestWithAccounts()
+
+	start := today()
+	accID := cardAccountID
+	mockAccountRepo.On("FindByID", mock.Anything, cardAccountID, ownerID).Return(nil, nil)
+
+	resp, err := u.Create(context.Background(), ownerID, &recurring.CreateRecurringRequest{Name: "Netflix", Amount: 10, RRule: "FREQ=MONTHLY;BYMONTHDAY=1", StartDate: &start, AccountID: &accID})
+
+	assert.ErrorIs(t, err, account.ErrAccountNotFound)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
+// ==========================================
+// 4. GROUP: UPDATE & DELETE TESTS
+// ==========================================
+
+func TestUpdate_PauseKeepsSchedule(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	existing := weeklyFrom(today())
+	inactive := false
+	mockRepo.On("FindByID", mock.Anything, recurringID, ownerID).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *recurring.Recurring) bool {
+		return !r.Active && r.NextRunAt != nil
+	})).Return(nil)
+
+	resp, err := u.Update(context.Background(), ownerID, recurringID, &recurring.UpdateRecurringRequest{Active: &inactive})
+
+	assert.NoError(t, err)
+	assert.False(t, resp.Active)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdate_EndDateInPastFinishesSchedule(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	existing := weeklyFrom(today().AddDate(0, 0, -14))
+	end := today().AddDate(0, 0, -1)
+	mockRepo.On("FindByID", mock.Anything, recurringID, ownerID).Return(existing, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(r *recurring.Recurring) bool {
+		return r.NextRunAt == nil
+	})).Return(nil)
+
+	_, err := u.Update(context.Background(), ownerID, recurringID, &recurring.UpdateRecurringRequest{EndDate: &end})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGet_InvalidID(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	resp, err := u.Get(context.Background(), ownerID, "not-a-uuid")
+
+	assert.ErrorIs(t, err, recurring.ErrRecurringNotFound)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "FindByID")
+}
+
+func TestDelete_NotOwned(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	mockRepo.On("Delete", mock.Anything, recurringID, "intruder").Return(recurring.ErrRecurringNotFound)
+
+	err := u.Delete(context.Background(), "intruder", recurringID)
+
+	assert.ErrorIs(t, err, recurring.ErrRecurringNotFound)
+}
+
+// ==========================================
+// 5. GROUP: UPCOMING TESTS
+// ==========================================
+
+func TestUpcoming_SortedAcrossTemplates(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	weekly := weeklyFrom(today().AddDate(0, 0, 3))
+	weekly.ID = "weekly"
+	monthly := *weeklyFrom(today())
+	monthly.ID = "monthly"
+	monthly.Rule = "FREQ=MONTHLY;BYMONTHDAY=-1"
+	paused := *weeklyFrom(today())
+	paused.ID = "paused"
+	paused.Active = false
+
+	mockRepo.On("FindAllByUserID", mock.Anything, ownerID).Return([]recurring.Recurring{*weekly, monthly, paused}, nil)
+
+	resp, err := u.Upcoming(context.Background(), ownerID, &recurring.UpcomingRequest{Days: 14})
+
+	assert.NoError(t, err)
+	// Mingguan: hari ke-3 dan ke-10; bulanan: hanya jika akhir bulan dalam 14 hari
+	weeklyCount := 0
+	for i, occ := range resp {
+		assert.NotEqual(t, "paused", occ.RecurringID)
+		if i > 0 {
+			assert.False(t, occ.Date.Before(resp[i-1].Date))
+		}
+		if occ.RecurringID == "weekly" {
+			weeklyCount++
+		}
+	}
+	assert.Equal(t, 2, weeklyCount)
+}
+
+func TestUpcoming_InvalidDays(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	resp, err := u.Upcoming(context.Background(), ownerID, &recurring.UpcomingRequest{Days: 0})
+
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "FindAllByUserID")
+}
+
+// ==========================================
+// 6. GROUP: MATERIALISE TESTS
+// ==========================================
+
+func TestMaterialiseDue_CatchesUpEveryMissedOccurrence(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _ := setupTest()
+
+	start := today().AddDate(0, 0, -14)
+	mockRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything).Return([]recurring.Recurring{*weeklyFrom(start)}, nil)
+	mockBudgetRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(&budget.Budget{ID: budgetID, UserID: ownerID}, nil)
+	mockRepo.On("Materialise", mock.Anything, mock.Anything, mock.MatchedBy(func(h *history.History) bool {
+		return h.BudgetID == budgetID && h.AccountID == defaultAccountID && *h.RecurringID == recurringID
+	}), mock.Anything).Return(nil)
+
+	err := u.MaterialiseDue(context.Background())
+
+	assert.NoError(t, err)
+	// start, start+7 dan hari ini
+	mockRepo.AssertNumberOfCalls(t, "Materialise", 3)
+	last := mockRepo.Calls[len(mockRepo.Calls)-1]
+	assert.Equal(t, today().AddDate(0, 0, 7), *last.Arguments.Get(3).(*time.Time))
+}
+
+func TestMaterialiseDue_PostponesWithoutBudget(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _ := setupTest()
+
+	mockRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything).Return([]recurring.Recurring{*weeklyFrom(today())}, nil)
+	mockBudgetRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(nil, nil)
+
+	err := u.MaterialiseDue(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "Materialise")
+}
+
+func TestMaterialiseDue_SkipsClosedBudget(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _ := setupTest()
+
+	closedAt := time.Now()
+	mockRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything).Return([]recurring.Recurring{*weeklyFrom(today())}, nil)
+	mockBudgetRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(&budget.Budget{ID: budgetID, UserID: ownerID, ClosedAt: &closedAt}, nil)
+	// Tidak ada history baru, jadwal tetap dimajukan
+	mockRepo.On("Materialise", mock.Anything, mock.Anything, (*history.History)(nil), mock.Anything).Return(nil)
+
+	err := u.MaterialiseDue(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Materialise", 1)
+}
+
+func TestMaterialiseDue_MonthFromUTCOccurrence(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _ := setupTest()
+
+	// Server di UTC-5: 1 Februari 00:00 UTC terbaca sebagai 31 Januari
+	local := time.Local
+	time.Local = time.FixedZone("EST", -5*60*60)
+	t.Cleanup(func() { time.Local = local })
+
+	start := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC).In(time.Local)
+	template := weeklyFrom(start)
+	template.EndDate = &start
+
+	mockRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything).Return([]recurring.Recurring{*template}, nil)
+	mockBudgetRepo.On("FindByMonth", mock.Anything, ownerID, date(2026, 2, 1)).Return(&budget.Budget{ID: budgetID, UserID: ownerID}, nil)
+	mockRepo.On("Materialise", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	err := u.MaterialiseDue(context.Background())
+
+	assert.NoError(t, err)
+	mockBudgetRepo.AssertExpectations(t)
+	mockRepo.AssertNumberOfCalls(t, "Materialise", 1)
+}
+
+func TestMaterialiseDue_OneFailureDoesNotStopOthers(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _ := setupTest()
+
+	broken := *weeklyFrom(today())
+	broken.ID = "broken"
+	healthy := *weeklyFrom(today())
+
+	mockRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything).Return([]recurring.Recurring{broken, healthy}, nil)
+	mockBudgetRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(&budget.Budget{ID: budgetID, UserID: ownerID}, nil)
+	mockRepo.On("Materialise", mock.Anything, mock.MatchedBy(func(r *recurring.Recurring) bool { return r.ID == "broken" }), mock.Anything, mock.Anything).Return(errors.New("db down"))
+	mockRepo.On("Materialise", mock.Anything, mock.MatchedBy(func(r *recurring.Recurring) bool { return r.ID == recurringID }), mock.Anything, mock.Anything).Return(nil)
+
+	err := u.MaterialiseDue(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Materialise", 2)
+}
+
+func TestMaterialiseDue_UsesTemplateAccount(t *testing.T) {
+	u, mockRepo, mockBudgetRepo, _, mockAccountRepo := setupTestWithAccounts()
+
+	card := cardAccountID
+	template := weeklyFrom(today())
+	template.AccountID = &card
+
+	mockRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything).Return([]recurring.Recurring{*template}, nil)
+	mockBudgetRepo.On("FindByMonth", mock.Anything, ownerID, mock.Anything).Return(&budget.Budget{ID: budgetID, UserID: ownerID}, nil)
+	mockRepo.On("Materialise", mock.Anything, mock.Anything, mock.MatchedBy(func(h *history.History) bool {
+		return h.AccountID == cardAccountID
+	}), mock.Anything).Return(nil)
+
+	err := u.MaterialiseDue(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertNumberOfCalls(t, "Materialise", 1)
+	mockAccountRepo.AssertNotCalled(t, "EnsureDefault", mock.Anything, mock.Anything)
+}
+
+func TestMaterialiseDue_FindDueError(t *testing.T) {
+	u, mockRepo, _, _ := setupTest()
+
+	mockRepo.On("FindDue", mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+	err := u.MaterialiseDue(context.Background())
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,244 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/mailer"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+var (
+	ErrCannotModifySelf = apperror.BadRequest("cannot_modify_self", "admins cannot disable or reset their own account")
+)
+
+// defaultPageSize: jumlah data per halaman jika limit tidak dikirim
+const defaultPageSize = 20
+
+// AdminUseCase: Operasi khusus role admin, semua perubahan dicatat di audit trail
+type AdminUseCase interface {
+	SearchUsers(ctx context.Context, req *SearchUsersRequest) ([]AdminUserResponse, error)
+	GetUser(ctx context.Context, userID string) (*AdminUserResponse, error)
+	DisableUser(ctx context.Context, actor *AdminActionRequest, userID string) (*AdminUserResponse, error)
+	EnableUser(ctx context.Context, actor *AdminActionRequest, userID string) (*AdminUserResponse, error)
+	ForcePasswordReset(ctx context.Context, actor *AdminActionRequest, userID string) error
+	ListAuditLogs(ctx context.Context, req *ListAuditLogsRequest) ([]AuditLogResponse, error)
+}
+
+type adminUseCase struct {
+	accountSecurity
+	repo     Repository
+	audit    AuditRepository
+	validate *validator.Validate
+}
+
+func NewAdminUseCase(repo Repository, tokenRepo TokenRepository, revocations RevocationStore, sessions SessionStore, apiTokens APITokenRevoker, mail mailer.Mailer, audit AuditRepository, log *logrus.Logger, validate *validator.Validate, cfg *viper.Viper) AdminUseCase {
+	return &adminUseCase{
+		accountSecurity: accountSecurity{
+			tokenRepo:   tokenRepo,
+			revocations: revocations,
+			sessions:    sessions,
+			apiTokens:   apiTokens,
+			mailer:      mail,
+			log:         log,
+			cfg:         cfg,
+		},
+		repo:     repo,
+		audit:    audit,
+		validate: validate,
+	}
+}
+
+func (a *adminUseCase) SearchUsers(ctx context.Context, req *SearchUsersRequest) ([]AdminUserResponse, error) {
+	// 1. Validasi Input
+	if err := a.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPageSize // Default value
+	}
+
+	// 2. Cari User
+	users, err := a.repo.Search(ctx, req.Query, limit, req.Offset)
+	if err != nil {
+		a.log.WithError(err).Error("Admin: failed to search users")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]AdminUserResponse, 0, len(users))
+	for i := range users {
+		resp = append(resp, toAdminUserResponse(&users[i]))
+	}
+	return resp, nil
+}
+
+func (a *adminUseCase) GetUser(ctx context.Context, userID string) (*AdminUserResponse, error) {
+	user, err := a.findTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toAdminUserResponse(user)
+	return &resp, nil
+}
+
+// DisableUser: akun tidak bisa login dan semua sesi / token yang ada langsung diputus
+func (a *adminUseCase) DisableUser(ctx context.Context, actor *AdminActionRequest, userID string) (*AdminUserResponse, error) {
+	// 1. Cari User target
+	user, err := a.findTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == actor.ActorID {
+		return nil, ErrCannotModifySelf
+	}
+
+	// 2. Sudah nonaktif => tidak ada yang berubah
+	if user.DisabledAt != nil {
+		resp := toAdminUserResponse(user)
+		return &resp, nil
+	}
+
+	// 3. Nonaktifkan & catat di audit trail (satu transaksi)
+	now := time.Now()
+	if err := a.repo.SetDisabled(ctx, user.ID, &now, a.auditEntry(actor, AuditUserDisabled, user.ID)); err != nil {
+		return nil, a.mapRepoError(err, "failed to disable user")
+	}
+	a.log.Infof("Admin %s: %s on user %s", actor.ActorID, AuditUserDisabled, user.ID)
+
+	// 4. Putus semua sesi (token yang tersisa tetap ditolak karena akun sudah nonaktif)
+	if err := a.revokeAllSessions(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	user.DisabledAt = &now
+	resp := toAdminUserResponse(user)
+	return &resp, nil
+}
+
+func (a *adminUseCase) EnableUser(ctx context.Context, actor *AdminActionRequest, userID string) (*AdminUserResponse, error) {
+	// 1. Cari User target
+	user, err := a.findTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Sudah aktif => tidak ada yang berubah
+	if user.DisabledAt == nil {
+		resp := toAdminUserResponse(user)
+		return &resp, nil
+	}
+
+	// 3. Aktifkan kembali & catat di audit trail (satu transaksi)
+	if err := a.repo.SetDisabled(ctx, user.ID, nil, a.auditEntry(actor, AuditUserEnabled, user.ID)); err != nil {
+		return nil, a.mapRepoError(err, "failed to enable user")
+	}
+	a.log.Infof("Admin %s: %s on user %s", actor.ActorID, AuditUserEnabled, user.ID)
+
+	user.DisabledAt = nil
+	resp := toAdminUserResponse(user)
+	return &resp, nil
+}
+
+// ForcePasswordReset: semua sesi diputus dan user tidak bisa login sebelum mengganti password lewat email
+func (a *adminUseCase) ForcePasswordReset(ctx context.Context, actor *AdminActionRequest, userID string) error {
+	// 1. Cari User target
+	user, err := a.findTarget(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.ID == actor.ActorID {
+		return ErrCannotModifySelf
+	}
+
+	// 2. Tandai wajib reset & catat di audit trail (satu transaksi)
+	if err := a.repo.RequirePasswordReset(ctx, user.ID, a.auditEntry(actor, AuditUserPasswordReset, user.ID)); err != nil {
+		return a.mapRepoError(err, "failed to require password reset")
+	}
+	a.log.Infof("Admin %s: %s on user %s", actor.ActorID, AuditUserPasswordReset, user.ID)
+
+	// 3. Putus semua sesi (token yang tersisa tetap ditolak karena akun wajib reset)
+	if err := a.revokeAllSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// 4. Kirim link reset; jika gagal aksi tetap berhasil, user masih bisa memakai lupa password
+	if err := a.sendPasswordResetEmail(ctx, user); err != nil {
+		a.log.WithField("user_id", user.ID).Warn("Admin: password reset email not sent")
+	}
+	return nil
+}
+
+func (a *adminUseCase) ListAuditLogs(ctx context.Context, req *ListAuditLogsRequest) ([]AuditLogResponse, error) {
+	// 1. Validasi Input
+	if err := a.validate.Struct(req); err != nil {
+		return nil, err
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultPageSize // Default value
+	}
+
+	// 2. Ambil Audit Log
+	logs, err := a.audit.FindAll(ctx, req.TargetUserID, limit, req.Offset)
+	if err != nil {
+		a.log.WithError(err).Error("Admin: failed to list audit logs")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]AuditLogResponse, 0, len(logs))
+	for _, log := range logs {
+		resp = append(resp, AuditLogResponse{
+			ID:           log.ID,
+			ActorID:      log.ActorID,
+			Action:       log.Action,
+			TargetUserID: log.TargetUserID,
+			IPAddress:    log.IPAddress,
+			CreatedAt:    log.CreatedAt,
+		})
+	}
+	return resp, nil
+}
+
+// findTarget: ID bukan UUID pasti tidak ada (hindari error cast dari Postgres)
+func (a *adminUseCase) findTarget(ctx context.Context, userID string) (*User, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	user, err := a.repo.FindByID(ctx, userID)
+	if err != nil {
+		a.log.WithError(err).Error("Admin: failed to find user")
+		return nil, ErrInternalServer
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (a *adminUseCase) mapRepoError(err error, msg string) error {
+	if errors.Is(err, ErrUserNotFound) {
+		return err
+	}
+	a.log.WithError(err).Error("Admin: " + msg)
+	return ErrInternalServer
+}
+
+// auditEntry: satu aksi admin, disimpan oleh repository bersama perubahan user-nya
+// (aksi yang tidak tercatat dianggap gagal)
+func (a *adminUseCase) auditEntry(actor *AdminActionRequest, action string, targetUserID string) *AuditLog {
+	return &AuditLog{
+		ID:           uuid.New().String(),
+		ActorID:      actor.ActorID,
+		Action:       action,
+		TargetUserID: &targetUserID,
+		IPAddress:    actor.IPAddress,
+		CreatedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,114 @@
+package user
+
+import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AdminHandler struct {
+	useCase AdminUseCase
+}
+
+func NewAdminHandler(useCase AdminUseCase) *AdminHandler {
+	return &AdminHandler{useCase: useCase}
+}
+
+func (h *AdminHandler) SearchUsers(c *fiber.Ctx) error {
+	var req SearchUsersRequest
+	if err := c.QueryParser(&req); err != nil {
+		return apperror.ErrInvalidQuery
+	}
+
+	resp, err := h.useCase.SearchUsers(c.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *AdminHandler) GetUser(c *fiber.Ctx) error {
+	resp, err := h.useCase.GetUser(c.Context(), c.Params("user_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *AdminHandler) DisableUser(c *fiber.Ctx) error {
+	actor, ok := adminActor(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.DisableUser(c.Context(), actor, c.Params("user_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *AdminHandler) EnableUser(c *fiber.Ctx) error {
+	actor, ok := adminActor(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.EnableUser(c.Context(), actor, c.Params("user_id"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *AdminHandler) ForcePasswordReset(c *fiber.Ctx) error {
+	actor, ok := adminActor(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.ForcePasswordReset(c.Context(), actor, c.Params("user_id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *AdminHandler) ListAuditLogs(c *fiber.Ctx) error {
+	var req ListAuditLogsRequest
+	if err := c.QueryParser(&req); err != nil {
+		return apperror.ErrInvalidQuery
+	}
+
+	resp, err := h.useCase.ListAuditLogs(c.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+// adminActor membaca identitas admin dari Principal untuk audit trail
+func adminActor(c *fiber.Ctx) (*AdminActionRequest, bool) {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return nil, false
+	}
+	return &AdminActionRequest{ActorID: userID, IPAddress: c.IP()}, true
+}
+
+// RegisterRoutes: adminOnly = middleware.RequireRole(RoleAdmin), dipasang setelah authMiddleware
+func (h *AdminHandler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler, adminOnly fiber.Handler) {
+	api := app.Group("/api/admin")
+
+	api.Get("/users", authMiddleware, adminOnly, h.SearchUsers)
+	api.Get("/users/:user_id", authMiddleware, adminOnly, h.GetUser)
+	api.Post("/users/:user_id/disable", authMiddleware, adminOnly, h.DisableUser)
+	api.Post("/users/:user_id/enable", authMiddleware, adminOnly, h.EnableUser)
+	api.Post("/users/:user_id/password-reset", authMiddleware, adminOnly, h.ForcePasswordReset)
+	api.Get("/audit-logs", authMiddleware, adminOnly, h.ListAuditLogs)
+}
@@ -0,0 +1,220 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/mailer"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/user"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ==========================================
+// 1. MOCK OBJECTS
+// ==========================================
+
+// MockAuditRepository memalsukan behavior AuditRepository
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) FindAll(ctx context.Context, targetUserID string, limit int, offset int) ([]user.AuditLog, error) {
+	args := m.Called(ctx, targetUserID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]user.AuditLog), args.Error(1)
+}
+
+// ==========================================
+// 2. HELPER SETUP
+// ==========================================
+
+const (
+	adminID  = "11111111-1111-1111-1111-111111111111"
+	targetID = "22222222-2222-2222-2222-222222222222"
+)
+
+type adminMocks struct {
+	mocks
+	audit *MockAuditRepository
+}
+
+func setupAdmin() (user.AdminUseCase, *adminMocks) {
+	m := &adminMocks{
+		mocks: mocks{
+			repo:        new(MockRepository),
+			tokenRepo:   new(MockTokenRepository),
+			revocations: new(MockRevocationStore),
+			sessions:    newPermissiveSessions(),
+			apiTokens:   newPermissiveAPITokens(),
+			mailer:      new(MockMailer),
+			cfg:         viper.New(),
+		},
+		audit: new(MockAuditRepository),
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	useCase := user.NewAdminUseCase(m.repo, m.tokenRepo, m.revocations, m.sessions, m.apiTokens, m.mailer, m.audit, log, validator.New(), m.cfg)
+	return useCase, m
+}
+
+func adminActor() *user.AdminActionRequest {
+	return &user.AdminActionRequest{ActorID: adminID, IPAddress: "10.0.0.1"}
+}
+
+// ==========================================
+// 3. GROUP: ADMIN TESTS
+// ==========================================
+
+func TestAdminSearchUsers_DefaultLimit(t *testing.T) {
+	u, m := setupAdmin()
+
+	m.repo.On("Search", mock.Anything, "budi", 20, 0).Return([]user.User{
+		{ID: targetID, Username: "budi", Email: "budi@example.com", Role: user.RoleUser},
+	}, nil)
+
+	resp, err := u.SearchUsers(context.Background(), &user.SearchUsersRequest{Query: "budi"})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, "budi", resp[0].Username)
+	assert.Nil(t, resp[0].DisabledAt)
+}
+
+func TestAdminGetUser_InvalidID(t *testing.T) {
+	u, m := setupAdmin()
+
+	resp, err := u.GetUser(context.Background(), "not-a-uuid")
+
+	assert.Nil(t, resp)
+	assert.Equal(t, user.ErrUserNotFound, err)
+	m.repo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestAdminDisableUser_RevokesSessionsAndAudits(t *testing.T) {
+	u, m := setupAdmin()
+
+	m.repo.On("FindByID", mock.Anything, targetID).Return(&user.User{ID: targetID, Role: user.RoleUser}, nil)
+	m.repo.On("SetDisabled", mock.Anything, targetID, mock.MatchedBy(func(at *time.Time) bool { return at != nil }), mock.MatchedBy(func(entry *user.AuditLog) bool {
+		return entry.ActorID == adminID && entry.Action == user.AuditUserDisabled &&
+			*entry.TargetUserID == targetID && entry.IPAddress == "10.0.0.1"
+	})).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, targetID, mock.Anything).Return(nil)
+	m.tokenRepo.On("RevokeAllByUserID", mock.Anything, targetID).Return(nil)
+
+	resp, err := u.DisableUser(context.Background(), adminActor(), targetID)
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.DisabledAt)
+	m.sessions.AssertCalled(t, "TerminateAllForUser", mock.Anything, targetID)
+	m.apiTokens.AssertCalled(t, "RevokeAllByUserID", mock.Anything, targetID)
+	m.repo.AssertExpectations(t)
+}
+
+func TestAdminDisableUser_AuditFailureKeepsSessions(t *testing.T) {
+	u, m := setupAdmin()
+
+	// Audit log gagal => transaksi di-rollback, akun tidak berubah dan sesi tidak diputus
+	m.repo.On("FindByID", mock.Anything, targetID).Return(&user.User{ID: targetID, Role: user.RoleUser}, nil)
+	m.repo.On("SetDisabled", mock.Anything, targetID, mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	resp, err := u.DisableUser(context.Background(), adminActor(), targetID)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, user.ErrInternalServer, err)
+	m.revocations.AssertNotCalled(t, "RevokeAllForUser", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminDisableUser_CannotDisableSelf(t *testing.T) {
+	u, m := setupAdmin()
+
+	m.repo.On("FindByID", mock.Anything, adminID).Return(&user.User{ID: adminID, Role: user.RoleAdmin}, nil)
+
+	resp, err := u.DisableUser(context.Background(), adminActor(), adminID)
+
+	assert.Nil(t, resp)
+	assert.Equal(t, user.ErrCannotModifySelf, err)
+	m.repo.AssertNotCalled(t, "SetDisabled", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminEnableUser_ClearsDisabled(t *testing.T) {
+	u, m := setupAdmin()
+
+	disabledAt := time.Now().Add(-time.Hour)
+	m.repo.On("FindByID", mock.Anything, targetID).Return(&user.User{ID: targetID, DisabledAt: &disabledAt}, nil)
+	m.repo.On("SetDisabled", mock.Anything, targetID, (*time.Time)(nil), mock.MatchedBy(func(entry *user.AuditLog) bool {
+		return entry.Action == user.AuditUserEnabled
+	})).Return(nil)
+
+	resp, err := u.EnableUser(context.Background(), adminActor(), targetID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, resp.DisabledAt)
+	m.repo.AssertExpectations(t)
+}
+
+func TestAdminForcePasswordReset_SendsEmailAndAudits(t *testing.T) {
+	u, m := setupAdmin()
+
+	target := &user.User{ID: targetID, Username: "budi", Email: "budi@example.com"}
+	m.repo.On("FindByID", mock.Anything, targetID).Return(target, nil)
+	m.repo.On("RequirePasswordReset", mock.Anything, targetID, mock.MatchedBy(func(entry *user.AuditLog) bool {
+		return entry.Action == user.AuditUserPasswordReset
+	})).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, targetID, mock.Anything).Return(nil)
+	m.tokenRepo.On("RevokeAllByUserID", mock.Anything, targetID).Return(nil)
+	m.tokenRepo.On("SaveActionToken", mock.Anything, mock.MatchedBy(func(token *user.ActionToken) bool {
+		return token.UserID == targetID && token.Purpose == user.PurposePasswordReset
+	})).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.MatchedBy(func(msg *mailer.Message) bool {
+		return msg.To == target.Email
+	})).Return(nil)
+
+	err := u.ForcePasswordReset(context.Background(), adminActor(), targetID)
+
+	assert.NoError(t, err)
+	m.mailer.AssertExpectations(t)
+	m.repo.AssertExpectations(t)
+	m.apiTokens.AssertCalled(t, "RevokeAllByUserID", mock.Anything, targetID)
+}
+
+func TestAdminForcePasswordReset_EmailFailureStillSucceeds(t *testing.T) {
+	u, m := setupAdmin()
+
+	target := &user.User{ID: targetID, Username: "budi", Email: "budi@example.com"}
+	m.repo.On("FindByID", mock.Anything, targetID).Return(target, nil)
+	m.repo.On("RequirePasswordReset", mock.Anything, targetID, mock.Anything).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, targetID, mock.Anything).Return(nil)
+	m.tokenRepo.On("RevokeAllByUserID", mock.Anything, targetID).Return(nil)
+	m.tokenRepo.On("SaveActionToken", mock.Anything, mock.Anything).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+
+	// Aksi sudah tercatat; user masih bisa memakai lupa password
+	err := u.ForcePasswordReset(context.Background(), adminActor(), targetID)
+
+	assert.NoError(t, err)
+}
+
+func TestAdminListAuditLogs_FilterByTarget(t *testing.T) {
+	u, m := setupAdmin()
+
+	m.audit.On("FindAll", mock.Anything, targetID, 5, 10).Return([]user.AuditLog{
+		{ID: "log-1", ActorID: adminID, Action: user.AuditUserDisabled, CreatedAt: time.Now()},
+	}, nil)
+
+	resp, err := u.ListAuditLogs(context.Background(), &user.ListAuditLogsRequest{TargetUserID: targetID, Limit: 5, Offset: 10})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp, 1)
+	assert.Equal(t, user.AuditUserDisabled, resp[0].Action)
+}
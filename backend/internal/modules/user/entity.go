@@ -9,36 +9,280 @@ type User struct {
 	Password  string
 	CreatedAt time.Time
 	DeletedAt *time.Time
+
+	EmailVerifiedAt *time.Time
+
+	TOTPSecret    *string
+	TOTPEnabledAt *time.Time
+
+	Role                  string
+	DisabledAt            *time.Time
+	PasswordResetRequired bool
+}
+
+// Role user; hanya RoleAdmin yang bisa mengakses /api/admin
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Session: Satu login (device); ID sama dengan FamilyID refresh token
+type Session struct {
+	ID           string
+	UserID       string
+	UserAgent    string
+	IPAddress    string
+	CreatedAt    time.Time
+	LastSeenAt   time.Time
+	TerminatedAt *time.Time
+}
+
+// SessionResponse: Daftar device yang sedang login
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IPAddress  string    `json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Current    bool      `json:"current"`
+}
+
+// RefreshToken: Satu token hasil rotasi dalam sebuah family (satu sesi login)
+type RefreshToken struct {
+	ID         string
+	UserID     string
+	FamilyID   string
+	TokenHash  string
+	ExpiresAt  time.Time
+	CreatedAt  time.Time
+	RevokedAt  *time.Time
+	ReplacedBy *string
+}
+
+// Tujuan ActionToken
+const (
+	PurposePasswordReset     = "password_reset"
+	PurposeEmailVerification = "email_verification"
+	PurposeMFAChallenge      = "mfa_challenge"
+)
+
+// ActionToken: Token sekali pakai yang dikirim lewat email
+type ActionToken struct {
+	ID        string
+	UserID    string
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
 }
 
 // UserResponse: Format standar data user untuk output JSON
 type UserResponse struct {
-	ID        string    `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
+	ID              string     `json:"id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	TOTPEnabled     bool       `json:"totp_enabled"`
+	Role            string     `json:"role"`
+	CreatedAt       time.Time  `json:"created_at"`
 }
 
 // RegisterRequest: Validasi input saat daftar
 type RegisterRequest struct {
 	Username string `json:"username" validate:"required,alphanum,min=3,max=30"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Password string `json:"password" validate:"required"` // Aturan lain dicek oleh password policy
 }
 
 type RegisterResponse struct {
 	UserResponse
 }
 
+// UpdateProfileRequest: Semua field opsional (PATCH).
+// Password (password saat ini) wajib diisi jika email diubah.
+type UpdateProfileRequest struct {
+	Username *string `json:"username" validate:"omitempty,alphanum,min=3,max=30"`
+	Email    *string `json:"email" validate:"omitempty,email"`
+	Password string  `json:"password"`
+}
+
+// ChangePasswordRequest: Ganti password, wajib menyertakan password lama
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// ForgotPasswordRequest: Minta link reset password
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest: Set password baru menggunakan token dari email
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+// VerifyEmailRequest: Token verifikasi dari link email (query ?token=)
+type VerifyEmailRequest struct {
+	Token string `query:"token" validate:"required"`
+}
+
+// ResendVerificationRequest: Kirim ulang email verifikasi
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// DeleteAccountRequest: Hapus akun wajib dikonfirmasi dengan password
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
 // LoginRequest: Validasi input saat login
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
+	// Identifier: email atau username, tidak membedakan huruf besar/kecil
+	Identifier string `json:"identifier" validate:"required_without=Email,max=255"`
+	// Email: field lama, tetap diterima agar client lama tidak rusak (pakai Identifier)
+	Email    string `json:"email" validate:"omitempty,email"`
 	Password string `json:"password" validate:"required"`
+
+	// IPAddress & UserAgent diisi oleh handler dari koneksi client, bukan dari body
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
 }
 
-// LoginResponse: WAJIB mengandung Token
+// LoginResponse: WAJIB mengandung Token.
+// Jika 2FA aktif, hanya MFARequired + MFAToken yang terisi; token didapat lewat LoginMFA.
 type LoginResponse struct {
-	AccessToken string       `json:"access_token"`
-	TokenType   string       `json:"token_type"`
-	User        UserResponse `json:"user"`
+	AccessToken  string        `json:"access_token,omitempty"`
+	RefreshToken string        `json:"refresh_token,omitempty"`
+	TokenType    string        `json:"token_type,omitempty"`
+	ExpiresIn    int64         `json:"expires_in,omitempty"`
+	User         *UserResponse `json:"user,omitempty"`
+
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
+
+// LoginMFARequest: Langkah kedua login, code berupa kode TOTP atau recovery code
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+
+	IPAddress string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+// TOTPEnrollmentResponse: Secret untuk dimasukkan ke aplikasi authenticator
+type TOTPEnrollmentResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// ConfirmTOTPRequest: Kode dari authenticator untuk mengaktifkan 2FA
+type ConfirmTOTPRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// RecoveryCodesResponse: Ditampilkan SEKALI saat 2FA diaktifkan
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// DisableTOTPRequest: Wajib password dan kode (TOTP atau recovery code)
+type DisableTOTPRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// LogoutRequest: Refresh token opsional, jika dikirim family-nya ikut dicabut
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// AccessTokenClaims: Identitas access token yang sedang dipakai (diisi oleh middleware)
+type AccessTokenClaims struct {
+	JTI       string
+	UserID    string
+	SessionID string
+	ExpiresAt time.Time
+}
+
+// RefreshRequest: Tukar refresh token lama dengan pasangan token baru
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+func toUserResponse(user *User) UserResponse {
+	return UserResponse{
+		ID:              user.ID,
+		Username:        user.Username,
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		TOTPEnabled:     user.TOTPEnabledAt != nil,
+		Role:            user.Role,
+		CreatedAt:       user.CreatedAt,
+	}
+}
+
+// AdminUserResponse: Data user untuk admin, termasuk status akun
+type AdminUserResponse struct {
+	UserResponse
+	DisabledAt            *time.Time `json:"disabled_at"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+}
+
+// SearchUsersRequest: Cari berdasarkan username / email (opsional), dengan paginasi
+type SearchUsersRequest struct {
+	Query  string `query:"q" validate:"max=100"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+// AdminActionRequest: Identitas admin yang melakukan aksi, untuk audit trail
+type AdminActionRequest struct {
+	ActorID   string `json:"-"`
+	IPAddress string `json:"-"`
+}
+
+// Aksi admin yang dicatat di audit trail
+const (
+	AuditUserDisabled      = "user.disabled"
+	AuditUserEnabled       = "user.enabled"
+	AuditUserPasswordReset = "user.password_reset_forced"
+)
+
+// AuditLog: Satu aksi admin
+type AuditLog struct {
+	ID           string
+	ActorID      string
+	Action       string
+	TargetUserID *string
+	IPAddress    string
+	CreatedAt    time.Time
+}
+
+type AuditLogResponse struct {
+	ID           string    `json:"id"`
+	ActorID      string    `json:"actor_id"`
+	Action       string    `json:"action"`
+	TargetUserID *string   `json:"target_user_id"`
+	IPAddress    string    `json:"ip_address"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ListAuditLogsRequest: Filter opsional per user target, dengan paginasi
+type ListAuditLogsRequest struct {
+	TargetUserID string `query:"target_user_id" validate:"omitempty,uuid"`
+	Limit        int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset       int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+func toAdminUserResponse(user *User) AdminUserResponse {
+	return AdminUserResponse{
+		UserResponse:          toUserResponse(user),
+		DisabledAt:            user.DisabledAt,
+		PasswordResetRequired: user.PasswordResetRequired,
+	}
 }
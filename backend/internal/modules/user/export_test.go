@@ -0,0 +1,15 @@
+package user
+
+import "time"
+
+// TOTPCodeAt membuka totpCode untuk test di package user_test
+func TOTPCodeAt(secret string, at time.Time) string {
+	key, _ := base32NoPadding.DecodeString(secret)
+	return totpCode(key, uint64(at.Unix()/totpPeriod))
+}
+
+// HashToken membuka hashToken untuk test di package user_test
+var HashToken = hashToken
+
+// IsBeforeCutoff membuka isBeforeCutoff untuk test di package user_test
+var IsBeforeCutoff = isBeforeCutoff
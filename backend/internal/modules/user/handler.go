@@ -1,6 +1,8 @@
 package user
 
 import (
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -15,22 +17,15 @@ func NewHandler(useCase UseCase) *Handler {
 func (h *Handler) Register(c *fiber.Ctx) error {
 	var req RegisterRequest
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+		return apperror.ErrInvalidBody
 	}
 
 	user, err := h.useCase.Register(c.Context(), &req)
 	if err != nil {
-		// 1. Cek Error Spesifik (409 Conflict)
-		if err == ErrEmailTaken || err == ErrUsernameTaken {
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
-		}
-
-		// 2. Default Error (500 Internal Server Error)
-		// Tidak perlu 'if err != nil' lagi disini, karena sudah pasti error (else logic)
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Internal Server Error"})
+		return err
 	}
 
-	// 3. Sukses (201 Created)
+	// Sukses (201 Created)
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"data": user})
 }
 
@@ -39,25 +34,15 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 
 	// 1. Parsing Body
 	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid request Body",
-		})
+		return apperror.ErrInvalidBody
 	}
 
 	// 2. Panggil Usecase
+	req.IPAddress = c.IP()
+	req.UserAgent = c.Get(fiber.HeaderUserAgent)
 	resp, err := h.useCase.Login(c.Context(), &req)
 	if err != nil {
-		// 3. Error Handling Spesifik
-		if err == ErrInvalidCredentials {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-
-		// Jika Error Lain (Misal DB mati/config error), return 500
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Internal Server Error",
-		})
+		return err
 	}
 	// Sukses - Return Token (Status 200 OK)
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -65,16 +50,278 @@ func (h *Handler) Login(c *fiber.Ctx) error {
 	})
 }
 
+func (h *Handler) Refresh(c *fiber.Ctx) error {
+	var req RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.Refresh(c.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) Logout(c *fiber.Ctx) error {
+	claims, ok := accessTokenClaims(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	// Body opsional: boleh kosong
+	var req LogoutRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return apperror.ErrInvalidBody
+		}
+	}
+
+	if err := h.useCase.Logout(c.Context(), claims, &req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) LogoutAll(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.LogoutAll(c.Context(), userID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) ChangePassword(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	if err := h.useCase.ChangePassword(c.Context(), userID, &req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) ForgotPassword(c *fiber.Ctx) error {
+	var req ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	if err := h.useCase.ForgotPassword(c.Context(), &req); err != nil {
+		return err
+	}
+
+	// Respon sama untuk email terdaftar maupun tidak
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) ResetPassword(c *fiber.Ctx) error {
+	var req ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	if err := h.useCase.ResetPassword(c.Context(), &req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) VerifyEmail(c *fiber.Ctx) error {
+	var req VerifyEmailRequest
+	if err := c.QueryParser(&req); err != nil {
+		return apperror.ErrInvalidQuery
+	}
+
+	resp, err := h.useCase.VerifyEmail(c.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) ResendVerification(c *fiber.Ctx) error {
+	var req ResendVerificationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	if err := h.useCase.ResendVerification(c.Context(), &req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) DeleteAccount(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req DeleteAccountRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	if err := h.useCase.DeleteAccount(c.Context(), userID, &req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) LoginMFA(c *fiber.Ctx) error {
+	var req LoginMFARequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	req.IPAddress = c.IP()
+	req.UserAgent = c.Get(fiber.HeaderUserAgent)
+	resp, err := h.useCase.LoginMFA(c.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) EnrollTOTP(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	resp, err := h.useCase.EnrollTOTP(c.Context(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) ConfirmTOTP(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req ConfirmTOTPRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.ConfirmTOTP(c.Context(), userID, &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) DisableTOTP(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req DisableTOTPRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	if err := h.useCase.DisableTOTP(c.Context(), userID, &req); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+func (h *Handler) ListSessions(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	// SessionID kosong jika request memakai API token
+	resp, err := h.useCase.ListSessions(c.Context(), principal.UserID, principal.SessionID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) TerminateSession(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	if err := h.useCase.TerminateSession(c.Context(), userID, c.Params("session_id")); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": true})
+}
+
+// accessTokenClaims membaca identitas access token dari Principal yang diset AuthMiddleware.
+// ok=false untuk API token, karena tidak punya jti / sesi.
+func accessTokenClaims(c *fiber.Ctx) (*AccessTokenClaims, bool) {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok || principal.TokenID == "" || principal.SessionID == "" {
+		return nil, false
+	}
+	return &AccessTokenClaims{JTI: principal.TokenID, UserID: principal.UserID, SessionID: principal.SessionID, ExpiresAt: principal.ExpiresAt}, true
+}
+
 func (h *Handler) GetMe(c *fiber.Ctx) error {
 	// Ambil user_id dari Locals (yang diset oleh Middleware)
-	userID, ok := c.Locals("user_id").(string)
+	userID, ok := middleware.CurrentUserID(c)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+		return apperror.ErrUnauthorized
 	}
 
 	resp, err := h.useCase.GetMe(c.Context(), userID)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Internal Server Error"})
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
+}
+
+func (h *Handler) UpdateProfile(c *fiber.Ctx) error {
+	userID, ok := middleware.CurrentUserID(c)
+	if !ok {
+		return apperror.ErrUnauthorized
+	}
+
+	var req UpdateProfileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.ErrInvalidBody
+	}
+
+	resp, err := h.useCase.UpdateProfile(c.Context(), userID, &req)
+	if err != nil {
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"data": resp})
@@ -85,6 +332,24 @@ func (h *Handler) RegisterRoutes(app *fiber.App, authMiddleware fiber.Handler) {
 
 	api.Post("/register", h.Register)
 	api.Post("/login", h.Login)
+	api.Post("/login/mfa", h.LoginMFA)
+	api.Post("/refresh", h.Refresh)
+	api.Post("/logout", authMiddleware, h.Logout)
+	api.Post("/logout-all", authMiddleware, h.LogoutAll)
+	api.Post("/password/forgot", h.ForgotPassword)
+	api.Post("/password/reset", h.ResetPassword)
+	api.Get("/verify", h.VerifyEmail)
+	api.Post("/verify/resend", h.ResendVerification)
 
 	api.Get("/current", authMiddleware, h.GetMe)
+	api.Patch("/current", authMiddleware, h.UpdateProfile)
+	api.Put("/current/password", authMiddleware, h.ChangePassword)
+	api.Delete("/current", authMiddleware, h.DeleteAccount)
+
+	api.Get("/current/sessions", authMiddleware, h.ListSessions)
+	api.Delete("/current/sessions/:session_id", authMiddleware, h.TerminateSession)
+
+	api.Post("/current/mfa/totp", authMiddleware, h.EnrollTOTP)
+	api.Post("/current/mfa/totp/confirm", authMiddleware, h.ConfirmTOTP)
+	api.Delete("/current/mfa/totp", authMiddleware, h.DisableTOTP)
 }
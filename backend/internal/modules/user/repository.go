@@ -4,21 +4,42 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 var (
-	ErrEmailTaken    = errors.New("email already taken")
-	ErrUsernameTaken = errors.New("username already taken")
+	ErrEmailTaken    = apperror.Conflict("email_taken", "email already taken")
+	ErrUsernameTaken = apperror.Conflict("username_taken", "username already taken")
 )
 
 type Repository interface {
 	Save(ctx context.Context, user *User) error
 	FindByEmail(ctx context.Context, email string) (*User, error)
+	FindByUsername(ctx context.Context, username string) (*User, error)
 	FindByID(ctx context.Context, id string) (*User, error)
+	Update(ctx context.Context, user *User) error
+	UpdatePassword(ctx context.Context, id string, password string) error
+	UpdatePasswordHash(ctx context.Context, id string, oldHash string, newHash string) error
+	MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error
+	SoftDelete(ctx context.Context, id string, deletedAt time.Time) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+
+	SetTOTPSecret(ctx context.Context, id string, secret string) error
+	EnableTOTP(ctx context.Context, id string, enabledAt time.Time, recoveryCodeHashes []string) error
+	DisableTOTP(ctx context.Context, id string) error
+	UseTOTPStep(ctx context.Context, id string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error)
+
+	Search(ctx context.Context, query string, limit int, offset int) ([]User, error)
+	// SetDisabled & RequirePasswordReset dipakai admin: perubahan dan audit log-nya disimpan dalam satu transaksi
+	SetDisabled(ctx context.Context, id string, disabledAt *time.Time, audit *AuditLog) error
+	RequirePasswordReset(ctx context.Context, id string, audit *AuditLog) error
 }
 
 type repository struct {
@@ -32,53 +53,301 @@ func NewRepository(db *pgxpool.Pool) Repository {
 func (r *repository) Save(ctx context.Context, user *User) error {
 	// Ubah query kolom name -> username
 	query := `
-		INSERT INTO users (id, username, email, password, created_at, deleted_at) 
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO users (id, username, email, password, created_at, deleted_at, role) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
-	_, err := r.db.Exec(ctx, query, user.ID, user.Username, user.Email, user.Password, user.CreatedAt, user.DeletedAt)
-
-	if err != nil {
-		var pgErr *pgconn.PgError
-		// Cek error Postgres Unique Violation (Code 23505)
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			// Deteksi constraint mana yang kena
-			// Pastikan nama constraint di DB Anda sesuai, atau gunakan logic strings.Contains
-			if strings.Contains(pgErr.ConstraintName, "email") {
-				return ErrEmailTaken
-			}
-			if strings.Contains(pgErr.ConstraintName, "username") {
-				return ErrUsernameTaken
-			}
+	_, err := r.db.Exec(ctx, query, user.ID, user.Username, user.Email, user.Password, user.CreatedAt, user.DeletedAt, user.Role)
+
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+	return nil
+}
+
+func (r *repository) Update(ctx context.Context, user *User) error {
+	query := `UPDATE users SET username = $1, email = $2, email_verified_at = $3 WHERE id = $4 AND deleted_at IS NULL`
+
+	_, err := r.db.Exec(ctx, query, user.Username, user.Email, user.EmailVerifiedAt, user.ID)
+	if err != nil {
+		return mapUniqueViolation(err)
+	}
+	return nil
+}
+
+// UpdatePassword sekaligus menghapus kewajiban reset password dari admin
+func (r *repository) UpdatePassword(ctx context.Context, id string, password string) error {
+	query := `UPDATE users SET password = $1, password_reset_required = FALSE WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.db.Exec(ctx, query, password, id)
+	return err
+}
+
+// UpdatePasswordHash mengganti hash untuk password yang SAMA (upgrade algoritma / parameter).
+// Hanya berlaku jika hash belum berubah, supaya tidak menimpa password yang baru saja diganti.
+func (r *repository) UpdatePasswordHash(ctx context.Context, id string, oldHash string, newHash string) error {
+	query := `UPDATE users SET password = $1 WHERE id = $2 AND password = $3 AND deleted_at IS NULL`
+	_, err := r.db.Exec(ctx, query, newHash, id, oldHash)
+	return err
+}
+
+// Search mencari user aktif (belum dihapus) berdasarkan potongan username / email
+func (r *repository) Search(ctx context.Context, query string, limit int, offset int) ([]User, error) {
+	sql := `
+		SELECT ` + userColumns + ` FROM users
+		WHERE deleted_at IS NULL
+		  AND ($1 = '' OR username ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%')
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(ctx, sql, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
 		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+// SetDisabled: disabledAt nil berarti akun diaktifkan kembali
+func (r *repository) SetDisabled(ctx context.Context, id string, disabledAt *time.Time, audit *AuditLog) error {
+	return r.updateWithAudit(ctx, audit, `UPDATE users SET disabled_at = $1 WHERE id = $2 AND deleted_at IS NULL`, disabledAt, id)
+}
+
+// RequirePasswordReset: user wajib reset password sebelum bisa login lagi
+func (r *repository) RequirePasswordReset(ctx context.Context, id string, audit *AuditLog) error {
+	return r.updateWithAudit(ctx, audit, `UPDATE users SET password_reset_required = TRUE WHERE id = $1 AND deleted_at IS NULL`, id)
+}
+
+// updateWithAudit menjalankan satu UPDATE users dan menyimpan audit log-nya secara atomik:
+// perubahan tanpa audit log (atau sebaliknya) tidak pernah tersimpan
+func (r *repository) updateWithAudit(ctx context.Context, audit *AuditLog, query string, args ...any) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
 		return err
 	}
-	return nil
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	insert := `
+		INSERT INTO audit_logs (id, actor_id, action, target_user_id, ip_address, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	if _, err := tx.Exec(ctx, insert, audit.ID, audit.ActorID, audit.Action, audit.TargetUserID, audit.IPAddress, audit.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
-func (r *repository) FindByEmail(ctx context.Context, email string) (*User, error) {
-	query := `SELECT id, username, email, password, created_at, deleted_at FROM users WHERE email = $1`
+// mapUniqueViolation menerjemahkan Unique Violation Postgres ke error domain
+func mapUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	// Cek error Postgres Unique Violation (Code 23505)
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		// Deteksi constraint mana yang kena
+		// Pastikan nama constraint di DB Anda sesuai, atau gunakan logic strings.Contains
+		if strings.Contains(pgErr.ConstraintName, "email") {
+			return ErrEmailTaken
+		}
+		if strings.Contains(pgErr.ConstraintName, "username") {
+			return ErrUsernameTaken
+		}
+	}
+	return err
+}
+
+// userColumns: Urutan kolom harus sama dengan urutan Scan di scanUser
+const userColumns = `id, username, email, password, created_at, deleted_at, email_verified_at, totp_secret, totp_enabled_at,
+	role, disabled_at, password_reset_required`
 
+func scanUser(row pgx.Row) (*User, error) {
 	var user User
-	err := r.db.QueryRow(ctx, query, email).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password, &user.CreatedAt, &user.DeletedAt,
+	err := row.Scan(
+		&user.ID, &user.Username, &user.Email, &user.Password, &user.CreatedAt, &user.DeletedAt, &user.EmailVerifiedAt,
+		&user.TOTPSecret, &user.TOTPEnabledAt,
+		&user.Role, &user.DisabledAt, &user.PasswordResetRequired,
 	)
-
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, nil
 		}
 		return nil, err
 	}
-
 	return &user, nil
 }
 
+// FindByEmail & FindByUsername tidak membedakan huruf besar/kecil (memakai index lower(...))
+func (r *repository) FindByEmail(ctx context.Context, email string) (*User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE lower(email) = lower($1) AND deleted_at IS NULL`
+	return scanUser(r.db.QueryRow(ctx, query, email))
+}
+
+func (r *repository) FindByUsername(ctx context.Context, username string) (*User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE lower(username) = lower($1) AND deleted_at IS NULL`
+	return scanUser(r.db.QueryRow(ctx, query, username))
+}
+
 func (r *repository) FindByID(ctx context.Context, id string) (*User, error) {
-	query := `SELECT id, username, email, password, created_at, deleted_at FROM users WHERE id = $1`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND deleted_at IS NULL`
+	return scanUser(r.db.QueryRow(ctx, query, id))
+}
 
-	var user User
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&user.ID, &user.Username, &user.Email, &user.Password, &user.CreatedAt, &user.DeletedAt,
+func (r *repository) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE users SET email_verified_at = $1 WHERE id = $2 AND deleted_at IS NULL`, verifiedAt, id)
+	return err
+}
+
+func (r *repository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`, deletedAt, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+	return nil
+}
+
+// PurgeDeleted menghapus permanen akun yang di-soft delete sebelum deletedBefore.
+// Data budget, history, token, dll ikut terhapus lewat ON DELETE CASCADE.
+func (r *repository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < $1`, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// SetTOTPSecret menyimpan secret baru (enrollment belum aktif sampai EnableTOTP)
+func (r *repository) SetTOTPSecret(ctx context.Context, id string, secret string) error {
+	query := `UPDATE users SET totp_secret = $1, totp_enabled_at = NULL, totp_last_step = 0 WHERE id = $2 AND deleted_at IS NULL`
+	_, err := r.db.Exec(ctx, query, secret, id)
+	return err
+}
+
+// EnableTOTP mengaktifkan 2FA dan mengganti seluruh recovery code dalam satu transaksi
+func (r *repository) EnableTOTP(ctx context.Context, id string, enabledAt time.Time, recoveryCodeHashes []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET totp_enabled_at = $1 WHERE id = $2`, enabledAt, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = $1`, id); err != nil {
+		return err
+	}
+	for _, hash := range recoveryCodeHashes {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO totp_recovery_codes (id, user_id, code_hash, created_at) VALUES ($1, $2, $3, $4)`,
+			uuid.New().String(), id, hash, enabledAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *repository) DisableTOTP(ctx context.Context, id string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE users SET totp_secret = NULL, totp_enabled_at = NULL, totp_last_step = 0 WHERE id = $1`
+	if _, err := tx.Exec(ctx, query, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseTOTPStep mencatat langkah TOTP terakhir yang dipakai.
+// Return false jika langkah tersebut (atau yang lebih baru) sudah pernah dipakai => replay.
+func (r *repository) UseTOTPStep(ctx context.Context, id string, step int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1`, step, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// UseRecoveryCode menandai recovery code terpakai; false jika tidak ada / sudah dipakai
+func (r *repository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	query := `UPDATE totp_recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+	tag, err := r.db.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+var (
+	ErrRefreshTokenReused = apperror.Unauthorized("refresh_token_reused", "refresh token already used")
+)
+
+// TokenRepository: Penyimpanan refresh token (hash) per family
+type TokenRepository interface {
+	Save(ctx context.Context, token *RefreshToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	Rotate(ctx context.Context, oldID string, newToken *RefreshToken) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllByUserID(ctx context.Context, userID string) error
+
+	SaveActionToken(ctx context.Context, token *ActionToken) error
+	FindActionToken(ctx context.Context, purpose string, tokenHash string) (*ActionToken, error)
+	ConsumeActionToken(ctx context.Context, purpose string, tokenHash string) (*ActionToken, error)
+}
+
+type tokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTokenRepository(db *pgxpool.Pool) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) Save(ctx context.Context, token *RefreshToken) error {
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.Exec(ctx, query, token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+func (r *tokenRepository) FindByHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, created_at, revoked_at, replaced_by
+		FROM refresh_tokens WHERE token_hash = $1
+	`
+
+	var token RefreshToken
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash,
+		&token.ExpiresAt, &token.CreatedAt, &token.RevokedAt, &token.ReplacedBy,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -86,5 +355,215 @@ func (r *repository) FindByID(ctx context.Context, id string) (*User, error) {
 		}
 		return nil, err
 	}
-	return &user, nil
+	return &token, nil
+}
+
+// Rotate mencabut token lama dan menyimpan penggantinya dalam satu transaksi.
+// Jika token lama ternyata sudah dicabut (dipakai bersamaan), return ErrRefreshTokenReused.
+func (r *tokenRepository) Rotate(ctx context.Context, oldID string, newToken *RefreshToken) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx,
+		`UPDATE refresh_tokens SET revoked_at = $1, replaced_by = $2 WHERE id = $3 AND revoked_at IS NULL`,
+		newToken.CreatedAt, newToken.ID, oldID,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRefreshTokenReused
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, newToken.ID, newToken.UserID, newToken.FamilyID, newToken.TokenHash, newToken.ExpiresAt, newToken.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *tokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, familyID)
+	return err
+}
+
+func (r *tokenRepository) RevokeAllByUserID(ctx context.Context, userID string) error {
+	query := `UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL`
+	_, err := r.db.Exec(ctx, query, userID)
+	return err
+}
+
+// SaveActionToken menyimpan token baru dan membatalkan token lama (belum terpakai)
+// dengan tujuan yang sama, sehingga hanya link terakhir yang berlaku.
+func (r *tokenRepository) SaveActionToken(ctx context.Context, token *ActionToken) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`UPDATE action_tokens SET used_at = $1 WHERE user_id = $2 AND purpose = $3 AND used_at IS NULL`,
+		token.CreatedAt, token.UserID, token.Purpose,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO action_tokens (id, user_id, purpose, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindActionToken mencari token yang masih berlaku tanpa memakainya
+func (r *tokenRepository) FindActionToken(ctx context.Context, purpose string, tokenHash string) (*ActionToken, error) {
+	query := `
+		SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at FROM action_tokens
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+	`
+
+	var token ActionToken
+	err := r.db.QueryRow(ctx, query, tokenHash, purpose).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ConsumeActionToken menandai token sebagai terpakai secara atomik.
+// Return nil jika token tidak ada, sudah dipakai, atau kedaluwarsa.
+func (r *tokenRepository) ConsumeActionToken(ctx context.Context, purpose string, tokenHash string) (*ActionToken, error) {
+	query := `
+		UPDATE action_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING id, user_id, purpose, token_hash, expires_at, used_at, created_at
+	`
+
+	var token ActionToken
+	err := r.db.QueryRow(ctx, query, tokenHash, purpose).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &token.UsedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// AttemptRepository: Penghitung gagal login untuk throttling & lockout
+type AttemptRepository interface {
+	RegisterFailure(ctx context.Context, key string, at time.Time, windowStart time.Time) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	LockedUntil(ctx context.Context, keys []string, now time.Time) (*time.Time, error)
+	Reset(ctx context.Context, key string) error
+}
+
+type attemptRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttemptRepository(db *pgxpool.Pool) AttemptRepository {
+	return &attemptRepository{db: db}
+}
+
+// RegisterFailure menambah counter gagal dan mengembalikan jumlah terbaru.
+// Counter baru kedaluwarsa (mulai dari 1) jika gagal terakhir DAN akhir lockout terakhir sudah sebelum windowStart,
+// jadi lockout sepanjang window tidak me-reset eskalasi.
+func (r *attemptRepository) RegisterFailure(ctx context.Context, key string, at time.Time, windowStart time.Time) (int, error) {
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at) VALUES ($1, 1, $2)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN GREATEST(login_attempts.last_failure_at, login_attempts.locked_until) < $3 THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures
+	`
+	var failures int
+	if err := r.db.QueryRow(ctx, query, key, at, windowStart).Scan(&failures); err != nil {
+		return 0, err
+	}
+	return failures, nil
+}
+
+func (r *attemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE login_attempts SET locked_until = $1 WHERE key = $2`, until, key)
+	return err
+}
+
+// LockedUntil mengembalikan lockout paling lama yang masih aktif di antara keys (nil jika tidak ada)
+func (r *attemptRepository) LockedUntil(ctx context.Context, keys []string, now time.Time) (*time.Time, error) {
+	query := `SELECT MAX(locked_until) FROM login_attempts WHERE key = ANY($1) AND locked_until > $2`
+
+	var until *time.Time
+	if err := r.db.QueryRow(ctx, query, keys, now).Scan(&until); err != nil {
+		return nil, err
+	}
+	return until, nil
+}
+
+func (r *attemptRepository) Reset(ctx context.Context, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM login_attempts WHERE key = $1`, key)
+	return err
+}
+
+// AuditRepository: Jejak aksi admin (append-only). Audit log ditulis oleh Repository
+// dalam transaksi yang sama dengan perubahan user-nya, di sini hanya dibaca.
+type AuditRepository interface {
+	FindAll(ctx context.Context, targetUserID string, limit int, offset int) ([]AuditLog, error)
+}
+
+type auditRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAuditRepository(db *pgxpool.Pool) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// FindAll: targetUserID kosong berarti semua user, urut terbaru
+func (r *auditRepository) FindAll(ctx context.Context, targetUserID string, limit int, offset int) ([]AuditLog, error) {
+	query := `
+		SELECT id, actor_id, action, target_user_id, ip_address, created_at FROM audit_logs
+		WHERE ($1 = '' OR target_user_id::text = $1)
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+	rows, err := r.db.Query(ctx, query, targetUserID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := make([]AuditLog, 0)
+	for rows.Next() {
+		var log AuditLog
+		if err := rows.Scan(&log.ID, &log.ActorID, &log.Action, &log.TargetUserID, &log.IPAddress, &log.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+	return logs, rows.Err()
 }
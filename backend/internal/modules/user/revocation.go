@@ -0,0 +1,167 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RevocationStore: Daftar access token (jti) yang dicabut sebelum exp.
+// Dipakai oleh AuthMiddleware di setiap request, jadi hasil pengecekan di-cache di memori.
+type RevocationStore interface {
+	Revoke(ctx context.Context, jti string, userID string, expiresAt time.Time) error
+	RevokeAllForUser(ctx context.Context, userID string, before time.Time) error
+	IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (bool, error)
+
+	// PurgeExpired menghapus jti yang exp-nya sudah lewat (token itu sudah ditolak karena kedaluwarsa)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type revocationEntry struct {
+	revoked    bool
+	validUntil time.Time
+}
+
+type cutoffEntry struct {
+	before     time.Time // zero value = tidak ada cutoff
+	validUntil time.Time
+}
+
+type revocationStore struct {
+	db       *pgxpool.Pool
+	cacheTTL time.Duration
+
+	mu        sync.RWMutex
+	tokens    map[string]revocationEntry
+	cutoffs   map[string]cutoffEntry
+	lastSweep time.Time
+}
+
+// NewRevocationStore membuat store berbasis Postgres dengan cache memori.
+// Pencabutan dari proses ini langsung terlihat; pencabutan dari instance lain
+// terlihat paling lambat setelah cacheTTL (cache hasil "tidak dicabut").
+func NewRevocationStore(db *pgxpool.Pool, cacheTTL time.Duration) RevocationStore {
+	return &revocationStore{
+		db:        db,
+		cacheTTL:  cacheTTL,
+		tokens:    make(map[string]revocationEntry),
+		cutoffs:   make(map[string]cutoffEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *revocationStore) Revoke(ctx context.Context, jti string, userID string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (jti) DO NOTHING
+	`
+	if _, err := s.db.Exec(ctx, query, jti, userID, expiresAt); err != nil {
+		return err
+	}
+
+	// Token yang dicabut tidak akan pernah valid lagi, cache sampai exp
+	s.mu.Lock()
+	s.tokens[jti] = revocationEntry{revoked: true, validUntil: expiresAt}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *revocationStore) RevokeAllForUser(ctx context.Context, userID string, before time.Time) error {
+	query := `
+		INSERT INTO user_token_cutoffs (user_id, revoked_before) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET revoked_before = EXCLUDED.revoked_before
+	`
+	if _, err := s.db.Exec(ctx, query, userID, before); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.cutoffs[userID] = cutoffEntry{before: before, validUntil: time.Now().Add(s.cacheTTL)}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *revocationStore) IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (bool, error) {
+	now := time.Now()
+	s.sweep(now)
+
+	// 1. Coba jawab dari cache
+	s.mu.RLock()
+	token, tokenCached := s.tokens[jti]
+	cutoff, cutoffCached := s.cutoffs[userID]
+	s.mu.RUnlock()
+
+	tokenCached = tokenCached && now.Before(token.validUntil)
+	cutoffCached = cutoffCached && now.Before(cutoff.validUntil)
+	if tokenCached && token.revoked {
+		return true, nil
+	}
+	if tokenCached && cutoffCached {
+		return isBeforeCutoff(issuedAt, cutoff.before), nil
+	}
+
+	// 2. Cache miss: tanya Postgres dalam satu query
+	query := `
+		SELECT
+			EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1),
+			(SELECT revoked_before FROM user_token_cutoffs WHERE user_id = $2)
+	`
+	var revoked bool
+	var before *time.Time
+	if err := s.db.QueryRow(ctx, query, jti, userID).Scan(&revoked, &before); err != nil {
+		return false, err
+	}
+
+	entry := cutoffEntry{validUntil: now.Add(s.cacheTTL)}
+	if before != nil {
+		entry.before = *before
+	}
+
+	s.mu.Lock()
+	s.tokens[jti] = revocationEntry{revoked: revoked, validUntil: now.Add(s.cacheTTL)}
+	s.cutoffs[userID] = entry
+	s.mu.Unlock()
+
+	return revoked || isBeforeCutoff(issuedAt, entry.before), nil
+}
+
+func (s *revocationStore) PurgeExpired(ctx context.Context) (int64, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// sweep membuang entry cache yang sudah kedaluwarsa, maksimal sekali per menit
+func (s *revocationStore) sweep(now time.Time) {
+	s.mu.RLock()
+	due := now.Sub(s.lastSweep) >= time.Minute
+	s.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for jti, entry := range s.tokens {
+		if now.After(entry.validUntil) {
+			delete(s.tokens, jti)
+		}
+	}
+	for userID, entry := range s.cutoffs {
+		if now.After(entry.validUntil) {
+			delete(s.cutoffs, userID)
+		}
+	}
+	s.lastSweep = now
+}
+
+// isBeforeCutoff membandingkan per detik karena iat JWT hanya berpresisi detik:
+// token yang terbit di detik yang sama dengan cutoff ikut dicabut.
+func isBeforeCutoff(issuedAt time.Time, before time.Time) bool {
+	return !before.IsZero() && !issuedAt.Truncate(time.Second).After(before.Truncate(time.Second))
+}
@@ -0,0 +1,171 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrSessionNotFound = apperror.NotFound("session_not_found", "session not found")
+)
+
+// SessionStore: Satu sesi per login (device). ID sesi = family refresh token,
+// dan ikut di claim "sid" access token sehingga AuthMiddleware bisa menolak sesi yang diakhiri.
+type SessionStore interface {
+	Create(ctx context.Context, session *Session) error
+	FindAllActiveByUserID(ctx context.Context, userID string) ([]Session, error)
+	Terminate(ctx context.Context, id string, userID string) error
+	TerminateAllForUser(ctx context.Context, userID string) error
+	Touch(ctx context.Context, id string, userID string) (bool, error)
+}
+
+type sessionEntry struct {
+	userID     string
+	active     bool
+	validUntil time.Time
+}
+
+type sessionStore struct {
+	db       *pgxpool.Pool
+	cacheTTL time.Duration
+
+	mu        sync.RWMutex
+	sessions  map[string]sessionEntry
+	lastSweep time.Time
+}
+
+// NewSessionStore membuat store berbasis Postgres dengan cache memori.
+// last_seen_at diperbarui paling sering sekali per cacheTTL per sesi, dan sesi yang
+// diakhiri dari instance lain terlihat paling lambat setelah cacheTTL.
+func NewSessionStore(db *pgxpool.Pool, cacheTTL time.Duration) SessionStore {
+	return &sessionStore{
+		db:        db,
+		cacheTTL:  cacheTTL,
+		sessions:  make(map[string]sessionEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *sessionStore) Create(ctx context.Context, session *Session) error {
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip_address, created_at, last_seen_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := s.db.Exec(ctx, query,
+		session.ID, session.UserID, session.UserAgent, session.IPAddress, session.CreatedAt, session.LastSeenAt,
+	)
+	return err
+}
+
+func (s *sessionStore) FindAllActiveByUserID(ctx context.Context, userID string) ([]Session, error) {
+	query := `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at, terminated_at FROM sessions
+		WHERE user_id = $1 AND terminated_at IS NULL
+		ORDER BY last_seen_at DESC
+	`
+	rows, err := s.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := make([]Session, 0)
+	for rows.Next() {
+		var session Session
+		err := rows.Scan(
+			&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+			&session.CreatedAt, &session.LastSeenAt, &session.TerminatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (s *sessionStore) Terminate(ctx context.Context, id string, userID string) error {
+	query := `UPDATE sessions SET terminated_at = NOW() WHERE id = $1 AND user_id = $2 AND terminated_at IS NULL`
+
+	tag, err := s.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+
+	// Sesi yang diakhiri tidak akan pernah aktif lagi
+	s.mu.Lock()
+	s.sessions[id] = sessionEntry{userID: userID, active: false, validUntil: time.Now().Add(s.cacheTTL)}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *sessionStore) TerminateAllForUser(ctx context.Context, userID string) error {
+	query := `UPDATE sessions SET terminated_at = NOW() WHERE user_id = $1 AND terminated_at IS NULL`
+	if _, err := s.db.Exec(ctx, query, userID); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	for id, entry := range s.sessions {
+		if entry.userID == userID {
+			delete(s.sessions, id)
+		}
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// Touch return true jika sesi masih aktif, sekaligus memperbarui last_seen_at saat cache miss
+func (s *sessionStore) Touch(ctx context.Context, id string, userID string) (bool, error) {
+	now := time.Now()
+	s.sweep(now)
+
+	// 1. Coba jawab dari cache
+	s.mu.RLock()
+	entry, cached := s.sessions[id]
+	s.mu.RUnlock()
+	if cached && now.Before(entry.validUntil) && entry.userID == userID {
+		return entry.active, nil
+	}
+
+	// 2. Cache miss: update last_seen_at, sekaligus mengecek sesi masih aktif
+	query := `UPDATE sessions SET last_seen_at = $1 WHERE id = $2 AND user_id = $3 AND terminated_at IS NULL`
+	tag, err := s.db.Exec(ctx, query, now, id, userID)
+	if err != nil {
+		return false, err
+	}
+	active := tag.RowsAffected() == 1
+
+	s.mu.Lock()
+	s.sessions[id] = sessionEntry{userID: userID, active: active, validUntil: now.Add(s.cacheTTL)}
+	s.mu.Unlock()
+
+	return active, nil
+}
+
+// sweep membuang entry cache yang sudah kedaluwarsa, maksimal sekali per menit
+func (s *sessionStore) sweep(now time.Time) {
+	s.mu.RLock()
+	due := now.Sub(s.lastSweep) >= time.Minute
+	s.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, entry := range s.sessions {
+		if now.After(entry.validUntil) {
+			delete(s.sessions, id)
+		}
+	}
+	s.lastSweep = now
+}
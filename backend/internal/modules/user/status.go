@@ -0,0 +1,96 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StatusStore: Apakah akun masih boleh dipakai (ada, belum dihapus, tidak dinonaktifkan, tidak wajib reset password).
+// Dipakai oleh AuthMiddleware di setiap request, jadi hasil pengecekan di-cache di memori.
+type StatusStore interface {
+	IsActive(ctx context.Context, userID string) (bool, error)
+}
+
+type statusEntry struct {
+	active     bool
+	validUntil time.Time
+}
+
+type statusStore struct {
+	db       *pgxpool.Pool
+	cacheTTL time.Duration
+
+	mu        sync.RWMutex
+	users     map[string]statusEntry
+	lastSweep time.Time
+}
+
+// NewStatusStore membuat store berbasis Postgres dengan cache memori.
+// Akun yang dihapus / dinonaktifkan / wajib reset password terlihat paling lambat setelah cacheTTL
+// (token & sesinya sendiri sudah dicabut saat itu juga oleh usecase).
+func NewStatusStore(db *pgxpool.Pool, cacheTTL time.Duration) StatusStore {
+	return &statusStore{
+		db:        db,
+		cacheTTL:  cacheTTL,
+		users:     make(map[string]statusEntry),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *statusStore) IsActive(ctx context.Context, userID string) (bool, error) {
+	// sub bukan UUID tidak mungkin milik user mana pun
+	if _, err := uuid.Parse(userID); err != nil {
+		return false, nil
+	}
+
+	now := time.Now()
+	s.sweep(now)
+
+	// 1. Coba jawab dari cache
+	s.mu.RLock()
+	entry, cached := s.users[userID]
+	s.mu.RUnlock()
+	if cached && now.Before(entry.validUntil) {
+		return entry.active, nil
+	}
+
+	// 2. Cache miss: user yang sudah di-purge tidak punya baris sama sekali
+	query := `SELECT deleted_at IS NULL AND disabled_at IS NULL AND NOT password_reset_required FROM users WHERE id = $1`
+	var active bool
+	err := s.db.QueryRow(ctx, query, userID).Scan(&active)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return false, err
+	}
+
+	s.mu.Lock()
+	s.users[userID] = statusEntry{active: active, validUntil: now.Add(s.cacheTTL)}
+	s.mu.Unlock()
+
+	return active, nil
+}
+
+// sweep membuang entry cache yang sudah kedaluwarsa, maksimal sekali per menit
+func (s *statusStore) sweep(now time.Time) {
+	s.mu.RLock()
+	due := now.Sub(s.lastSweep) >= time.Minute
+	s.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for userID, entry := range s.users {
+		if now.After(entry.validUntil) {
+			delete(s.users, userID)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vector RFC 6238 (SHA1, secret "12345678901234567890"), dipotong ke 6 digit
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	secret := []byte("12345678901234567890")
+
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range cases {
+		assert.Equal(t, expected, totpCode(secret, uint64(unix/totpPeriod)), "time %d", unix)
+	}
+}
+
+func TestVerifyTOTP_AllowsSkew(t *testing.T) {
+	secret, err := generateTOTPSecret()
+	assert.NoError(t, err)
+
+	key, _ := base32NoPadding.DecodeString(secret)
+	now := time.Unix(1_700_000_000, 0)
+	previous := totpCode(key, uint64(now.Unix()/totpPeriod-1))
+
+	step, ok := verifyTOTP(secret, previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Unix()/totpPeriod-1, step)
+
+	tooOld := totpCode(key, uint64(now.Unix()/totpPeriod-3))
+	_, ok = verifyTOTP(secret, tooOld, now)
+	assert.False(t, ok)
+}
+
+func TestTOTPURI_Format(t *testing.T) {
+	uri := totpURI("finance-tracker-app", "me@example.com", "JBSWY3DPEHPK3PXP")
+
+	assert.Contains(t, uri, "otpauth://totp/finance-tracker-app:me@example.com?")
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=finance-tracker-app")
+}
+
+func TestGenerateRecoveryCodes_Unique(t *testing.T) {
+	codes, err := generateRecoveryCodes(10)
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, code)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+}
@@ -2,41 +2,160 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/apperror"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/mailer"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/password"
 	"github.com/go-playground/validator/v10"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
-	"golang.org/x/crypto/bcrypt"
 )
 
 var (
-	ErrInternalServer     = errors.New("internal server error")
-	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrInternalServer     = apperror.ErrInternal
+	ErrInvalidCredentials = apperror.Unauthorized("invalid_credentials", "invalid email or password")
+	ErrUserNotFound       = apperror.NotFound("user_not_found", "user not found")
+	ErrPasswordRequired   = apperror.BadRequest("password_required", "current password is required to change email")
+	ErrInvalidPassword    = apperror.Forbidden("invalid_password", "current password is incorrect")
+
+	ErrInvalidRefreshToken = apperror.Unauthorized("invalid_refresh_token", "invalid or expired refresh token")
+	ErrInvalidResetToken   = apperror.BadRequest("invalid_reset_token", "invalid or expired reset token")
+
+	ErrEmailNotVerified   = apperror.Forbidden("email_not_verified", "email address has not been verified")
+	ErrInvalidVerifyToken = apperror.BadRequest("invalid_verify_token", "invalid or expired verification token")
+
+	ErrTOTPAlreadyEnabled = apperror.Conflict("totp_already_enabled", "two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled    = apperror.BadRequest("totp_not_enrolled", "two-factor authentication has not been set up")
+	ErrTOTPNotEnabled     = apperror.BadRequest("totp_not_enabled", "two-factor authentication is not enabled")
+	ErrInvalidMFACode     = apperror.Unauthorized("invalid_mfa_code", "invalid authentication code")
+	ErrInvalidMFAToken    = apperror.Unauthorized("invalid_mfa_token", "invalid or expired mfa token")
+
+	ErrAccountDisabled       = apperror.Forbidden("account_disabled", "account has been disabled")
+	ErrPasswordResetRequired = apperror.Forbidden("password_reset_required", "password reset required, check your email")
 )
 
+// recoveryCodeCount: jumlah recovery code yang dibuat saat 2FA diaktifkan
+const recoveryCodeCount = 10
+
+// maxUserAgentLength: sesuai panjang kolom sessions.user_agent
+const maxUserAgentLength = 512
+
+// TooManyAttemptsError: Login ditolak sementara karena terlalu banyak gagal
+type TooManyAttemptsError struct {
+	RetryAfter time.Duration
+}
+
+func (e *TooManyAttemptsError) Error() string {
+	return "too many failed login attempts, try again later"
+}
+
+// AppError: 429 dengan header Retry-After
+func (e *TooManyAttemptsError) AppError() *apperror.Error {
+	return apperror.TooManyRequests("too_many_attempts", e.Error(), e.RetryAfter)
+}
+
+// TokenSigner: Penandatangan access token (key aktif dari jwtkey.KeySet)
+type TokenSigner interface {
+	Sign(claims jwt.Claims) (string, error)
+}
+
+// PasswordHasher: Hash password (argon2id / bcrypt dari password.Hasher)
+type PasswordHasher interface {
+	Hash(plain string) (string, error)
+	Verify(hash string, plain string) (bool, error)
+	NeedsRehash(hash string) bool
+}
+
+// PasswordPolicy: Aturan password baru, return *password.PolicyError jika ditolak
+type PasswordPolicy interface {
+	Check(plain string, personal ...string) error
+}
+
+// CategorySeeder: Membuat kategori pengeluaran default untuk user baru (module category)
+type CategorySeeder interface {
+	SeedDefaults(ctx context.Context, userID string) error
+}
+
+// APITokenRevoker: Mencabut semua personal access token milik user (module apitoken)
+type APITokenRevoker interface {
+	RevokeAllByUserID(ctx context.Context, userID string) error
+}
+
 type UseCase interface {
 	Register(ctx context.Context, req *RegisterRequest) (*RegisterResponse, error)
 	Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error)
+	Refresh(ctx context.Context, req *RefreshRequest) (*LoginResponse, error)
+	Logout(ctx context.Context, claims *AccessTokenClaims, req *LogoutRequest) error
+	LogoutAll(ctx context.Context, userID string) error
 	GetMe(ctx context.Context, userID string) (*UserResponse, error)
+	UpdateProfile(ctx context.Context, userID string, req *UpdateProfileRequest) (*UserResponse, error)
+	ChangePassword(ctx context.Context, userID string, req *ChangePasswordRequest) error
+	ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *ResetPasswordRequest) error
+	VerifyEmail(ctx context.Context, req *VerifyEmailRequest) (*UserResponse, error)
+	ResendVerification(ctx context.Context, req *ResendVerificationRequest) error
+	DeleteAccount(ctx context.Context, userID string, req *DeleteAccountRequest) error
+	PurgeDeletedAccounts(ctx context.Context) error
+	EnrollTOTP(ctx context.Context, userID string) (*TOTPEnrollmentResponse, error)
+	ConfirmTOTP(ctx context.Context, userID string, req *ConfirmTOTPRequest) (*RecoveryCodesResponse, error)
+	DisableTOTP(ctx context.Context, userID string, req *DisableTOTPRequest) error
+	LoginMFA(ctx context.Context, req *LoginMFARequest) (*LoginResponse, error)
+	ListSessions(ctx context.Context, userID string, currentSessionID string) ([]SessionResponse, error)
+	TerminateSession(ctx context.Context, userID string, sessionID string) error
+}
+
+// accountSecurity: Dependency untuk memutus semua sesi & mengirim link reset password,
+// dipakai bersama oleh UseCase dan AdminUseCase
+type accountSecurity struct {
+	tokenRepo   TokenRepository
+	revocations RevocationStore
+	sessions    SessionStore
+	apiTokens   APITokenRevoker
+	mailer      mailer.Mailer
+	log         *logrus.Logger
+	cfg         *viper.Viper
 }
 
 type useCase struct {
-	repo     Repository
-	log      *logrus.Logger
-	validate *validator.Validate
-	cfg      *viper.Viper
+	accountSecurity
+	repo       Repository
+	attempts   AttemptRepository
+	categories CategorySeeder
+	signer     TokenSigner
+	passwords  PasswordHasher
+	policy     PasswordPolicy
+	validate   *validator.Validate
 }
 
-func NewUseCase(repo Repository, log *logrus.Logger, validate *validator.Validate, cfg *viper.Viper) UseCase {
+func NewUseCase(repo Repository, tokenRepo TokenRepository, revocations RevocationStore, sessions SessionStore, apiTokens APITokenRevoker, mail mailer.Mailer, attempts AttemptRepository, categories CategorySeeder, signer TokenSigner, passwords PasswordHasher, policy PasswordPolicy, log *logrus.Logger, validate *validator.Validate, cfg *viper.Viper) UseCase {
 	return &useCase{
-		repo:     repo,
-		log:      log,
-		validate: validate,
-		cfg:      cfg,
+		accountSecurity: accountSecurity{
+			tokenRepo:   tokenRepo,
+			revocations: revocations,
+			sessions:    sessions,
+			apiTokens:   apiTokens,
+			mailer:      mail,
+			log:         log,
+			cfg:         cfg,
+		},
+		repo:       repo,
+		attempts:   attempts,
+		categories: categories,
+		signer:     signer,
+		passwords:  passwords,
+		policy:     policy,
+		validate:   validate,
 	}
 }
 
@@ -47,8 +166,11 @@ func (u *useCase) Register(ctx context.Context, req *RegisterRequest) (*Register
 		return nil, err
 	}
 
-	// 2. Hash Password
-	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	// 2. Cek Password Policy & Hash Password
+	if err := u.checkPasswordPolicy("password", req.Password, req.Username, req.Email); err != nil {
+		return nil, err
+	}
+	hashed, err := u.passwords.Hash(req.Password)
 	if err != nil {
 		u.log.Error("Failed to hash password:", err)
 		return nil, ErrInternalServer
@@ -59,9 +181,10 @@ func (u *useCase) Register(ctx context.Context, req *RegisterRequest) (*Register
 		ID:        uuid.New().String(),
 		Username:  req.Username,
 		Email:     req.Email,
-		Password:  string(hashed),
+		Password:  hashed,
 		CreatedAt: time.Now(),
 		DeletedAt: nil,
+		Role:      RoleUser,
 	}
 
 	// 4. Simpan ke DB
@@ -75,83 +198,1092 @@ func (u *useCase) Register(ctx context.Context, req *RegisterRequest) (*Register
 		return nil, ErrInternalServer
 	}
 
-	// 5. Return Response
+	// 5. Buat kategori default (gagal tidak membatalkan registrasi, dibuat ulang saat kategori pertama kali dibuka)
+	if err := u.categories.SeedDefaults(ctx, newUser.ID); err != nil {
+		u.log.WithError(err).Warn("Register: failed to seed default categories")
+	}
+
+	// 6. Kirim email verifikasi (gagal kirim tidak membatalkan registrasi, user bisa minta kirim ulang)
+	if err := u.sendVerificationEmail(ctx, newUser); err != nil {
+		u.log.WithError(err).Warn("Register: failed to send verification email")
+	}
+
+	// 7. Return Response
 	return &RegisterResponse{
-		UserResponse: UserResponse{
-			ID:        newUser.ID,
-			Username:  newUser.Username,
-			Email:     newUser.Email,
-			CreatedAt: newUser.CreatedAt,
-		},
+		UserResponse: toUserResponse(newUser),
 	}, nil
 }
 
 // Login Usecase
 func (u *useCase) Login(ctx context.Context, req *LoginRequest) (*LoginResponse, error) {
-	// 1. Cari User by Email
-	user, err := u.repo.FindByEmail(ctx, req.Email)
+	// 1. Cari User by Email / Username
+	identifier := req.loginIdentifier()
+	user, err := u.findByIdentifier(ctx, identifier)
 	if err != nil {
 		u.log.WithError(err).Error("Login Failed : Error Finding User")
 		return nil, ErrInternalServer
 	}
 
-	// 2. Verifikasi Password
-	// Jika user nil, return InvalidCredentials
-	if user == nil {
+	// 2. Tolak jika akun / IP sedang terkunci karena terlalu banyak gagal login
+	keys := loginAttemptKeys(req, user)
+	if err := u.checkLockout(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	// 3. Verifikasi Password
+	// Hash tetap dihitung walau user tidak ada (hash kosong), supaya waktu respons sama
+	// dan keberadaan akun tidak bisa ditebak dari lamanya respons.
+	hash := ""
+	if user != nil {
+		hash = user.Password
+	}
+	if !u.checkPassword(hash, req.Password) || user == nil {
+		u.log.Warnf("Login failed: invalid credentials for %s", identifier)
+		u.registerLoginFailure(ctx, keys)
 		return nil, ErrInvalidCredentials
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
+	// 4. Login sukses: counter gagal untuk akun ini di-reset
+	if err := u.attempts.Reset(ctx, keys[0]); err != nil {
+		u.log.WithError(err).Warn("Login: failed to reset attempt counter")
+	}
+
+	// 5. Tolak akun yang dinonaktifkan / wajib reset password oleh admin
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+
+	// 6. Upgrade hash lama (bcrypt / parameter lemah) selagi password plaintext tersedia
+	u.upgradePasswordHash(ctx, user, req.Password)
+
+	// 7. Tolak akun yang belum verifikasi email (jika diaktifkan)
+	if u.cfg.GetBool("auth.require_verified_email") && user.EmailVerifiedAt == nil {
+		return nil, ErrEmailNotVerified
+	}
+
+	// 8. 2FA aktif: token baru diberikan setelah langkah kedua (LoginMFA)
+	if user.TOTPEnabledAt != nil {
+		return u.issueMFAChallenge(ctx, user)
+	}
+
+	// 9. Buat sesi baru + Access Token + Refresh Token
+	return u.startSession(ctx, user, req.IPAddress, req.UserAgent)
+}
+
+// checkPassword: hash yang tidak dikenali dicatat di log dan dianggap salah
+func (u *useCase) checkPassword(hash string, plain string) bool {
+	ok, err := u.passwords.Verify(hash, plain)
 	if err != nil {
-		u.log.Warnf("Login failed: invalid password for email %s", req.Email)
-		return nil, ErrInvalidCredentials
+		u.log.WithError(err).Error("Failed to verify password hash")
 	}
+	return ok
+}
 
-	// 3. Generate JWT Token
-	tokenTTL := u.cfg.GetDuration("jwt.ttl")
-	if tokenTTL == 0 {
-		tokenTTL = 24 * time.Hour // Default value
+// checkPasswordPolicy: field = nama field JSON asal password, untuk pesan error per field
+func (u *useCase) checkPasswordPolicy(field string, plain string, personal ...string) error {
+	err := u.policy.Check(plain, personal...)
+	var policyErr *password.PolicyError
+	if errors.As(err, &policyErr) {
+		policyErr.Field = field
 	}
+	return err
+}
+
+// upgradePasswordHash: gagal upgrade tidak menggagalkan login, dicoba lagi di login berikutnya
+func (u *useCase) upgradePasswordHash(ctx context.Context, user *User, plain string) {
+	if !u.passwords.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashed, err := u.passwords.Hash(plain)
+	if err != nil {
+		u.log.WithError(err).Warn("Login: failed to rehash password")
+		return
+	}
+	if err := u.repo.UpdatePasswordHash(ctx, user.ID, user.Password, hashed); err != nil {
+		u.log.WithError(err).Warn("Login: failed to save rehashed password")
+		return
+	}
+	user.Password = hashed
+}
+
+// issueMFAChallenge membuat token challenge berumur pendek sebagai bukti password sudah benar
+func (u *useCase) issueMFAChallenge(ctx context.Context, user *User) (*LoginResponse, error) {
+	challengeTTL := u.durationOrDefault("auth.mfa_challenge_ttl", 5*time.Minute)
+
+	plain, err := u.issueActionToken(ctx, user.ID, PurposeMFAChallenge, challengeTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResponse{MFARequired: true, MFAToken: plain}, nil
+}
+
+// LoginMFA Usecase: langkah kedua login dengan kode TOTP atau recovery code
+func (u *useCase) LoginMFA(ctx context.Context, req *LoginMFARequest) (*LoginResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Cek challenge (belum dipakai, supaya salah ketik kode tidak menghanguskan challenge)
+	challengeHash := hashToken(req.MFAToken)
+	challenge, err := u.tokenRepo.FindActionToken(ctx, PurposeMFAChallenge, challengeHash)
+	if err != nil {
+		u.log.WithError(err).Error("LoginMFA: failed to find challenge")
+		return nil, ErrInternalServer
+	}
+	if challenge == nil {
+		return nil, ErrInvalidMFAToken
+	}
+
+	// 3. Tebakan kode dibatasi per user, memakai mekanisme lockout login
+	keys := []string{"mfa:" + challenge.UserID}
+	if err := u.checkLockout(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	user, err := u.repo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		u.log.WithError(err).Error("LoginMFA: failed to find user")
+		return nil, ErrInternalServer
+	}
+	if user == nil || user.TOTPEnabledAt == nil {
+		return nil, ErrInvalidMFAToken
+	}
+	if err := accountStatusError(user); err != nil {
+		return nil, err
+	}
+
+	// 4. Verifikasi kode
+	ok, err := u.verifyMFACode(ctx, user, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		u.log.Warnf("LoginMFA failed: invalid code for user %s", user.ID)
+		u.registerLoginFailure(ctx, keys)
+		return nil, ErrInvalidMFACode
+	}
+
+	// 5. Pakai challenge (atomik, mencegah dua request memakai challenge yang sama)
+	consumed, err := u.tokenRepo.ConsumeActionToken(ctx, PurposeMFAChallenge, challengeHash)
+	if err != nil {
+		u.log.WithError(err).Error("LoginMFA: failed to consume challenge")
+		return nil, ErrInternalServer
+	}
+	if consumed == nil {
+		return nil, ErrInvalidMFAToken
+	}
+
+	if err := u.attempts.Reset(ctx, keys[0]); err != nil {
+		u.log.WithError(err).Warn("LoginMFA: failed to reset attempt counter")
+	}
+
+	// 6. Buat sesi baru + Access Token + Refresh Token
+	return u.startSession(ctx, user, req.IPAddress, req.UserAgent)
+}
+
+// EnrollTOTP Usecase: buat secret baru, 2FA belum aktif sampai dikonfirmasi
+func (u *useCase) EnrollTOTP(ctx context.Context, userID string) (*TOTPEnrollmentResponse, error) {
+	// 1. Cari User
+	user, err := u.findUser(ctx, userID, "EnrollTOTP")
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+
+	// 2. Generate & simpan secret (menimpa enrollment lama yang belum dikonfirmasi)
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		u.log.WithError(err).Error("EnrollTOTP: failed to generate secret")
+		return nil, ErrInternalServer
+	}
+	if err := u.repo.SetTOTPSecret(ctx, user.ID, secret); err != nil {
+		u.log.WithError(err).Error("EnrollTOTP: failed to save secret")
+		return nil, ErrInternalServer
+	}
+
+	issuer := u.cfg.GetString("app.name")
+	if issuer == "" {
+		issuer = "Finance Tracker" // Default value
+	}
+
+	return &TOTPEnrollmentResponse{
+		Secret: secret,
+		URI:    totpURI(issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP Usecase: aktifkan 2FA jika kode dari authenticator cocok
+func (u *useCase) ConfirmTOTP(ctx context.Context, userID string, req *ConfirmTOTPRequest) (*RecoveryCodesResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Cari User
+	user, err := u.findUser(ctx, userID, "ConfirmTOTP")
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabledAt != nil {
+		return nil, ErrTOTPAlreadyEnabled
+	}
+	if user.TOTPSecret == nil {
+		return nil, ErrTOTPNotEnrolled
+	}
+
+	// 3. Verifikasi kode
+	step, ok := verifyTOTP(*user.TOTPSecret, req.Code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	// 4. Generate recovery code, yang disimpan hanya hash-nya
+	codes, err := generateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		u.log.WithError(err).Error("ConfirmTOTP: failed to generate recovery codes")
+		return nil, ErrInternalServer
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = hashToken(normalizeRecoveryCode(code))
+	}
+
+	if err := u.repo.EnableTOTP(ctx, user.ID, time.Now(), hashes); err != nil {
+		u.log.WithError(err).Error("ConfirmTOTP: failed to enable totp")
+		return nil, ErrInternalServer
+	}
+
+	// 5. Kode konfirmasi tidak boleh dipakai lagi untuk login
+	if _, err := u.repo.UseTOTPStep(ctx, user.ID, step); err != nil {
+		u.log.WithError(err).Warn("ConfirmTOTP: failed to record totp step")
+	}
+
+	return &RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableTOTP Usecase: wajib password dan kode 2FA
+func (u *useCase) DisableTOTP(ctx context.Context, userID string, req *DisableTOTPRequest) error {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return err
+	}
+
+	// 2. Cari User
+	user, err := u.findUser(ctx, userID, "DisableTOTP")
+	if err != nil {
+		return err
+	}
+	if user.TOTPEnabledAt == nil {
+		return ErrTOTPNotEnabled
+	}
+
+	// 3. Verifikasi password & kode
+	if !u.checkPassword(user.Password, req.Password) {
+		return ErrInvalidPassword
+	}
+	ok, err := u.verifyMFACode(ctx, user, req.Code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	// 4. Hapus secret & recovery code
+	if err := u.repo.DisableTOTP(ctx, user.ID); err != nil {
+		u.log.WithError(err).Error("DisableTOTP: failed to disable totp")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// verifyMFACode menerima kode TOTP (6 digit) atau recovery code.
+// Kode TOTP yang sudah pernah dipakai ditolak (replay).
+func (u *useCase) verifyMFACode(ctx context.Context, user *User, code string) (bool, error) {
+	if user.TOTPSecret != nil {
+		if step, ok := verifyTOTP(*user.TOTPSecret, code, time.Now()); ok {
+			fresh, err := u.repo.UseTOTPStep(ctx, user.ID, step)
+			if err != nil {
+				u.log.WithError(err).Error("Failed to record totp step")
+				return false, ErrInternalServer
+			}
+			return fresh, nil
+		}
+	}
+
+	used, err := u.repo.UseRecoveryCode(ctx, user.ID, hashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		u.log.WithError(err).Error("Failed to use recovery code")
+		return false, ErrInternalServer
+	}
+	return used, nil
+}
+
+// findUser: helper cari user aktif, ErrUserNotFound jika tidak ada
+func (u *useCase) findUser(ctx context.Context, userID string, op string) (*User, error) {
+	user, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Errorf("%s: failed to find user", op)
+		return nil, ErrInternalServer
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// accountStatusError: status akun yang diatur admin dan menghalangi login
+func accountStatusError(user *User) error {
+	if user.DisabledAt != nil {
+		return ErrAccountDisabled
+	}
+	if user.PasswordResetRequired {
+		return ErrPasswordResetRequired
+	}
+	return nil
+}
+
+// loginIdentifier: Identifier diutamakan, fallback ke field email lama
+func (r *LoginRequest) loginIdentifier() string {
+	if identifier := strings.TrimSpace(r.Identifier); identifier != "" {
+		return identifier
+	}
+	return strings.TrimSpace(r.Email)
+}
+
+// findByIdentifier: username hanya boleh alfanumerik, jadi identifier yang mengandung "@" pasti email
+func (u *useCase) findByIdentifier(ctx context.Context, identifier string) (*User, error) {
+	if identifier == "" {
+		return nil, nil
+	}
+	if isEmailIdentifier(identifier) {
+		return u.repo.FindByEmail(ctx, identifier)
+	}
+	return u.repo.FindByUsername(ctx, identifier)
+}
+
+func isEmailIdentifier(identifier string) bool {
+	return strings.Contains(identifier, "@")
+}
+
+// loginAttemptKeys: key pertama selalu akun, diikuti IP client jika ada.
+// Akun yang ditemukan di-key dengan user ID supaya login bergantian lewat email & username
+// berbagi satu counter; identifier (lower-case) hanya dipakai untuk akun yang tidak dikenal.
+func loginAttemptKeys(req *LoginRequest, user *User) []string {
+	var key string
+	switch identifier := strings.ToLower(req.loginIdentifier()); {
+	case user != nil:
+		key = "user:" + user.ID
+	case isEmailIdentifier(identifier):
+		key = "email:" + identifier
+	default:
+		key = "username:" + identifier
+	}
+
+	keys := []string{key}
+	if req.IPAddress != "" {
+		keys = append(keys, "ip:"+req.IPAddress)
+	}
+	return keys
+}
+
+// checkLockout return *TooManyAttemptsError jika salah satu key masih terkunci
+func (u *useCase) checkLockout(ctx context.Context, keys []string) error {
+	now := time.Now()
+	until, err := u.attempts.LockedUntil(ctx, keys, now)
+	if err != nil {
+		u.log.WithError(err).Error("Login Failed : Error Checking Lockout")
+		return ErrInternalServer
+	}
+	if until != nil {
+		return &TooManyAttemptsError{RetryAfter: until.Sub(now)}
+	}
+	return nil
+}
+
+// registerLoginFailure menambah counter gagal dan mengunci key dengan backoff eksponensial:
+// gagal ke-N dikunci base_delay, ke-(N+1) 2x base_delay, dst. maksimal max_delay.
+// Counter bertahan sampai satu window setelah lockout terakhir berakhir (lihat AttemptRepository.RegisterFailure).
+func (u *useCase) registerLoginFailure(ctx context.Context, keys []string) {
+	window := u.durationOrDefault("auth.lockout.window", 15*time.Minute)
+	baseDelay := u.durationOrDefault("auth.lockout.base_delay", 30*time.Second)
+	maxDelay := u.durationOrDefault("auth.lockout.max_delay", 15*time.Minute)
 
-	// Setup Claims
 	now := time.Now()
-	claims := jwt.MapClaims{
-		"sub":   user.ID,
-		"exp":   now.Add(tokenTTL).Unix(),
-		"iat":   now.Unix(),
-		"name":  user.Username,
-		"email": user.Email,
+	for _, key := range keys {
+		maxAttempts := u.intOrDefault("auth.lockout.max_attempts", 5)
+		if strings.HasPrefix(key, "ip:") {
+			// Satu IP bisa dipakai banyak user (NAT kantor/kampus), jadi lebih longgar
+			maxAttempts = u.intOrDefault("auth.lockout.ip_max_attempts", 20)
+		}
+
+		failures, err := u.attempts.RegisterFailure(ctx, key, now, now.Add(-window))
+		if err != nil {
+			u.log.WithError(err).Error("Login: failed to register attempt")
+			continue
+		}
+		if failures < maxAttempts {
+			continue
+		}
+
+		// Clamp sebelum shift: baseDelay<<shift bisa overflow menjadi negatif
+		delay := maxDelay
+		if shift := failures - maxAttempts; shift < 63 && baseDelay <= maxDelay>>shift {
+			delay = baseDelay << shift
+		}
+		if err := u.attempts.Lock(ctx, key, now.Add(delay)); err != nil {
+			u.log.WithError(err).Error("Login: failed to lock key")
+		}
+		u.log.Warnf("Login locked for %s after %d failures (%s)", key, failures, delay)
+	}
+}
+
+func (u *useCase) durationOrDefault(key string, def time.Duration) time.Duration {
+	if d := u.cfg.GetDuration(key); d > 0 {
+		return d
+	}
+	return def
+}
+
+func (u *useCase) intOrDefault(key string, def int) int {
+	if n := u.cfg.GetInt(key); n > 0 {
+		return n
 	}
+	return def
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// Refresh Usecase: rotasi refresh token dengan deteksi pemakaian ulang
+func (u *useCase) Refresh(ctx context.Context, req *RefreshRequest) (*LoginResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
 
-	// Ambil Secret Key
-	jwtSecret := u.cfg.GetString("jwt.secret")
-	if jwtSecret == "" {
-		u.log.Error("JWT Secret Is not Configured")
+	// 2. Cari token berdasarkan hash
+	stored, err := u.tokenRepo.FindByHash(ctx, hashToken(req.RefreshToken))
+	if err != nil {
+		u.log.WithError(err).Error("Refresh failed: error finding token")
 		return nil, ErrInternalServer
 	}
+	if stored == nil {
+		return nil, ErrInvalidRefreshToken
+	}
 
-	// Sign Token
-	signedToken, err := token.SignedString([]byte(jwtSecret))
+	// 3. Token yang sudah dirotasi dipakai lagi => kemungkinan dicuri, cabut seluruh family
+	if stored.RevokedAt != nil {
+		return nil, u.revokeReusedFamily(ctx, stored)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// 4. Pastikan user masih aktif (sama dengan StatusStore: belum dihapus, tidak dinonaktifkan, tidak wajib reset password)
+	user, err := u.repo.FindByID(ctx, stored.UserID)
 	if err != nil {
-		u.log.WithError(err).Error("Failed To Sign Token")
+		u.log.WithError(err).Error("Refresh failed: error finding user")
 		return nil, ErrInternalServer
 	}
+	if user == nil || accountStatusError(user) != nil {
+		return nil, ErrInvalidRefreshToken
+	}
 
-	// 4. Return Response
-	return &LoginResponse{
-		AccessToken: signedToken,
-		TokenType:   "Bearer",
-		User: UserResponse{
-			ID:        user.ID,
-			Username:  user.Username,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt,
+	// 5. Terbitkan pasangan token baru dalam family (sesi) yang sama
+	accessToken, expiresIn, err := u.signAccessToken(user, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, newToken, err := u.newRefreshToken(user.ID, stored.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.tokenRepo.Rotate(ctx, stored.ID, newToken); err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			return nil, u.revokeReusedFamily(ctx, stored)
+		}
+		u.log.WithError(err).Error("Refresh failed: error rotating token")
+		return nil, ErrInternalServer
+	}
+
+	return newLoginResponse(user, accessToken, plain, expiresIn), nil
+}
+
+// Logout Usecase: cabut access token yang sedang dipakai (dan refresh token jika dikirim)
+func (u *useCase) Logout(ctx context.Context, claims *AccessTokenClaims, req *LogoutRequest) error {
+	// 1. Cabut jti access token saat ini
+	if err := u.revocations.Revoke(ctx, claims.JTI, claims.UserID, claims.ExpiresAt); err != nil {
+		u.log.WithError(err).Error("Logout failed: error revoking access token")
+		return ErrInternalServer
+	}
+
+	// 2. Akhiri sesi saat ini (refresh token-nya ikut dicabut)
+	if err := u.endSession(ctx, claims.UserID, claims.SessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+
+	// 3. Cabut family refresh token yang dikirim, hanya jika memang milik user ini
+	if req.RefreshToken == "" {
+		return nil
+	}
+	stored, err := u.tokenRepo.FindByHash(ctx, hashToken(req.RefreshToken))
+	if err != nil {
+		u.log.WithError(err).Error("Logout failed: error finding refresh token")
+		return ErrInternalServer
+	}
+	if stored == nil || stored.UserID != claims.UserID {
+		return nil
+	}
+	if err := u.tokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		u.log.WithError(err).Error("Logout failed: error revoking refresh token")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// ListSessions Usecase: daftar device yang sedang login, sesi saat ini ditandai current
+func (u *useCase) ListSessions(ctx context.Context, userID string, currentSessionID string) ([]SessionResponse, error) {
+	sessions, err := u.sessions.FindAllActiveByUserID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to list sessions")
+		return nil, ErrInternalServer
+	}
+
+	resp := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, SessionResponse{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == currentSessionID,
+		})
+	}
+	return resp, nil
+}
+
+// TerminateSession Usecase: logout dari satu device tertentu
+func (u *useCase) TerminateSession(ctx context.Context, userID string, sessionID string) error {
+	// ID bukan UUID pasti tidak ada (hindari error cast dari Postgres)
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return ErrSessionNotFound
+	}
+	return u.endSession(ctx, userID, sessionID)
+}
+
+// endSession menandai sesi berakhir (ditolak middleware) dan mencabut refresh token-nya
+func (u *useCase) endSession(ctx context.Context, userID string, sessionID string) error {
+	if err := u.sessions.Terminate(ctx, sessionID, userID); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+		u.log.WithError(err).Error("Failed to terminate session")
+		return ErrInternalServer
+	}
+
+	// Terminate sudah memastikan sesi milik userID, jadi family aman dicabut
+	if err := u.tokenRepo.RevokeFamily(ctx, sessionID); err != nil {
+		u.log.WithError(err).Error("Failed to revoke session refresh tokens")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// LogoutAll Usecase: "keluar dari semua device"
+func (u *useCase) LogoutAll(ctx context.Context, userID string) error {
+	return u.revokeAllSessions(ctx, userID)
+}
+
+// ChangePassword Usecase: wajib password lama, semua sesi lain diputus
+func (u *useCase) ChangePassword(ctx context.Context, userID string, req *ChangePasswordRequest) error {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return err
+	}
+
+	// 2. Verifikasi password lama
+	user, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("ChangePassword: failed to find user")
+		return ErrInternalServer
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !u.checkPassword(user.Password, req.OldPassword) {
+		return ErrInvalidPassword
+	}
+
+	// 3. Cek Password Policy
+	if err := u.checkPasswordPolicy("new_password", req.NewPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	// 4. Simpan password baru & putus semua sesi
+	return u.setPassword(ctx, user.ID, req.NewPassword)
+}
+
+// ForgotPassword Usecase: selalu sukses agar keberadaan email tidak bocor
+func (u *useCase) ForgotPassword(ctx context.Context, req *ForgotPasswordRequest) error {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return err
+	}
+
+	// 2. Cari User by Email (email tidak terdaftar = diam-diam selesai)
+	user, err := u.repo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		u.log.WithError(err).Error("ForgotPassword: failed to find user")
+		return ErrInternalServer
+	}
+	if user == nil {
+		return nil
+	}
+
+	// 3. Kirim link reset lewat email
+	return u.sendPasswordResetEmail(ctx, user)
+}
+
+// sendPasswordResetEmail menerbitkan token reset sekali pakai dan mengirim link-nya ke user
+func (u *accountSecurity) sendPasswordResetEmail(ctx context.Context, user *User) error {
+	// 1. Terbitkan token sekali pakai
+	resetTTL := u.cfg.GetDuration("password.reset_ttl")
+	if resetTTL == 0 {
+		resetTTL = time.Hour // Default value
+	}
+	plain, err := u.issueActionToken(ctx, user.ID, PurposePasswordReset, resetTTL)
+	if err != nil {
+		return err
+	}
+
+	// 2. Kirim lewat email
+	link := u.cfg.GetString("app.frontend_url") + "/reset-password?token=" + plain
+	err = u.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nUse the link below to reset your password. It expires in %s and can only be used once.\n\n%s\n\nIf you did not request this, you can ignore this email.\n",
+			user.Username, resetTTL, link,
+		),
+	})
+	if err != nil {
+		u.log.WithError(err).Error("Failed to send password reset email")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// ResetPassword Usecase: token valid sekali, lalu semua sesi diputus
+func (u *useCase) ResetPassword(ctx context.Context, req *ResetPasswordRequest) error {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return err
+	}
+
+	// 2. Cek token & Password Policy sebelum token dipakai,
+	// supaya password yang ditolak policy tidak menghanguskan link reset
+	tokenHash := hashToken(req.Token)
+	token, err := u.tokenRepo.FindActionToken(ctx, PurposePasswordReset, tokenHash)
+	if err != nil {
+		u.log.WithError(err).Error("ResetPassword: failed to find token")
+		return ErrInternalServer
+	}
+	if token == nil {
+		return ErrInvalidResetToken
+	}
+	user, err := u.repo.FindByID(ctx, token.UserID)
+	if err != nil {
+		u.log.WithError(err).Error("ResetPassword: failed to find user")
+		return ErrInternalServer
+	}
+	if user == nil {
+		return ErrInvalidResetToken
+	}
+	if err := u.checkPasswordPolicy("new_password", req.NewPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	// 3. Pakai token (atomik: tidak bisa dipakai dua kali)
+	token, err = u.tokenRepo.ConsumeActionToken(ctx, PurposePasswordReset, tokenHash)
+	if err != nil {
+		u.log.WithError(err).Error("ResetPassword: failed to consume token")
+		return ErrInternalServer
+	}
+	if token == nil {
+		return ErrInvalidResetToken
+	}
+
+	// 4. Simpan password baru & putus semua sesi
+	return u.setPassword(ctx, token.UserID, req.NewPassword)
+}
+
+// VerifyEmail Usecase: token sekali pakai dari email registrasi
+func (u *useCase) VerifyEmail(ctx context.Context, req *VerifyEmailRequest) (*UserResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, ErrInvalidVerifyToken
+	}
+
+	// 2. Pakai token (atomik: tidak bisa dipakai dua kali)
+	token, err := u.tokenRepo.ConsumeActionToken(ctx, PurposeEmailVerification, hashToken(req.Token))
+	if err != nil {
+		u.log.WithError(err).Error("VerifyEmail: failed to consume token")
+		return nil, ErrInternalServer
+	}
+	if token == nil {
+		return nil, ErrInvalidVerifyToken
+	}
+
+	// 3. Tandai email terverifikasi
+	user, err := u.repo.FindByID(ctx, token.UserID)
+	if err != nil {
+		u.log.WithError(err).Error("VerifyEmail: failed to find user")
+		return nil, ErrInternalServer
+	}
+	if user == nil {
+		return nil, ErrInvalidVerifyToken
+	}
+
+	now := time.Now()
+	if err := u.repo.MarkEmailVerified(ctx, user.ID, now); err != nil {
+		u.log.WithError(err).Error("VerifyEmail: failed to mark email verified")
+		return nil, ErrInternalServer
+	}
+	user.EmailVerifiedAt = &now
+
+	resp := toUserResponse(user)
+	return &resp, nil
+}
+
+// ResendVerification Usecase: email tidak terdaftar atau sudah terverifikasi tetap dianggap sukses,
+// agar endpoint ini tidak bisa dipakai untuk menebak status akun
+func (u *useCase) ResendVerification(ctx context.Context, req *ResendVerificationRequest) error {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return err
+	}
+
+	// 2. Cari User by Email (tidak terdaftar / sudah terverifikasi = diam-diam selesai)
+	user, err := u.repo.FindByEmail(ctx, req.Email)
+	if err != nil {
+		u.log.WithError(err).Error("ResendVerification: failed to find user")
+		return ErrInternalServer
+	}
+	if user == nil || user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	// 3. Token baru otomatis membatalkan token sebelumnya
+	if err := u.sendVerificationEmail(ctx, user); err != nil {
+		u.log.WithError(err).Error("ResendVerification: failed to send email")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// sendVerificationEmail menerbitkan token verifikasi dan mengirimkannya ke email user
+func (u *useCase) sendVerificationEmail(ctx context.Context, user *User) error {
+	verifyTTL := u.cfg.GetDuration("auth.verification_ttl")
+	if verifyTTL == 0 {
+		verifyTTL = 24 * time.Hour // Default value
+	}
+
+	plain, err := u.issueActionToken(ctx, user.ID, PurposeEmailVerification, verifyTTL)
+	if err != nil {
+		return err
+	}
+
+	link := u.cfg.GetString("app.frontend_url") + "/verify-email?token=" + plain
+	return u.mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below. It expires in %s.\n\n%s\n",
+			user.Username, verifyTTL, link,
+		),
+	})
+}
+
+// DeleteAccount Usecase: soft delete, data dihapus permanen setelah masa retensi
+func (u *useCase) DeleteAccount(ctx context.Context, userID string, req *DeleteAccountRequest) error {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return err
+	}
+
+	// 2. Konfirmasi password
+	user, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("DeleteAccount: failed to find user")
+		return ErrInternalServer
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	if !u.checkPassword(user.Password, req.Password) {
+		return ErrInvalidPassword
+	}
+
+	// 3. Tandai terhapus (username & email langsung bisa dipakai akun lain)
+	if err := u.repo.SoftDelete(ctx, user.ID, time.Now()); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return err
+		}
+		u.log.WithError(err).Error("DeleteAccount: failed to delete user")
+		return ErrInternalServer
+	}
+
+	// 4. Putus semua sesi
+	return u.revokeAllSessions(ctx, user.ID)
+}
+
+// PurgeDeletedAccounts menghapus permanen akun yang melewati account.retention
+// beserta catatan access token dicabut yang sudah kedaluwarsa.
+// Dipanggil berkala oleh background worker.
+func (u *useCase) PurgeDeletedAccounts(ctx context.Context) error {
+	retention := u.cfg.GetDuration("account.retention")
+	if retention == 0 {
+		retention = 30 * 24 * time.Hour // Default value
+	}
+
+	purged, err := u.repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		u.log.Infof("Purged %d deleted accounts", purged)
+	}
+
+	// jti yang sudah lewat exp tidak perlu dicek lagi, tabelnya jangan dibiarkan tumbuh
+	expired, err := u.revocations.PurgeExpired(ctx)
+	if err != nil {
+		return err
+	}
+	if expired > 0 {
+		u.log.Infof("Purged %d expired revoked tokens", expired)
+	}
+	return nil
+}
+
+// setPassword meng-hash & menyimpan password baru, lalu memutus semua sesi user
+func (u *useCase) setPassword(ctx context.Context, userID string, password string) error {
+	hashed, err := u.passwords.Hash(password)
+	if err != nil {
+		u.log.WithError(err).Error("Failed to hash password")
+		return ErrInternalServer
+	}
+
+	if err := u.repo.UpdatePassword(ctx, userID, hashed); err != nil {
+		u.log.WithError(err).Error("Failed to update password")
+		return ErrInternalServer
+	}
+
+	return u.revokeAllSessions(ctx, userID)
+}
+
+// revokeAllSessions mencabut semua access token & refresh token milik user
+func (u *accountSecurity) revokeAllSessions(ctx context.Context, userID string) error {
+	// 1. Semua access token yang terbit sampai detik ini ditolak middleware (iat JWT berpresisi detik)
+	if err := u.revocations.RevokeAllForUser(ctx, userID, time.Now().Truncate(time.Second)); err != nil {
+		u.log.WithError(err).Error("Failed to revoke access tokens")
+		return ErrInternalServer
+	}
+
+	// 2. Semua refresh token dicabut agar tidak bisa menerbitkan access token baru
+	if err := u.tokenRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		u.log.WithError(err).Error("Failed to revoke refresh tokens")
+		return ErrInternalServer
+	}
+
+	// 3. Semua sesi ditandai berakhir
+	if err := u.sessions.TerminateAllForUser(ctx, userID); err != nil {
+		u.log.WithError(err).Error("Failed to terminate sessions")
+		return ErrInternalServer
+	}
+
+	// 4. Personal access token ikut dicabut, kalau tidak token yang bocor tetap berlaku
+	if err := u.apiTokens.RevokeAllByUserID(ctx, userID); err != nil {
+		u.log.WithError(err).Error("Failed to revoke api tokens")
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// issueActionToken membuat token sekali pakai, return nilai plaintext untuk dikirim ke user
+func (u *accountSecurity) issueActionToken(ctx context.Context, userID string, purpose string, ttl time.Duration) (string, error) {
+	plain, err := generateToken()
+	if err != nil {
+		u.log.WithError(err).Error("Failed to generate action token")
+		return "", ErrInternalServer
+	}
+
+	now := time.Now()
+	token := &ActionToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashToken(plain),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+	if err := u.tokenRepo.SaveActionToken(ctx, token); err != nil {
+		u.log.WithError(err).Error("Failed to save action token")
+		return "", ErrInternalServer
+	}
+	return plain, nil
+}
+
+// startSession mencatat sesi (device) baru lalu menerbitkan token untuk sesi tersebut
+func (u *useCase) startSession(ctx context.Context, user *User, ipAddress string, userAgent string) (*LoginResponse, error) {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := &Session{
+		ID:         uuid.New().String(),
+		UserID:     user.ID,
+		UserAgent:  userAgent,
+		IPAddress:  ipAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+	}
+	if err := u.sessions.Create(ctx, session); err != nil {
+		u.log.WithError(err).Error("Failed To Save Session")
+		return nil, ErrInternalServer
+	}
+
+	// ID sesi sekaligus menjadi family refresh token
+	return u.issueTokens(ctx, user, session.ID)
+}
+
+// issueTokens menerbitkan access token dan refresh token baru untuk family tertentu
+func (u *useCase) issueTokens(ctx context.Context, user *User, familyID string) (*LoginResponse, error) {
+	accessToken, expiresIn, err := u.signAccessToken(user, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, refreshToken, err := u.newRefreshToken(user.ID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := u.tokenRepo.Save(ctx, refreshToken); err != nil {
+		u.log.WithError(err).Error("Failed To Save Refresh Token")
+		return nil, ErrInternalServer
+	}
+
+	return newLoginResponse(user, accessToken, plain, expiresIn), nil
+}
+
+// signAccessToken membuat JWT berumur pendek (jwt.ttl) untuk sesi tertentu
+func (u *useCase) signAccessToken(user *User, sessionID string) (string, int64, error) {
+	tokenTTL := u.cfg.GetDuration("jwt.ttl")
+	if tokenTTL == 0 {
+		tokenTTL = 15 * time.Minute // Default value
+	}
+
+	// Setup Claims
+	now := time.Now()
+	claims := middleware.AccessClaims{
+		SessionID: sessionID,
+		Role:      user.Role,
+		Name:      user.Username,
+		Email:     user.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Subject:   user.ID,
+			Issuer:    u.cfg.GetString("jwt.issuer"),
+			ExpiresAt: jwt.NewNumericDate(now.Add(tokenTTL)),
+			NotBefore: jwt.NewNumericDate(now),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
+	}
+	if audience := u.cfg.GetString("jwt.audience"); audience != "" {
+		claims.Audience = jwt.ClaimStrings{audience}
+	}
+
+	// Sign Token (algoritma & kid mengikuti key aktif)
+	signedToken, err := u.signer.Sign(claims)
+	if err != nil {
+		u.log.WithError(err).Error("Failed To Sign Token")
+		return "", 0, ErrInternalServer
+	}
+
+	return signedToken, int64(tokenTTL.Seconds()), nil
+}
+
+// newRefreshToken membuat token acak; yang disimpan ke DB hanya hash-nya
+func (u *useCase) newRefreshToken(userID string, familyID string) (string, *RefreshToken, error) {
+	refreshTTL := u.cfg.GetDuration("jwt.refresh_ttl")
+	if refreshTTL == 0 {
+		refreshTTL = 7 * 24 * time.Hour // Default value
+	}
+
+	plain, err := generateToken()
+	if err != nil {
+		u.log.WithError(err).Error("Failed To Generate Refresh Token")
+		return "", nil, ErrInternalServer
+	}
+
+	now := time.Now()
+	return plain, &RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(plain),
+		ExpiresAt: now.Add(refreshTTL),
+		CreatedAt: now,
 	}, nil
 }
 
+func (u *useCase) revokeReusedFamily(ctx context.Context, stored *RefreshToken) error {
+	u.log.Warnf("Refresh token reuse detected for user %s, revoking family %s", stored.UserID, stored.FamilyID)
+	if err := u.tokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		u.log.WithError(err).Error("Failed to revoke token family")
+		return ErrInternalServer
+	}
+
+	// Access token yang masih beredar di sesi ini ikut ditolak
+	if err := u.sessions.Terminate(ctx, stored.FamilyID, stored.UserID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		u.log.WithError(err).Error("Failed to terminate session")
+		return ErrInternalServer
+	}
+	return ErrRefreshTokenReused
+}
+
+func newLoginResponse(user *User, accessToken string, refreshToken string, expiresIn int64) *LoginResponse {
+	userResponse := toUserResponse(user)
+	return &LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    expiresIn,
+		User:         &userResponse,
+	}
+}
+
+// generateToken menghasilkan 32 byte acak dalam format base64url
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashToken: SHA-256 hex, dipakai untuk menyimpan & mencari token di DB
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func (u *useCase) GetMe(ctx context.Context, userID string) (*UserResponse, error) {
 	// Kita reuse FindByEmail logic, tapi kali ini kita butuh FindByID di repository
 	// Karena FindByID belum ada, mari kita buat di langkah berikutnya.
@@ -165,13 +1297,65 @@ func (u *useCase) GetMe(ctx context.Context, userID string) (*UserResponse, erro
 		return nil, ErrInternalServer
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
-	return &UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-	}, nil
+	resp := toUserResponse(user)
+	return &resp, nil
+}
+
+// UpdateProfile Usecase
+func (u *useCase) UpdateProfile(ctx context.Context, userID string, req *UpdateProfileRequest) (*UserResponse, error) {
+	// 1. Validasi Input
+	if err := u.validate.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Ambil data user saat ini
+	user, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		u.log.WithError(err).Error("UpdateProfile: failed to find user")
+		return nil, ErrInternalServer
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	// 3. Perubahan email wajib dikonfirmasi dengan password saat ini
+	// (email case-insensitive: beda huruf besar/kecil saja bukan alamat baru)
+	emailChanged := req.Email != nil && !strings.EqualFold(*req.Email, user.Email)
+	if emailChanged {
+		if req.Password == "" {
+			return nil, ErrPasswordRequired
+		}
+		if !u.checkPassword(user.Password, req.Password) {
+			return nil, ErrInvalidPassword
+		}
+		user.Email = *req.Email
+		// Email baru harus diverifikasi ulang
+		user.EmailVerifiedAt = nil
+	}
+	if req.Username != nil {
+		user.Username = *req.Username
+	}
+
+	// 4. Simpan perubahan
+	if err := u.repo.Update(ctx, user); err != nil {
+		if errors.Is(err, ErrEmailTaken) || errors.Is(err, ErrUsernameTaken) {
+			return nil, err
+		}
+
+		u.log.WithError(err).Error("Failed to update user")
+		return nil, ErrInternalServer
+	}
+
+	// 5. Kirim email verifikasi ke alamat baru
+	if emailChanged {
+		if err := u.sendVerificationEmail(ctx, user); err != nil {
+			u.log.WithError(err).Warn("UpdateProfile: failed to send verification email")
+		}
+	}
+
+	resp := toUserResponse(user)
+	return &resp, nil
 }
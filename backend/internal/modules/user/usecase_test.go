@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/jwtkey"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/mailer"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/middleware"
+	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/infra/password"
 	"github.com/TubagusAldiMY/finance-tracker-app/backend/internal/modules/user"
 	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -39,6 +45,14 @@ func (m *MockRepository) FindByEmail(ctx context.Context, email string) (*user.U
 	return args.Get(0).(*user.User), args.Error(1)
 }
 
+func (m *MockRepository) FindByUsername(ctx context.Context, username string) (*user.User, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.User), args.Error(1)
+}
+
 func (m *MockRepository) FindByID(ctx context.Context, id string) (*user.User, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -47,13 +61,333 @@ func (m *MockRepository) FindByID(ctx context.Context, id string) (*user.User, e
 	return args.Get(0).(*user.User), args.Error(1)
 }
 
+func (m *MockRepository) Update(ctx context.Context, u *user.User) error {
+	args := m.Called(ctx, u)
+	return args.Error(0)
+}
+
+func (m *MockRepository) MarkEmailVerified(ctx context.Context, id string, verifiedAt time.Time) error {
+	args := m.Called(ctx, id, verifiedAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SoftDelete(ctx context.Context, id string, deletedAt time.Time) error {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) UpdatePassword(ctx context.Context, id string, password string) error {
+	args := m.Called(ctx, id, password)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdatePasswordHash(ctx context.Context, id string, oldHash string, newHash string) error {
+	args := m.Called(ctx, id, oldHash, newHash)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SetTOTPSecret(ctx context.Context, id string, secret string) error {
+	args := m.Called(ctx, id, secret)
+	return args.Error(0)
+}
+
+func (m *MockRepository) EnableTOTP(ctx context.Context, id string, enabledAt time.Time, recoveryCodeHashes []string) error {
+	args := m.Called(ctx, id, enabledAt, recoveryCodeHashes)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DisableTOTP(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UseTOTPStep(ctx context.Context, id string, step int64) (bool, error) {
+	args := m.Called(ctx, id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) Search(ctx context.Context, query string, limit int, offset int) ([]user.User, error) {
+	args := m.Called(ctx, query, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]user.User), args.Error(1)
+}
+
+func (m *MockRepository) SetDisabled(ctx context.Context, id string, disabledAt *time.Time, audit *user.AuditLog) error {
+	args := m.Called(ctx, id, disabledAt, audit)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RequirePasswordReset(ctx context.Context, id string, audit *user.AuditLog) error {
+	args := m.Called(ctx, id, audit)
+	return args.Error(0)
+}
+
+// MockTokenRepository memalsukan behavior TokenRepository
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRepository) Save(ctx context.Context, token *user.RefreshToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*user.RefreshToken, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.RefreshToken), args.Error(1)
+}
+
+func (m *MockTokenRepository) Rotate(ctx context.Context, oldID string, newToken *user.RefreshToken) error {
+	args := m.Called(ctx, oldID, newToken)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(ctx, familyID)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) RevokeAllByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) SaveActionToken(ctx context.Context, token *user.ActionToken) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) FindActionToken(ctx context.Context, purpose string, tokenHash string) (*user.ActionToken, error) {
+	args := m.Called(ctx, purpose, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.ActionToken), args.Error(1)
+}
+
+func (m *MockTokenRepository) ConsumeActionToken(ctx context.Context, purpose string, tokenHash string) (*user.ActionToken, error) {
+	args := m.Called(ctx, purpose, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*user.ActionToken), args.Error(1)
+}
+
+// MockMailer memalsukan pengiriman email
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	args := m.Called(ctx, msg)
+	return args.Error(0)
+}
+
+// MockAttemptRepository memalsukan penghitung gagal login
+type MockAttemptRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttemptRepository) RegisterFailure(ctx context.Context, key string, at time.Time, windowStart time.Time) (int, error) {
+	args := m.Called(ctx, key, at, windowStart)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockAttemptRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	args := m.Called(ctx, key, until)
+	return args.Error(0)
+}
+
+func (m *MockAttemptRepository) LockedUntil(ctx context.Context, keys []string, now time.Time) (*time.Time, error) {
+	args := m.Called(ctx, keys, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockAttemptRepository) Reset(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// newPermissiveAttempts: tidak pernah mengunci, dipakai test yang tidak menguji lockout
+func newPermissiveAttempts() *MockAttemptRepository {
+	m := new(MockAttemptRepository)
+	m.On("LockedUntil", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+	m.On("RegisterFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(1, nil).Maybe()
+	m.On("Reset", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+// MockCategorySeeder memalsukan pembuatan kategori default
+type MockCategorySeeder struct {
+	mock.Mock
+}
+
+func (m *MockCategorySeeder) SeedDefaults(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// newPermissiveCategories: seed selalu berhasil, dipakai test yang tidak menguji kategori
+func newPermissiveCategories() *MockCategorySeeder {
+	m := new(MockCategorySeeder)
+	m.On("SeedDefaults", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+// MockRevocationStore memalsukan behavior RevocationStore
+type MockRevocationStore struct {
+	mock.Mock
+}
+
+func (m *MockRevocationStore) Revoke(ctx context.Context, jti string, userID string, expiresAt time.Time) error {
+	args := m.Called(ctx, jti, userID, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) RevokeAllForUser(ctx context.Context, userID string, before time.Time) error {
+	args := m.Called(ctx, userID, before)
+	return args.Error(0)
+}
+
+func (m *MockRevocationStore) IsRevoked(ctx context.Context, jti string, userID string, issuedAt time.Time) (bool, error) {
+	args := m.Called(ctx, jti, userID, issuedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRevocationStore) PurgeExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockSessionStore memalsukan penyimpanan sesi login
+type MockSessionStore struct {
+	mock.Mock
+}
+
+func (m *MockSessionStore) Create(ctx context.Context, session *user.Session) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockSessionStore) FindAllActiveByUserID(ctx context.Context, userID string) ([]user.Session, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]user.Session), args.Error(1)
+}
+
+func (m *MockSessionStore) Terminate(ctx context.Context, id string, userID string) error {
+	args := m.Called(ctx, id, userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionStore) TerminateAllForUser(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionStore) Touch(ctx context.Context, id string, userID string) (bool, error) {
+	args := m.Called(ctx, id, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+// newPermissiveSessions: default semua operasi sesi sukses,
+// test yang ingin memeriksa sesi menimpa dengan ExpectedCalls = nil
+func newPermissiveSessions() *MockSessionStore {
+	m := new(MockSessionStore)
+	m.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+	m.On("Terminate", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	m.On("TerminateAllForUser", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+// --- Mock APITokenRevoker ---
+type MockAPITokenRevoker struct {
+	mock.Mock
+}
+
+func (m *MockAPITokenRevoker) RevokeAllByUserID(ctx context.Context, userID string) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+// newPermissiveAPITokens: default pencabutan personal access token selalu sukses
+func newPermissiveAPITokens() *MockAPITokenRevoker {
+	m := new(MockAPITokenRevoker)
+	m.On("RevokeAllByUserID", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return m
+}
+
+// failingSigner: Penandatangan token yang selalu gagal (key rusak / tidak terbaca)
+type failingSigner struct{}
+
+func (failingSigner) Sign(claims jwt.Claims) (string, error) {
+	return "", errors.New("signing key unavailable")
+}
+
 // ==========================================
 // 2. HELPER SETUP
 // ==========================================
 
+// newTestHasher: bcrypt MinCost supaya test cepat; hash di test tidak pernah perlu di-upgrade
+func newTestHasher() *password.Hasher {
+	hasher, err := password.NewHasher(password.AlgorithmBcrypt, bcrypt.MinCost, password.DefaultArgon2Params)
+	if err != nil {
+		panic(err)
+	}
+	return hasher
+}
+
+// mocks: Kumpulan seluruh dependency palsu milik UseCase
+type mocks struct {
+	repo        *MockRepository
+	tokenRepo   *MockTokenRepository
+	revocations *MockRevocationStore
+	sessions    *MockSessionStore
+	apiTokens   *MockAPITokenRevoker
+	mailer      *MockMailer
+	attempts    *MockAttemptRepository
+	categories  *MockCategorySeeder
+	cfg         *viper.Viper
+}
+
 // setupTest mengembalikan Interface UseCase, MockRepo, dan Config untuk dimanipulasi
 func setupTest() (user.UseCase, *MockRepository, *viper.Viper) {
-	mockRepo := new(MockRepository)
+	u, m := setupMocks()
+	return u, m.repo, m.cfg
+}
+
+// setupMocks sama seperti setupTest, tapi mengembalikan semua mock dependency
+func setupMocks() (user.UseCase, *mocks) {
+	m := &mocks{
+		repo:        new(MockRepository),
+		tokenRepo:   new(MockTokenRepository),
+		revocations: new(MockRevocationStore),
+		sessions:    newPermissiveSessions(),
+		apiTokens:   newPermissiveAPITokens(),
+		mailer:      new(MockMailer),
+		attempts:    newPermissiveAttempts(),
+		categories:  newPermissiveCategories(),
+	}
 
 	// Logger buang ke tong sampah (supaya terminal bersih)
 	log := logrus.New()
@@ -62,13 +396,13 @@ func setupTest() (user.UseCase, *MockRepository, *viper.Viper) {
 	validate := validator.New()
 
 	// Config default yang valid
-	cfg := viper.New()
-	cfg.Set("jwt.secret", "secret_key_testing_123")
-	cfg.Set("jwt.ttl", "1h")
+	m.cfg = viper.New()
+	m.cfg.Set("jwt.ttl", "1h")
 
-	useCase := user.NewUseCase(mockRepo, log, validate, cfg)
+	signer := jwtkey.NewHMAC("secret_key_testing_123")
+	useCase := user.NewUseCase(m.repo, m.tokenRepo, m.revocations, m.sessions, m.apiTokens, m.mailer, m.attempts, m.categories, signer, newTestHasher(), password.DefaultPolicy(), log, validate, m.cfg)
 
-	return useCase, mockRepo, cfg
+	return useCase, m
 }
 
 // ==========================================
@@ -76,18 +410,26 @@ func setupTest() (user.UseCase, *MockRepository, *viper.Viper) {
 // ==========================================
 
 func TestRegister_Success(t *testing.T) {
-	u, mockRepo, _ := setupTest()
+	u, m := setupMocks()
+	mockRepo := m.repo
 
 	req := &user.RegisterRequest{
 		Username: "validuser",
 		Email:    "valid@example.com",
-		Password: "password123",
+		Password: "tabungan-aman-2026",
 	}
 
 	// Expectation: Repo.Save dipanggil sekali dengan data yang cocok
 	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(userObj *user.User) bool {
 		return userObj.Email == req.Email && userObj.Username == req.Username && userObj.ID != ""
 	})).Return(nil)
+	// Expectation: Email verifikasi dikirim setelah user tersimpan
+	m.tokenRepo.On("SaveActionToken", mock.Anything, mock.MatchedBy(func(token *user.ActionToken) bool {
+		return token.Purpose == user.PurposeEmailVerification
+	})).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.MatchedBy(func(msg *mailer.Message) bool {
+		return msg.To == req.Email
+	})).Return(nil)
 
 	// Action
 	resp, err := u.Register(context.Background(), req)
@@ -97,8 +439,59 @@ func TestRegister_Success(t *testing.T) {
 	assert.NotNil(t, resp)
 	assert.Equal(t, req.Email, resp.Email)
 	assert.NotEmpty(t, resp.ID)
+	assert.Nil(t, resp.EmailVerifiedAt)
 
 	mockRepo.AssertExpectations(t)
+	m.mailer.AssertExpectations(t)
+	m.categories.AssertCalled(t, "SeedDefaults", mock.Anything, resp.ID)
+}
+
+func TestRegister_CategorySeedFailureDoesNotFail(t *testing.T) {
+	_, m := setupMocks()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	m.categories = new(MockCategorySeeder)
+	u := user.NewUseCase(m.repo, m.tokenRepo, m.revocations, m.sessions, m.apiTokens, m.mailer, m.attempts, m.categories,
+		jwtkey.NewHMAC("secret_key_testing_123"), newTestHasher(), password.DefaultPolicy(), log, validator.New(), m.cfg)
+
+	req := &user.RegisterRequest{
+		Username: "validuser",
+		Email:    "valid@example.com",
+		Password: "tabungan-aman-2026",
+	}
+
+	m.repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	m.tokenRepo.On("SaveActionToken", mock.Anything, mock.Anything).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.Anything).Return(nil)
+	m.categories.On("SeedDefaults", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	resp, err := u.Register(context.Background(), req)
+
+	// Kategori default dibuat ulang saat user pertama kali membuka daftar kategori
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	m.categories.AssertExpectations(t)
+}
+
+func TestRegister_MailerFailureDoesNotFail(t *testing.T) {
+	u, m := setupMocks()
+
+	req := &user.RegisterRequest{
+		Username: "validuser",
+		Email:    "valid@example.com",
+		Password: "tabungan-aman-2026",
+	}
+
+	m.repo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	m.tokenRepo.On("SaveActionToken", mock.Anything, mock.Anything).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+
+	resp, err := u.Register(context.Background(), req)
+
+	// Akun tetap dibuat, user bisa minta kirim ulang email verifikasi
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
 }
 
 func TestRegister_ValidationError(t *testing.T) {
@@ -128,7 +521,7 @@ func TestRegister_DuplicateEmail(t *testing.T) {
 	req := &user.RegisterRequest{
 		Username: "newuser",
 		Email:    "taken@example.com",
-		Password: "password123",
+		Password: "tabungan-aman-2026",
 	}
 
 	// Expectation: Repo return error EmailTaken
@@ -147,7 +540,7 @@ func TestRegister_DuplicateUsername(t *testing.T) {
 	req := &user.RegisterRequest{
 		Username: "takenuser",
 		Email:    "new@example.com",
-		Password: "password123",
+		Password: "tabungan-aman-2026",
 	}
 
 	// Expectation: Repo return error UsernameTaken
@@ -166,7 +559,7 @@ func TestRegister_RepositoryError(t *testing.T) {
 	req := &user.RegisterRequest{
 		Username: "user",
 		Email:    "email@example.com",
-		Password: "password123",
+		Password: "tabungan-aman-2026",
 	}
 
 	// Expectation: Repo gagal koneksi DB
@@ -185,7 +578,8 @@ func TestRegister_RepositoryError(t *testing.T) {
 // ==========================================
 
 func TestLogin_Success(t *testing.T) {
-	u, mockRepo, _ := setupTest()
+	u, m := setupMocks()
+	mockRepo, mockTokenRepo := m.repo, m.tokenRepo
 
 	// Siapkan password hash yang valid
 	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
@@ -203,8 +597,11 @@ func TestLogin_Success(t *testing.T) {
 		Password: "password123", // Password raw yang cocok
 	}
 
-	// Expectation: FindByEmail sukses
+	// Expectation: FindByEmail sukses & refresh token disimpan dalam bentuk hash
 	mockRepo.On("FindByEmail", mock.Anything, req.Email).Return(dummyUser, nil)
+	mockTokenRepo.On("Save", mock.Anything, mock.MatchedBy(func(token *user.RefreshToken) bool {
+		return token.UserID == dummyUser.ID && token.FamilyID != "" && len(token.TokenHash) == 64
+	})).Return(nil)
 
 	// Action
 	resp, err := u.Login(context.Background(), req)
@@ -213,6 +610,8 @@ func TestLogin_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, resp)
 	assert.NotEmpty(t, resp.AccessToken) // Token harus ada
+	assert.NotEmpty(t, resp.RefreshToken)
+	assert.Equal(t, int64(3600), resp.ExpiresIn)
 	assert.Equal(t, "Bearer", resp.TokenType)
 	assert.Equal(t, dummyUser.Email, resp.User.Email)
 }
@@ -279,18 +678,17 @@ func TestLogin_RepositoryError(t *testing.T) {
 	assert.Nil(t, resp)
 }
 
-func TestLogin_MissingJWTSecret(t *testing.T) {
-	// Setup Manual Khusus case ini (karena butuh config rusak)
+func TestLogin_SigningFailure(t *testing.T) {
+	// Setup Manual Khusus case ini (karena butuh signer rusak)
 	mockRepo := new(MockRepository)
 	log := logrus.New()
 	log.SetOutput(io.Discard)
 	validate := validator.New()
 
-	// CONFIG RUSAK (Secret Kosong)
+	// Key penandatangan tidak bisa dipakai
 	cfg := viper.New()
-	cfg.Set("jwt.secret", "")
 
-	u := user.NewUseCase(mockRepo, log, validate, cfg)
+	u := user.NewUseCase(mockRepo, new(MockTokenRepository), new(MockRevocationStore), newPermissiveSessions(), newPermissiveAPITokens(), new(MockMailer), newPermissiveAttempts(), newPermissiveCategories(), failingSigner{}, newTestHasher(), password.DefaultPolicy(), log, validate, cfg)
 
 	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
 	dummyUser := &user.User{
@@ -353,7 +751,7 @@ func TestGetMe_UserNotFound(t *testing.T) {
 	// Assertions
 	assert.Error(t, err)
 	assert.Nil(t, resp)
-	assert.Equal(t, "user not found", err.Error())
+	assert.Equal(t, user.ErrUserNotFound, err)
 }
 
 func TestGetMe_RepositoryError(t *testing.T) {
@@ -372,3 +770,1229 @@ func TestGetMe_RepositoryError(t *testing.T) {
 	assert.Equal(t, user.ErrInternalServer, err)
 	assert.Nil(t, resp)
 }
+
+// ==========================================
+// 6. GROUP: UPDATE PROFILE TESTS
+// ==========================================
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestUpdateProfile_UsernameOnly(t *testing.T) {
+	u, mockRepo, _ := setupTest()
+
+	userID := "user-uuid-123"
+	dummyUser := &user.User{ID: userID, Username: "oldname", Email: "me@example.com"}
+
+	mockRepo.On("FindByID", mock.Anything, userID).Return(dummyUser, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(userObj *user.User) bool {
+		return userObj.Username == "newname" && userObj.Email == "me@example.com"
+	})).Return(nil)
+
+	// Ganti username tidak butuh password
+	resp, err := u.UpdateProfile(context.Background(), userID, &user.UpdateProfileRequest{Username: strPtr("newname")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "newname", resp.Username)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateProfile_EmailRequiresPassword(t *testing.T) {
+	u, mockRepo, _ := setupTest()
+
+	userID := "user-uuid-123"
+	dummyUser := &user.User{ID: userID, Email: "me@example.com"}
+
+	mockRepo.On("FindByID", mock.Anything, userID).Return(dummyUser, nil)
+
+	resp, err := u.UpdateProfile(context.Background(), userID, &user.UpdateProfileRequest{Email: strPtr("new@example.com")})
+
+	assert.Equal(t, user.ErrPasswordRequired, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestUpdateProfile_EmailWrongPassword(t *testing.T) {
+	u, mockRepo, _ := setupTest()
+
+	userID := "user-uuid-123"
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("realpassword"), bcrypt.DefaultCost)
+	dummyUser := &user.User{ID: userID, Email: "me@example.com", Password: string(hashedPwd)}
+
+	mockRepo.On("FindByID", mock.Anything, userID).Return(dummyUser, nil)
+
+	resp, err := u.UpdateProfile(context.Background(), userID, &user.UpdateProfileRequest{
+		Email:    strPtr("new@example.com"),
+		Password: "WRONG_PASSWORD",
+	})
+
+	assert.Equal(t, user.ErrInvalidPassword, err)
+	assert.Nil(t, resp)
+	mockRepo.AssertNotCalled(t, "Update")
+}
+
+func TestUpdateProfile_EmailTaken(t *testing.T) {
+	u, mockRepo, _ := setupTest()
+
+	userID := "user-uuid-123"
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	dummyUser := &user.User{ID: userID, Email: "me@example.com", Password: string(hashedPwd)}
+
+	mockRepo.On("FindByID", mock.Anything, userID).Return(dummyUser, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(user.ErrEmailTaken)
+
+	resp, err := u.UpdateProfile(context.Background(), userID, &user.UpdateProfileRequest{
+		Email:    strPtr("taken@example.com"),
+		Password: "password123",
+	})
+
+	assert.Equal(t, user.ErrEmailTaken, err)
+	assert.Nil(t, resp)
+}
+
+// ==========================================
+// 7. GROUP: REFRESH TOKEN TESTS
+// ==========================================
+
+// loginForRefreshToken melakukan login sukses dan mengembalikan refresh token plaintext + entity yang disimpan
+func loginForRefreshToken(t *testing.T, u user.UseCase, mockRepo *MockRepository, mockTokenRepo *MockTokenRepository) (string, *user.RefreshToken, *user.User) {
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Email: "login@example.com", Password: string(hashedPwd)}
+
+	var saved *user.RefreshToken
+	mockRepo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+	mockTokenRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*user.RefreshToken)
+	}).Return(nil).Once()
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Email: dummyUser.Email, Password: "password123"})
+	assert.NoError(t, err)
+
+	return resp.RefreshToken, saved, dummyUser
+}
+
+func TestRefresh_RotatesToken(t *testing.T) {
+	u, m := setupMocks()
+	mockRepo, mockTokenRepo := m.repo, m.tokenRepo
+
+	plain, stored, dummyUser := loginForRefreshToken(t, u, mockRepo, mockTokenRepo)
+
+	mockTokenRepo.On("FindByHash", mock.Anything, stored.TokenHash).Return(stored, nil)
+	mockRepo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	// Token baru harus tetap di family yang sama, dengan hash berbeda
+	mockTokenRepo.On("Rotate", mock.Anything, stored.ID, mock.MatchedBy(func(token *user.RefreshToken) bool {
+		return token.FamilyID == stored.FamilyID && token.TokenHash != stored.TokenHash
+	})).Return(nil)
+
+	resp, err := u.Refresh(context.Background(), &user.RefreshRequest{RefreshToken: plain})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.NotEqual(t, plain, resp.RefreshToken)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	u, m := setupMocks()
+	mockRepo, mockTokenRepo := m.repo, m.tokenRepo
+
+	plain, stored, _ := loginForRefreshToken(t, u, mockRepo, mockTokenRepo)
+
+	// Token sudah pernah dirotasi sebelumnya
+	revokedAt := time.Now()
+	stored.RevokedAt = &revokedAt
+
+	mockTokenRepo.On("FindByHash", mock.Anything, stored.TokenHash).Return(stored, nil)
+	mockTokenRepo.On("RevokeFamily", mock.Anything, stored.FamilyID).Return(nil)
+
+	resp, err := u.Refresh(context.Background(), &user.RefreshRequest{RefreshToken: plain})
+
+	assert.Equal(t, user.ErrRefreshTokenReused, err)
+	assert.Nil(t, resp)
+	mockTokenRepo.AssertCalled(t, "RevokeFamily", mock.Anything, stored.FamilyID)
+	mockTokenRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything, mock.Anything)
+	// Access token di sesi yang dicuri juga harus mati
+	m.sessions.AssertCalled(t, "Terminate", mock.Anything, stored.FamilyID, stored.UserID)
+}
+
+func TestRefresh_ConcurrentReuseRevokesFamily(t *testing.T) {
+	u, m := setupMocks()
+	mockRepo, mockTokenRepo := m.repo, m.tokenRepo
+
+	plain, stored, dummyUser := loginForRefreshToken(t, u, mockRepo, mockTokenRepo)
+
+	// Token lolos pengecekan awal tapi kalah balapan saat rotasi
+	mockTokenRepo.On("FindByHash", mock.Anything, stored.TokenHash).Return(stored, nil)
+	mockRepo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	mockTokenRepo.On("Rotate", mock.Anything, stored.ID, mock.Anything).Return(user.ErrRefreshTokenReused)
+	mockTokenRepo.On("RevokeFamily", mock.Anything, stored.FamilyID).Return(nil)
+
+	resp, err := u.Refresh(context.Background(), &user.RefreshRequest{RefreshToken: plain})
+
+	assert.Equal(t, user.ErrRefreshTokenReused, err)
+	assert.Nil(t, resp)
+	mockTokenRepo.AssertCalled(t, "RevokeFamily", mock.Anything, stored.FamilyID)
+}
+
+func TestRefresh_Expired(t *testing.T) {
+	u, m := setupMocks()
+	mockRepo, mockTokenRepo := m.repo, m.tokenRepo
+
+	plain, stored, _ := loginForRefreshToken(t, u, mockRepo, mockTokenRepo)
+	stored.ExpiresAt = time.Now().Add(-time.Minute)
+
+	mockTokenRepo.On("FindByHash", mock.Anything, stored.TokenHash).Return(stored, nil)
+
+	resp, err := u.Refresh(context.Background(), &user.RefreshRequest{RefreshToken: plain})
+
+	assert.Equal(t, user.ErrInvalidRefreshToken, err)
+	assert.Nil(t, resp)
+}
+
+func TestRefresh_PasswordResetRequired(t *testing.T) {
+	u, m := setupMocks()
+	mockRepo, mockTokenRepo := m.repo, m.tokenRepo
+
+	plain, stored, dummyUser := loginForRefreshToken(t, u, mockRepo, mockTokenRepo)
+
+	// Admin mewajibkan reset password setelah login: sesi lama tidak boleh diperpanjang
+	dummyUser.PasswordResetRequired = true
+	mockTokenRepo.On("FindByHash", mock.Anything, stored.TokenHash).Return(stored, nil)
+	mockRepo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+
+	resp, err := u.Refresh(context.Background(), &user.RefreshRequest{RefreshToken: plain})
+
+	assert.Equal(t, user.ErrInvalidRefreshToken, err)
+	assert.Nil(t, resp)
+	mockTokenRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRefresh_UnknownToken(t *testing.T) {
+	u, m := setupMocks()
+	mockTokenRepo := m.tokenRepo
+
+	mockTokenRepo.On("FindByHash", mock.Anything, mock.Anything).Return(nil, nil)
+
+	resp, err := u.Refresh(context.Background(), &user.RefreshRequest{RefreshToken: "does-not-exist"})
+
+	assert.Equal(t, user.ErrInvalidRefreshToken, err)
+	assert.Nil(t, resp)
+}
+
+// ==========================================
+// 8. GROUP: LOGOUT TESTS
+// ==========================================
+
+func TestLogin_TokenHasJTI(t *testing.T) {
+	u, m := setupMocks()
+
+	plain, _, _ := loginForRefreshToken(t, u, m.repo, m.tokenRepo)
+	assert.NotEmpty(t, plain)
+
+	// Login kedua harus menghasilkan jti berbeda
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+	first, _ := u.Login(context.Background(), &user.LoginRequest{Email: "login@example.com", Password: "password123"})
+	second, _ := u.Login(context.Background(), &user.LoginRequest{Email: "login@example.com", Password: "password123"})
+
+	firstClaims, secondClaims := jwt.MapClaims{}, jwt.MapClaims{}
+	_, _, err := jwt.NewParser().ParseUnverified(first.AccessToken, firstClaims)
+	assert.NoError(t, err)
+	_, _, err = jwt.NewParser().ParseUnverified(second.AccessToken, secondClaims)
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, firstClaims["jti"])
+	assert.NotEqual(t, firstClaims["jti"], secondClaims["jti"])
+}
+
+func TestLogout_RevokesAccessAndRefreshToken(t *testing.T) {
+	u, m := setupMocks()
+
+	plain, stored, dummyUser := loginForRefreshToken(t, u, m.repo, m.tokenRepo)
+	claims := &user.AccessTokenClaims{JTI: "jti-1", UserID: dummyUser.ID, SessionID: stored.FamilyID, ExpiresAt: time.Now().Add(time.Hour)}
+
+	m.revocations.On("Revoke", mock.Anything, claims.JTI, claims.UserID, claims.ExpiresAt).Return(nil)
+	m.tokenRepo.On("FindByHash", mock.Anything, stored.TokenHash).Return(stored, nil)
+	m.tokenRepo.On("RevokeFamily", mock.Anything, stored.FamilyID).Return(nil)
+
+	err := u.Logout(context.Background(), claims, &user.LogoutRequest{RefreshToken: plain})
+
+	assert.NoError(t, err)
+	m.revocations.AssertExpectations(t)
+	m.tokenRepo.AssertExpectations(t)
+	m.sessions.AssertCalled(t, "Terminate", mock.Anything, stored.FamilyID, dummyUser.ID)
+}
+
+func TestLogout_IgnoresRefreshTokenOfOtherUser(t *testing.T) {
+	u, m := setupMocks()
+
+	plain, stored, _ := loginForRefreshToken(t, u, m.repo, m.tokenRepo)
+	claims := &user.AccessTokenClaims{JTI: "jti-1", UserID: "someone-else", SessionID: "session-other", ExpiresAt: time.Now().Add(time.Hour)}
+
+	m.revocations.On("Revoke", mock.Anything, claims.JTI, claims.UserID, claims.ExpiresAt).Return(nil)
+	m.tokenRepo.On("RevokeFamily", mock.Anything, claims.SessionID).Return(nil)
+	m.tokenRepo.On("FindByHash", mock.Anything, stored.TokenHash).Return(stored, nil)
+
+	err := u.Logout(context.Background(), claims, &user.LogoutRequest{RefreshToken: plain})
+
+	assert.NoError(t, err)
+	m.tokenRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, stored.FamilyID)
+}
+
+func TestLogout_StoreError(t *testing.T) {
+	u, m := setupMocks()
+
+	claims := &user.AccessTokenClaims{JTI: "jti-1", UserID: "uuid-123", ExpiresAt: time.Now()}
+	m.revocations.On("Revoke", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+	err := u.Logout(context.Background(), claims, &user.LogoutRequest{})
+
+	assert.Equal(t, user.ErrInternalServer, err)
+}
+
+func TestLogoutAll_RevokesEverything(t *testing.T) {
+	u, m := setupMocks()
+
+	// Cutoff dibulatkan ke detik agar sebanding dengan iat JWT
+	m.revocations.On("RevokeAllForUser", mock.Anything, "uuid-123", mock.MatchedBy(func(before time.Time) bool {
+		return before.Equal(before.Truncate(time.Second))
+	})).Return(nil)
+	m.tokenRepo.On("RevokeAllByUserID", mock.Anything, "uuid-123").Return(nil)
+
+	err := u.LogoutAll(context.Background(), "uuid-123")
+
+	assert.NoError(t, err)
+	m.revocations.AssertExpectations(t)
+	m.tokenRepo.AssertExpectations(t)
+	m.apiTokens.AssertCalled(t, "RevokeAllByUserID", mock.Anything, "uuid-123")
+}
+
+// ==========================================
+// 9. GROUP: PASSWORD CHANGE & RESET TESTS
+// ==========================================
+
+func TestChangePassword_Success(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("oldpassword"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Password: string(hashedPwd)}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("UpdatePassword", mock.Anything, dummyUser.ID, mock.MatchedBy(func(hash string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte("newpassword")) == nil
+	})).Return(nil)
+	// Semua sesi lama harus diputus
+	m.revocations.On("RevokeAllForUser", mock.Anything, dummyUser.ID, mock.Anything).Return(nil)
+	m.tokenRepo.On("RevokeAllByUserID", mock.Anything, dummyUser.ID).Return(nil)
+
+	err := u.ChangePassword(context.Background(), dummyUser.ID, &user.ChangePasswordRequest{
+		OldPassword: "oldpassword",
+		NewPassword: "newpassword",
+	})
+
+	assert.NoError(t, err)
+	m.repo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+	m.tokenRepo.AssertExpectations(t)
+	m.apiTokens.AssertCalled(t, "RevokeAllByUserID", mock.Anything, dummyUser.ID)
+}
+
+func TestChangePassword_WrongOldPassword(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("oldpassword"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Password: string(hashedPwd)}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+
+	err := u.ChangePassword(context.Background(), dummyUser.ID, &user.ChangePasswordRequest{
+		OldPassword: "WRONG_PASSWORD",
+		NewPassword: "newpassword",
+	})
+
+	assert.Equal(t, user.ErrInvalidPassword, err)
+	m.repo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestForgotPassword_SendsHashedToken(t *testing.T) {
+	u, m := setupMocks()
+
+	dummyUser := &user.User{ID: "uuid-123", Username: "forgetful", Email: "me@example.com"}
+	var saved *user.ActionToken
+
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+	m.tokenRepo.On("SaveActionToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*user.ActionToken)
+	}).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.MatchedBy(func(msg *mailer.Message) bool {
+		return msg.To == dummyUser.Email
+	})).Return(nil)
+
+	err := u.ForgotPassword(context.Background(), &user.ForgotPasswordRequest{Email: dummyUser.Email})
+
+	assert.NoError(t, err)
+	assert.Equal(t, user.PurposePasswordReset, saved.Purpose)
+	assert.True(t, saved.ExpiresAt.After(time.Now()))
+	// Email berisi token plaintext, DB hanya menyimpan hash-nya
+	msg := m.mailer.Calls[0].Arguments.Get(1).(*mailer.Message)
+	assert.NotContains(t, msg.Body, saved.TokenHash)
+}
+
+func TestForgotPassword_UnknownEmailIsSilent(t *testing.T) {
+	u, m := setupMocks()
+
+	m.repo.On("FindByEmail", mock.Anything, "ghost@example.com").Return(nil, nil)
+
+	err := u.ForgotPassword(context.Background(), &user.ForgotPasswordRequest{Email: "ghost@example.com"})
+
+	assert.NoError(t, err)
+	m.mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestResetPassword_Success(t *testing.T) {
+	u, m := setupMocks()
+
+	token := &user.ActionToken{ID: "token-1", UserID: "uuid-123", Purpose: user.PurposePasswordReset}
+
+	m.tokenRepo.On("FindActionToken", mock.Anything, user.PurposePasswordReset, mock.Anything).Return(token, nil)
+	m.repo.On("FindByID", mock.Anything, token.UserID).Return(&user.User{ID: token.UserID, Username: "budi", Email: "budi@example.com"}, nil)
+	m.tokenRepo.On("ConsumeActionToken", mock.Anything, user.PurposePasswordReset, mock.Anything).Return(token, nil)
+	m.repo.On("UpdatePassword", mock.Anything, token.UserID, mock.Anything).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, token.UserID, mock.Anything).Return(nil)
+	m.tokenRepo.On("RevokeAllByUserID", mock.Anything, token.UserID).Return(nil)
+
+	err := u.ResetPassword(context.Background(), &user.ResetPasswordRequest{Token: "plain-token", NewPassword: "newpassword"})
+
+	assert.NoError(t, err)
+	m.repo.AssertExpectations(t)
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	u, m := setupMocks()
+
+	// Token sudah dipakai / kedaluwarsa / tidak ada
+	m.tokenRepo.On("FindActionToken", mock.Anything, user.PurposePasswordReset, mock.Anything).Return(nil, nil)
+
+	err := u.ResetPassword(context.Background(), &user.ResetPasswordRequest{Token: "used-token", NewPassword: "newpassword"})
+
+	assert.Equal(t, user.ErrInvalidResetToken, err)
+	m.repo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// ==========================================
+// 10. GROUP: EMAIL VERIFICATION TESTS
+// ==========================================
+
+func TestLogin_UnverifiedEmailRejectedWhenRequired(t *testing.T) {
+	u, m := setupMocks()
+	m.cfg.Set("auth.require_verified_email", true)
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Email: "new@example.com", Password: string(hashedPwd)}
+
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Email: dummyUser.Email, Password: "password123"})
+
+	assert.Equal(t, user.ErrEmailNotVerified, err)
+	assert.Nil(t, resp)
+	m.tokenRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestVerifyEmail_Success(t *testing.T) {
+	u, m := setupMocks()
+
+	token := &user.ActionToken{UserID: "uuid-123", Purpose: user.PurposeEmailVerification}
+	dummyUser := &user.User{ID: "uuid-123", Email: "new@example.com"}
+
+	m.tokenRepo.On("ConsumeActionToken", mock.Anything, user.PurposeEmailVerification, mock.Anything).Return(token, nil)
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("MarkEmailVerified", mock.Anything, dummyUser.ID, mock.Anything).Return(nil)
+
+	resp, err := u.VerifyEmail(context.Background(), &user.VerifyEmailRequest{Token: "plain-token"})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.EmailVerifiedAt)
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	u, m := setupMocks()
+
+	m.tokenRepo.On("ConsumeActionToken", mock.Anything, user.PurposeEmailVerification, mock.Anything).Return(nil, nil)
+
+	resp, err := u.VerifyEmail(context.Background(), &user.VerifyEmailRequest{Token: "expired"})
+
+	assert.Equal(t, user.ErrInvalidVerifyToken, err)
+	assert.Nil(t, resp)
+}
+
+func TestResendVerification_AlreadyVerifiedIsSilent(t *testing.T) {
+	u, m := setupMocks()
+
+	verifiedAt := time.Now()
+	m.repo.On("FindByEmail", mock.Anything, "budi@example.com").Return(&user.User{ID: "uuid-123", Email: "budi@example.com", EmailVerifiedAt: &verifiedAt}, nil)
+
+	// Respons sama dengan email yang belum terverifikasi / tidak terdaftar
+	err := u.ResendVerification(context.Background(), &user.ResendVerificationRequest{Email: "budi@example.com"})
+
+	assert.NoError(t, err)
+	m.tokenRepo.AssertNotCalled(t, "SaveActionToken", mock.Anything, mock.Anything)
+	m.mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestUpdateProfile_EmailCaseOnlyKeepsVerification(t *testing.T) {
+	u, m := setupMocks()
+
+	verifiedAt := time.Now()
+	dummyUser := &user.User{ID: "uuid-123", Email: "budi@example.com", EmailVerifiedAt: &verifiedAt}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("Update", mock.Anything, mock.MatchedBy(func(userObj *user.User) bool {
+		return userObj.EmailVerifiedAt != nil
+	})).Return(nil)
+
+	// Alamat yang sama: tidak butuh password, tidak ada email verifikasi
+	resp, err := u.UpdateProfile(context.Background(), dummyUser.ID, &user.UpdateProfileRequest{
+		Email: strPtr("Budi@Example.com"),
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.EmailVerifiedAt)
+	m.tokenRepo.AssertNotCalled(t, "SaveActionToken", mock.Anything, mock.Anything)
+	m.mailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestUpdateProfile_EmailChangeResetsVerification(t *testing.T) {
+	u, m := setupMocks()
+
+	verifiedAt := time.Now()
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Email: "old@example.com", Password: string(hashedPwd), EmailVerifiedAt: &verifiedAt}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("Update", mock.Anything, mock.MatchedBy(func(userObj *user.User) bool {
+		return userObj.Email == "new@example.com" && userObj.EmailVerifiedAt == nil
+	})).Return(nil)
+	m.tokenRepo.On("SaveActionToken", mock.Anything, mock.Anything).Return(nil)
+	m.mailer.On("Send", mock.Anything, mock.MatchedBy(func(msg *mailer.Message) bool {
+		return msg.To == "new@example.com"
+	})).Return(nil)
+
+	resp, err := u.UpdateProfile(context.Background(), dummyUser.ID, &user.UpdateProfileRequest{
+		Email:    strPtr("new@example.com"),
+		Password: "password123",
+	})
+
+	assert.NoError(t, err)
+	assert.Nil(t, resp.EmailVerifiedAt)
+	m.mailer.AssertExpectations(t)
+}
+
+// ==========================================
+// 11. GROUP: ACCOUNT DELETION TESTS
+// ==========================================
+
+func TestDeleteAccount_Success(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Password: string(hashedPwd)}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("SoftDelete", mock.Anything, dummyUser.ID, mock.AnythingOfType("time.Time")).Return(nil)
+	m.revocations.On("RevokeAllForUser", mock.Anything, dummyUser.ID, mock.Anything).Return(nil)
+	m.tokenRepo.On("RevokeAllByUserID", mock.Anything, dummyUser.ID).Return(nil)
+
+	err := u.DeleteAccount(context.Background(), dummyUser.ID, &user.DeleteAccountRequest{Password: "password123"})
+
+	assert.NoError(t, err)
+	m.repo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+}
+
+func TestDeleteAccount_WrongPassword(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Password: string(hashedPwd)}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+
+	err := u.DeleteAccount(context.Background(), dummyUser.ID, &user.DeleteAccountRequest{Password: "WRONG_PASSWORD"})
+
+	assert.Equal(t, user.ErrInvalidPassword, err)
+	m.repo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPurgeDeletedAccounts_UsesRetention(t *testing.T) {
+	u, m := setupMocks()
+	m.cfg.Set("account.retention", "48h")
+
+	m.repo.On("PurgeDeleted", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		expected := time.Now().Add(-48 * time.Hour)
+		return before.Sub(expected).Abs() < time.Minute
+	})).Return(int64(2), nil)
+	m.revocations.On("PurgeExpired", mock.Anything).Return(int64(5), nil)
+
+	err := u.PurgeDeletedAccounts(context.Background())
+
+	assert.NoError(t, err)
+	m.repo.AssertExpectations(t)
+	m.revocations.AssertExpectations(t)
+}
+
+func TestPurgeDeletedAccounts_RevokedTokensFailure(t *testing.T) {
+	u, m := setupMocks()
+
+	m.repo.On("PurgeDeleted", mock.Anything, mock.Anything).Return(int64(0), nil)
+	m.revocations.On("PurgeExpired", mock.Anything).Return(int64(0), errors.New("db down"))
+
+	err := u.PurgeDeletedAccounts(context.Background())
+
+	assert.Error(t, err)
+}
+
+func TestIsBeforeCutoff_SameSecondIsRevoked(t *testing.T) {
+	cutoff := time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC)
+
+	// iat berpresisi detik: token yang terbit di detik cutoff ikut dicabut, detik berikutnya tidak
+	assert.True(t, user.IsBeforeCutoff(cutoff, cutoff))
+	assert.True(t, user.IsBeforeCutoff(cutoff.Add(-time.Second), cutoff))
+	assert.False(t, user.IsBeforeCutoff(cutoff.Add(time.Second), cutoff))
+	assert.False(t, user.IsBeforeCutoff(cutoff, time.Time{}))
+}
+
+// ==========================================
+// 12. GROUP: LOGIN THROTTLING TESTS
+// ==========================================
+
+func TestLogin_LockedReturnsRetryAfter(t *testing.T) {
+	u, m := setupMocks()
+	m.attempts.ExpectedCalls = nil
+
+	until := time.Now().Add(2 * time.Minute)
+	m.repo.On("FindByEmail", mock.Anything, "locked@example.com").Return(nil, nil)
+	m.attempts.On("LockedUntil", mock.Anything, []string{"email:locked@example.com", "ip:10.0.0.1"}, mock.Anything).Return(&until, nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{
+		Email:     "locked@example.com",
+		Password:  "password123",
+		IPAddress: "10.0.0.1",
+	})
+
+	var tooMany *user.TooManyAttemptsError
+	assert.ErrorAs(t, err, &tooMany)
+	assert.InDelta(t, (2 * time.Minute).Seconds(), tooMany.RetryAfter.Seconds(), 5)
+	assert.Nil(t, resp)
+	// Password bahkan tidak dicek selama terkunci, jadi counter juga tidak bertambah
+	m.attempts.AssertNotCalled(t, "RegisterFailure", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLogin_LocksAfterMaxAttemptsWithBackoff(t *testing.T) {
+	u, m := setupMocks()
+	m.attempts.ExpectedCalls = nil
+	m.cfg.Set("auth.lockout.max_attempts", 3)
+	m.cfg.Set("auth.lockout.base_delay", "10s")
+
+	m.attempts.On("LockedUntil", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	m.repo.On("FindByEmail", mock.Anything, "victim@example.com").Return(nil, nil)
+	// Gagal ke-4 dengan max 3 => dikunci 2x base_delay
+	m.attempts.On("RegisterFailure", mock.Anything, "email:victim@example.com", mock.Anything, mock.Anything).Return(4, nil)
+	m.attempts.On("Lock", mock.Anything, "email:victim@example.com", mock.MatchedBy(func(until time.Time) bool {
+		return time.Until(until).Round(time.Second) == 20*time.Second
+	})).Return(nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Email: "victim@example.com", Password: "guess"})
+
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+	assert.Nil(t, resp)
+	m.attempts.AssertExpectations(t)
+}
+
+func TestLogin_LongLockoutClampsToMaxDelay(t *testing.T) {
+	u, m := setupMocks()
+	m.attempts.ExpectedCalls = nil
+	m.cfg.Set("auth.lockout.max_attempts", 3)
+	m.cfg.Set("auth.lockout.base_delay", "30s")
+	m.cfg.Set("auth.lockout.max_delay", "15m")
+
+	m.attempts.On("LockedUntil", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	m.repo.On("FindByEmail", mock.Anything, "victim@example.com").Return(nil, nil)
+	// 30s<<40 overflow time.Duration; lockout tetap max_delay, bukan waktu di masa lalu
+	m.attempts.On("RegisterFailure", mock.Anything, "email:victim@example.com", mock.Anything, mock.Anything).Return(43, nil)
+	m.attempts.On("Lock", mock.Anything, "email:victim@example.com", mock.MatchedBy(func(until time.Time) bool {
+		return time.Until(until).Round(time.Second) == 15*time.Minute
+	})).Return(nil)
+
+	_, err := u.Login(context.Background(), &user.LoginRequest{Email: "victim@example.com", Password: "guess"})
+
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+	m.attempts.AssertExpectations(t)
+}
+
+func TestLogin_IPHasHigherThreshold(t *testing.T) {
+	u, m := setupMocks()
+	m.attempts.ExpectedCalls = nil
+
+	m.attempts.On("LockedUntil", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	m.repo.On("FindByEmail", mock.Anything, "a@example.com").Return(nil, nil)
+	m.attempts.On("RegisterFailure", mock.Anything, "email:a@example.com", mock.Anything, mock.Anything).Return(1, nil)
+	// 6 gagal dari IP yang sama belum mencapai batas default IP (20)
+	m.attempts.On("RegisterFailure", mock.Anything, "ip:10.0.0.1", mock.Anything, mock.Anything).Return(6, nil)
+
+	_, err := u.Login(context.Background(), &user.LoginRequest{Email: "a@example.com", Password: "x", IPAddress: "10.0.0.1"})
+
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+	m.attempts.AssertNotCalled(t, "Lock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLogin_SuccessResetsAccountCounter(t *testing.T) {
+	u, m := setupMocks()
+	m.attempts.ExpectedCalls = nil
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Email: "Me@Example.com", Password: string(hashedPwd)}
+
+	m.attempts.On("LockedUntil", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+	m.attempts.On("Reset", mock.Anything, "user:uuid-123").Return(nil)
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	_, err := u.Login(context.Background(), &user.LoginRequest{Email: dummyUser.Email, Password: "password123", IPAddress: "10.0.0.1"})
+
+	assert.NoError(t, err)
+	m.attempts.AssertExpectations(t)
+}
+
+// ==========================================
+// 13. GROUP: TOTP TWO-FACTOR TESTS
+// ==========================================
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+// totpUser: user dengan 2FA aktif dan password "password123"
+func totpUser() *user.User {
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	enabledAt := time.Now().Add(-time.Hour)
+	return &user.User{
+		ID:            "uuid-123",
+		Email:         "mfa@example.com",
+		Password:      string(hashedPwd),
+		TOTPSecret:    strPtr(testTOTPSecret),
+		TOTPEnabledAt: &enabledAt,
+	}
+}
+
+func TestLogin_TOTPEnabledReturnsChallenge(t *testing.T) {
+	u, m := setupMocks()
+	dummyUser := totpUser()
+
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+	m.tokenRepo.On("SaveActionToken", mock.Anything, mock.MatchedBy(func(token *user.ActionToken) bool {
+		return token.Purpose == user.PurposeMFAChallenge && token.UserID == dummyUser.ID &&
+			time.Until(token.ExpiresAt).Round(time.Minute) == 5*time.Minute
+	})).Return(nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Email: dummyUser.Email, Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.True(t, resp.MFARequired)
+	assert.NotEmpty(t, resp.MFAToken)
+	assert.Empty(t, resp.AccessToken)
+	assert.Nil(t, resp.User)
+	// Belum ada refresh token sebelum langkah kedua
+	m.tokenRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestLoginMFA_ValidCodeIssuesTokens(t *testing.T) {
+	u, m := setupMocks()
+	dummyUser := totpUser()
+	challenge := &user.ActionToken{ID: "challenge-1", UserID: dummyUser.ID, Purpose: user.PurposeMFAChallenge}
+
+	m.tokenRepo.On("FindActionToken", mock.Anything, user.PurposeMFAChallenge, mock.Anything).Return(challenge, nil)
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("UseTOTPStep", mock.Anything, dummyUser.ID, mock.Anything).Return(true, nil)
+	m.tokenRepo.On("ConsumeActionToken", mock.Anything, user.PurposeMFAChallenge, mock.Anything).Return(challenge, nil)
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := u.LoginMFA(context.Background(), &user.LoginMFARequest{
+		MFAToken: "challenge-token",
+		Code:     user.TOTPCodeAt(testTOTPSecret, time.Now()),
+	})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Equal(t, dummyUser.Email, resp.User.Email)
+	m.tokenRepo.AssertExpectations(t)
+}
+
+func TestLoginMFA_ReplayedCodeRejected(t *testing.T) {
+	u, m := setupMocks()
+	dummyUser := totpUser()
+	challenge := &user.ActionToken{ID: "challenge-1", UserID: dummyUser.ID, Purpose: user.PurposeMFAChallenge}
+
+	m.tokenRepo.On("FindActionToken", mock.Anything, user.PurposeMFAChallenge, mock.Anything).Return(challenge, nil)
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	// Langkah TOTP ini sudah dipakai sebelumnya
+	m.repo.On("UseTOTPStep", mock.Anything, dummyUser.ID, mock.Anything).Return(false, nil)
+
+	resp, err := u.LoginMFA(context.Background(), &user.LoginMFARequest{
+		MFAToken: "challenge-token",
+		Code:     user.TOTPCodeAt(testTOTPSecret, time.Now()),
+	})
+
+	assert.Equal(t, user.ErrInvalidMFACode, err)
+	assert.Nil(t, resp)
+	m.tokenRepo.AssertNotCalled(t, "ConsumeActionToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLoginMFA_RecoveryCodeAccepted(t *testing.T) {
+	u, m := setupMocks()
+	dummyUser := totpUser()
+	challenge := &user.ActionToken{ID: "challenge-1", UserID: dummyUser.ID, Purpose: user.PurposeMFAChallenge}
+
+	m.tokenRepo.On("FindActionToken", mock.Anything, user.PurposeMFAChallenge, mock.Anything).Return(challenge, nil)
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	// Input dinormalisasi sebelum di-hash
+	m.repo.On("UseRecoveryCode", mock.Anything, dummyUser.ID, user.HashToken("abcde-fghij")).Return(true, nil)
+	m.tokenRepo.On("ConsumeActionToken", mock.Anything, user.PurposeMFAChallenge, mock.Anything).Return(challenge, nil)
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := u.LoginMFA(context.Background(), &user.LoginMFARequest{MFAToken: "challenge-token", Code: " ABCDE-FGHIJ "})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	m.repo.AssertExpectations(t)
+}
+
+func TestLoginMFA_InvalidChallenge(t *testing.T) {
+	u, m := setupMocks()
+
+	m.tokenRepo.On("FindActionToken", mock.Anything, user.PurposeMFAChallenge, mock.Anything).Return(nil, nil)
+
+	resp, err := u.LoginMFA(context.Background(), &user.LoginMFARequest{MFAToken: "bogus", Code: "123456"})
+
+	assert.Equal(t, user.ErrInvalidMFAToken, err)
+	assert.Nil(t, resp)
+}
+
+func TestEnrollTOTP_ReturnsOTPAuthURI(t *testing.T) {
+	u, m := setupMocks()
+	m.cfg.Set("app.name", "Finance Tracker")
+	dummyUser := &user.User{ID: "uuid-123", Email: "me@example.com"}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("SetTOTPSecret", mock.Anything, dummyUser.ID, mock.AnythingOfType("string")).Return(nil)
+
+	resp, err := u.EnrollTOTP(context.Background(), dummyUser.ID)
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Secret, 32)
+	assert.Contains(t, resp.URI, "otpauth://totp/")
+	assert.Contains(t, resp.URI, "secret="+resp.Secret)
+	m.repo.AssertExpectations(t)
+}
+
+func TestEnrollTOTP_AlreadyEnabled(t *testing.T) {
+	u, m := setupMocks()
+	dummyUser := totpUser()
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+
+	resp, err := u.EnrollTOTP(context.Background(), dummyUser.ID)
+
+	assert.Equal(t, user.ErrTOTPAlreadyEnabled, err)
+	assert.Nil(t, resp)
+}
+
+func TestConfirmTOTP_EnablesAndReturnsRecoveryCodes(t *testing.T) {
+	u, m := setupMocks()
+	dummyUser := &user.User{ID: "uuid-123", TOTPSecret: strPtr(testTOTPSecret)}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("EnableTOTP", mock.Anything, dummyUser.ID, mock.Anything, mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == 10
+	})).Return(nil)
+	m.repo.On("UseTOTPStep", mock.Anything, dummyUser.ID, mock.Anything).Return(true, nil)
+
+	resp, err := u.ConfirmTOTP(context.Background(), dummyUser.ID, &user.ConfirmTOTPRequest{
+		Code: user.TOTPCodeAt(testTOTPSecret, time.Now()),
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.RecoveryCodes, 10)
+	m.repo.AssertExpectations(t)
+}
+
+func TestConfirmTOTP_WrongCode(t *testing.T) {
+	u, m := setupMocks()
+	dummyUser := &user.User{ID: "uuid-123", TOTPSecret: strPtr(testTOTPSecret)}
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+
+	code := user.TOTPCodeAt(testTOTPSecret, time.Now().Add(-time.Hour))
+	resp, err := u.ConfirmTOTP(context.Background(), dummyUser.ID, &user.ConfirmTOTPRequest{Code: code})
+
+	assert.Equal(t, user.ErrInvalidMFACode, err)
+	assert.Nil(t, resp)
+	m.repo.AssertNotCalled(t, "EnableTOTP", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDisableTOTP_RequiresPasswordAndCode(t *testing.T) {
+	u, m := setupMocks()
+	dummyUser := totpUser()
+
+	m.repo.On("FindByID", mock.Anything, dummyUser.ID).Return(dummyUser, nil)
+	m.repo.On("UseTOTPStep", mock.Anything, dummyUser.ID, mock.Anything).Return(true, nil)
+	m.repo.On("DisableTOTP", mock.Anything, dummyUser.ID).Return(nil)
+
+	err := u.DisableTOTP(context.Background(), dummyUser.ID, &user.DisableTOTPRequest{
+		Password: "password123",
+		Code:     user.TOTPCodeAt(testTOTPSecret, time.Now()),
+	})
+	assert.NoError(t, err)
+
+	err = u.DisableTOTP(context.Background(), dummyUser.ID, &user.DisableTOTPRequest{Password: "WRONG", Code: "123456"})
+	assert.Equal(t, user.ErrInvalidPassword, err)
+	m.repo.AssertNumberOfCalls(t, "DisableTOTP", 1)
+}
+
+// ==========================================
+// 14. GROUP: SESSION MANAGEMENT TESTS
+// ==========================================
+
+func TestLogin_CreatesSessionReferencedByToken(t *testing.T) {
+	u, m := setupMocks()
+	m.sessions.ExpectedCalls = nil
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Email: "login@example.com", Password: string(hashedPwd)}
+
+	var session *user.Session
+	var refresh *user.RefreshToken
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+	m.sessions.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		session = args.Get(1).(*user.Session)
+	}).Return(nil)
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		refresh = args.Get(1).(*user.RefreshToken)
+	}).Return(nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{
+		Email:     dummyUser.Email,
+		Password:  "password123",
+		IPAddress: "10.0.0.1",
+		UserAgent: "curl/8.0",
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, dummyUser.ID, session.UserID)
+	assert.Equal(t, "10.0.0.1", session.IPAddress)
+	assert.Equal(t, "curl/8.0", session.UserAgent)
+	assert.Equal(t, session.ID, refresh.FamilyID)
+
+	claims := jwt.MapClaims{}
+	_, _, err = jwt.NewParser().ParseUnverified(resp.AccessToken, claims)
+	assert.NoError(t, err)
+	assert.Equal(t, session.ID, claims["sid"])
+}
+
+func TestListSessions_MarksCurrent(t *testing.T) {
+	u, m := setupMocks()
+
+	m.sessions.On("FindAllActiveByUserID", mock.Anything, "uuid-123").Return([]user.Session{
+		{ID: "session-a", UserID: "uuid-123", UserAgent: "Firefox"},
+		{ID: "session-b", UserID: "uuid-123", UserAgent: "curl/8.0"},
+	}, nil)
+
+	resp, err := u.ListSessions(context.Background(), "uuid-123", "session-b")
+
+	assert.NoError(t, err)
+	assert.Len(t, resp, 2)
+	assert.False(t, resp[0].Current)
+	assert.True(t, resp[1].Current)
+}
+
+func TestTerminateSession_RevokesRefreshTokens(t *testing.T) {
+	u, m := setupMocks()
+	m.sessions.ExpectedCalls = nil
+
+	sessionID := "44444444-4444-4444-4444-444444444444"
+	m.sessions.On("Terminate", mock.Anything, sessionID, "uuid-123").Return(nil)
+	m.tokenRepo.On("RevokeFamily", mock.Anything, sessionID).Return(nil)
+
+	err := u.TerminateSession(context.Background(), "uuid-123", sessionID)
+
+	assert.NoError(t, err)
+	m.sessions.AssertExpectations(t)
+	m.tokenRepo.AssertExpectations(t)
+}
+
+func TestTerminateSession_NotOwned(t *testing.T) {
+	u, m := setupMocks()
+	m.sessions.ExpectedCalls = nil
+
+	sessionID := "44444444-4444-4444-4444-444444444444"
+	m.sessions.On("Terminate", mock.Anything, sessionID, "uuid-123").Return(user.ErrSessionNotFound)
+
+	err := u.TerminateSession(context.Background(), "uuid-123", sessionID)
+
+	assert.Equal(t, user.ErrSessionNotFound, err)
+	m.tokenRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything, mock.Anything)
+}
+
+func TestTerminateSession_InvalidID(t *testing.T) {
+	u, m := setupMocks()
+
+	err := u.TerminateSession(context.Background(), "uuid-123", "not-a-uuid")
+
+	assert.Equal(t, user.ErrSessionNotFound, err)
+	m.sessions.AssertNotCalled(t, "Terminate", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLogoutAll_TerminatesAllSessions(t *testing.T) {
+	u, m := setupMocks()
+
+	m.revocations.On("RevokeAllForUser", mock.Anything, "uuid-123", mock.Anything).Return(nil)
+	m.tokenRepo.On("RevokeAllByUserID", mock.Anything, "uuid-123").Return(nil)
+
+	err := u.LogoutAll(context.Background(), "uuid-123")
+
+	assert.NoError(t, err)
+	m.sessions.AssertCalled(t, "TerminateAllForUser", mock.Anything, "uuid-123")
+}
+
+// ==========================================
+// 15. GROUP: ACCOUNT STATUS TESTS
+// ==========================================
+
+func TestLogin_DisabledAccountRejected(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	disabledAt := time.Now().Add(-time.Hour)
+	dummyUser := &user.User{ID: "uuid-123", Email: "off@example.com", Password: string(hashedPwd), DisabledAt: &disabledAt}
+
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Email: dummyUser.Email, Password: "password123"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, user.ErrAccountDisabled, err)
+	m.tokenRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestLogin_PasswordResetRequiredRejected(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Email: "reset@example.com", Password: string(hashedPwd), PasswordResetRequired: true}
+
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Email: dummyUser.Email, Password: "password123"})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, user.ErrPasswordResetRequired, err)
+}
+
+// ==========================================
+// 16. GROUP: LOGIN IDENTIFIER TESTS
+// ==========================================
+
+func TestLogin_ByUsername(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Username: "budi", Email: "budi@example.com", Password: string(hashedPwd)}
+
+	m.repo.On("FindByUsername", mock.Anything, "Budi").Return(dummyUser, nil)
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Identifier: " Budi ", Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "budi", resp.User.Username)
+	m.repo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+}
+
+func TestLogin_IdentifierWithAtUsesEmail(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Username: "foo", Email: "foo@x.com", Password: string(hashedPwd)}
+
+	// Pencocokan huruf besar/kecil dilakukan di database (lower(email) = lower($1))
+	m.repo.On("FindByEmail", mock.Anything, "Foo@X.com").Return(dummyUser, nil)
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Identifier: "Foo@X.com", Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo@x.com", resp.User.Email)
+	m.repo.AssertNotCalled(t, "FindByUsername", mock.Anything, mock.Anything)
+}
+
+func TestLogin_IdentifierTakesPrecedenceOverEmail(t *testing.T) {
+	u, m := setupMocks()
+
+	m.repo.On("FindByUsername", mock.Anything, "budi").Return(nil, nil)
+
+	_, err := u.Login(context.Background(), &user.LoginRequest{Identifier: "budi", Email: "other@example.com", Password: "x"})
+
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+	m.repo.AssertNotCalled(t, "FindByEmail", mock.Anything, mock.Anything)
+}
+
+func TestLogin_UsernameFailuresLockedPerUsername(t *testing.T) {
+	u, m := setupMocks()
+	m.attempts.ExpectedCalls = nil
+
+	m.attempts.On("LockedUntil", mock.Anything, []string{"username:budi"}, mock.Anything).Return(nil, nil)
+	m.attempts.On("RegisterFailure", mock.Anything, "username:budi", mock.Anything, mock.Anything).Return(1, nil)
+	m.repo.On("FindByUsername", mock.Anything, "Budi").Return(nil, nil)
+
+	_, err := u.Login(context.Background(), &user.LoginRequest{Identifier: "Budi", Password: "x"})
+
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+	m.attempts.AssertExpectations(t)
+}
+
+func TestLogin_EmailAndUsernameShareAccountCounter(t *testing.T) {
+	u, m := setupMocks()
+	m.attempts.ExpectedCalls = nil
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Username: "budi", Email: "budi@example.com", Password: string(hashedPwd)}
+
+	// Gagal lewat email maupun username menambah counter yang sama
+	m.attempts.On("LockedUntil", mock.Anything, []string{"user:uuid-123"}, mock.Anything).Return(nil, nil).Twice()
+	m.attempts.On("RegisterFailure", mock.Anything, "user:uuid-123", mock.Anything, mock.Anything).Return(1, nil).Twice()
+	m.repo.On("FindByEmail", mock.Anything, "budi@example.com").Return(dummyUser, nil)
+	m.repo.On("FindByUsername", mock.Anything, "budi").Return(dummyUser, nil)
+
+	_, err := u.Login(context.Background(), &user.LoginRequest{Email: "budi@example.com", Password: "guess"})
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+	_, err = u.Login(context.Background(), &user.LoginRequest{Identifier: "budi", Password: "guess"})
+	assert.Equal(t, user.ErrInvalidCredentials, err)
+
+	m.attempts.AssertExpectations(t)
+}
+
+// ==========================================
+// 17. GROUP: PASSWORD POLICY & HASH UPGRADE TESTS
+// ==========================================
+
+func TestRegister_RejectsCommonPassword(t *testing.T) {
+	u, m := setupMocks()
+
+	_, err := u.Register(context.Background(), &user.RegisterRequest{Username: "budi", Email: "budi@example.com", Password: "Password123"})
+
+	var policyErr *password.PolicyError
+	assert.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, password.RuleCommon, policyErr.Rule)
+	m.repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestRegister_RejectsPasswordContainingUsername(t *testing.T) {
+	u, m := setupMocks()
+
+	_, err := u.Register(context.Background(), &user.RegisterRequest{Username: "budisantoso", Email: "budi@example.com", Password: "xx-BudiSantoso-99"})
+
+	var policyErr *password.PolicyError
+	assert.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, password.RulePersonal, policyErr.Rule)
+	m.repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestChangePassword_RejectsShortPassword(t *testing.T) {
+	u, m := setupMocks()
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("oldpassword"), bcrypt.MinCost)
+	m.repo.On("FindByID", mock.Anything, "uuid-123").Return(&user.User{ID: "uuid-123", Password: string(hashedPwd)}, nil)
+
+	err := u.ChangePassword(context.Background(), "uuid-123", &user.ChangePasswordRequest{OldPassword: "oldpassword", NewPassword: "short"})
+
+	var policyErr *password.PolicyError
+	assert.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, password.RuleMinLength, policyErr.Rule)
+	m.repo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResetPassword_PolicyRejectionKeepsToken(t *testing.T) {
+	u, m := setupMocks()
+
+	token := &user.ActionToken{ID: "token-1", UserID: "uuid-123", Purpose: user.PurposePasswordReset}
+	m.tokenRepo.On("FindActionToken", mock.Anything, user.PurposePasswordReset, mock.Anything).Return(token, nil)
+	m.repo.On("FindByID", mock.Anything, token.UserID).Return(&user.User{ID: token.UserID, Username: "budi", Email: "budi@example.com"}, nil)
+
+	err := u.ResetPassword(context.Background(), &user.ResetPasswordRequest{Token: "plain-token", NewPassword: "qwerty123"})
+
+	var policyErr *password.PolicyError
+	assert.ErrorAs(t, err, &policyErr)
+	// Link reset masih bisa dipakai dengan password yang lebih kuat
+	m.tokenRepo.AssertNotCalled(t, "ConsumeActionToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestLogin_UpgradesBcryptHashToArgon2id(t *testing.T) {
+	_, m := setupMocks()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	hasher, err := password.NewHasher(password.AlgorithmArgon2id, bcrypt.MinCost, password.Argon2Params{
+		Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32,
+	})
+	assert.NoError(t, err)
+	u := user.NewUseCase(m.repo, m.tokenRepo, m.revocations, m.sessions, m.apiTokens, m.mailer, m.attempts, m.categories,
+		jwtkey.NewHMAC("secret_key_testing_123"), hasher, password.DefaultPolicy(), log, validator.New(), m.cfg)
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Email: "old@example.com", Password: string(hashedPwd)}
+
+	var upgraded string
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+	m.repo.On("UpdatePasswordHash", mock.Anything, dummyUser.ID, string(hashedPwd), mock.Anything).Run(func(args mock.Arguments) {
+		upgraded = args.String(3)
+	}).Return(nil)
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Email: dummyUser.Email, Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	assert.True(t, strings.HasPrefix(upgraded, "$argon2id$"))
+	ok, err := hasher.Verify(upgraded, "password123")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+// ==========================================
+// 18. GROUP: ACCESS TOKEN CLAIMS TESTS
+// ==========================================
+
+func TestLogin_AccessTokenCarriesIssuerAndAudience(t *testing.T) {
+	u, m := setupMocks()
+	m.cfg.Set("jwt.issuer", "finance-tracker-app")
+	m.cfg.Set("jwt.audience", "finance-tracker-api")
+
+	hashedPwd, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	dummyUser := &user.User{ID: "uuid-123", Email: "login@example.com", Role: user.RoleUser, Password: string(hashedPwd)}
+	m.repo.On("FindByEmail", mock.Anything, dummyUser.Email).Return(dummyUser, nil)
+	m.tokenRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	resp, err := u.Login(context.Background(), &user.LoginRequest{Email: dummyUser.Email, Password: "password123"})
+	assert.NoError(t, err)
+
+	// Token harus lolos parser dengan aturan yang sama seperti AuthMiddleware
+	var claims middleware.AccessClaims
+	_, err = jwt.NewParser(jwt.WithIssuer("finance-tracker-app"), jwt.WithAudience("finance-tracker-api"), jwt.WithExpirationRequired()).
+		ParseWithClaims(resp.AccessToken, &claims, jwtkey.NewHMAC("secret_key_testing_123").Keyfunc)
+	assert.NoError(t, err)
+	assert.Equal(t, dummyUser.ID, claims.Subject)
+	assert.Equal(t, user.RoleUser, claims.Role)
+	assert.NotEmpty(t, claims.ID)
+	assert.NotEmpty(t, claims.SessionID)
+	if assert.NotNil(t, claims.NotBefore) {
+		assert.Equal(t, claims.IssuedAt.Unix(), claims.NotBefore.Unix())
+	}
+}